@@ -25,13 +25,20 @@ var (
 	ErrInvalidJWT = errors.New("the JWT is invalid")
 )
 
+// ClaimPaths are the dot separated paths in the JWT claims to find the principal's roles and groups. For example, the
+// Keycloak realm roles are found at "realm_access.roles".
+type ClaimPaths struct {
+	Groups []string
+	Roles  []string
+}
+
 // JWTHandler is a function signature that takes in a Base64 encoded JWT and returns the auth principal from it.
 type JWTHandler func(jwtB64 string) (principal *models.Principal, err error)
 
 // HandleJWT creates a JWT auth handler via a closure.
 //
 // TODO Add logging. Error is returned to user. Log error. Generic thing back to user.
-func HandleJWT(ctx context.Context, client *http.Client, jwksURL string, logger *zap.SugaredLogger, sleep time.Duration) (authHandler JWTHandler, err error) {
+func HandleJWT(ctx context.Context, client *http.Client, claimPaths ClaimPaths, jwksURL string, logger *zap.SugaredLogger, sleep time.Duration) (authHandler JWTHandler, err error) {
 
 	// Try to get the JWKS until the context expires.
 	var ks jwks.Keystore
@@ -90,6 +97,47 @@ func HandleJWT(ctx context.Context, client *http.Client, jwksURL string, logger
 			return nil, fmt.Errorf("failed to unmarshal claims back from JSON: %w: %v", ErrClaims, err)
 		}
 
+		// Only trust the roles and groups found at the configured claim paths.
+		principal.Groups = claimStrings(claims, claimPaths.Groups)
+		principal.Roles = claimStrings(claims, claimPaths.Roles)
+
 		return principal, nil
 	}, nil
 }
+
+// claimStrings gathers all the strings found at the given dot separated paths in the JWT claims. Claims that are not
+// found or are not a string or an array of strings are ignored.
+func claimStrings(claims jwt.MapClaims, paths []string) (values []string) {
+
+	// Create the return slice.
+	values = make([]string, 0)
+
+	// Iterate through the given paths.
+	for _, path := range paths {
+
+		// Follow the path through the nested claims.
+		var claim interface{} = map[string]interface{}(claims)
+		for _, key := range strings.Split(path, ".") {
+			object, ok := claim.(map[string]interface{})
+			if !ok {
+				claim = nil
+				break
+			}
+			claim = object[key]
+		}
+
+		// Add the claim's strings to the return slice.
+		switch claim := claim.(type) {
+		case string:
+			values = append(values, claim)
+		case []interface{}:
+			for _, item := range claim {
+				if str, ok := item.(string); ok {
+					values = append(values, str)
+				}
+			}
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/runtime/middleware"
+
+	"github.com/MicahParks/terseurl/models"
+)
+
+// Policy describes which principals are allowed to perform which operations. Administrators are allowed to perform
+// every operation on every shortened URL.
+type Policy struct {
+
+	// AdminGroups are the groups that make a principal an administrator.
+	AdminGroups []string
+
+	// AdminRoles are the roles that make a principal an administrator.
+	AdminRoles []string
+
+	// Operations maps swagger operation IDs to the roles or groups that are allowed to perform them. Having any one of
+	// them is enough. An operation with no roles or groups is only allowed for administrators. Operations not present
+	// are allowed for every authenticated principal.
+	Operations map[string][]string
+}
+
+// Admin determines if the given principal is an administrator.
+func (p Policy) Admin(principal *models.Principal) (admin bool) {
+
+	// Without a principal, authentication is turned off.
+	if principal == nil {
+		return true
+	}
+
+	return intersects(principal.Roles, p.AdminRoles) || intersects(principal.Groups, p.AdminGroups)
+}
+
+// Authorize implements the runtime.Authorizer interface. It confirms the principal is allowed to perform the requested
+// operation.
+func (p Policy) Authorize(request *http.Request, inter interface{}) (err error) {
+
+	// Get the principal.
+	principal, _ := inter.(*models.Principal)
+
+	// Administrators can perform any operation.
+	if p.Admin(principal) {
+		return nil
+	}
+
+	// Get the operation being requested.
+	route := middleware.MatchedRouteFrom(request)
+	if route == nil || route.Operation == nil {
+		return nil
+	}
+
+	// Check to see if the operation is restricted.
+	allowed, ok := p.Operations[route.Operation.ID]
+	if !ok {
+		return nil
+	}
+
+	// Confirm the principal has one of the allowed roles or groups.
+	if intersects(principal.Roles, allowed) || intersects(principal.Groups, allowed) {
+		return nil
+	}
+
+	return errors.New(http.StatusForbidden, "not authorized to perform operation %s", route.Operation.ID)
+}
+
+// intersects determines if the two slices of strings have at least one element in common.
+func intersects(a, b []string) bool {
+	for _, strA := range a {
+		for _, strB := range b {
+			if strA == strB {
+				return true
+			}
+		}
+	}
+	return false
+}
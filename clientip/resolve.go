@@ -0,0 +1,144 @@
+package clientip
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Resolver determines the IP address of the client that made an HTTP request. The forwarding headers are only trusted
+// when the request came from a trusted proxy, so clients cannot spoof their IP address.
+type Resolver struct {
+	trusted []*net.IPNet
+}
+
+// NewResolver creates a new Resolver that trusts the forwarding headers of the given proxies. Each proxy is an IP
+// address or CIDR block. If there are no proxies, the forwarding headers are never trusted.
+func NewResolver(trustedProxies []string) (resolver Resolver, err error) {
+
+	// Parse the trusted proxies into networks.
+	for _, proxy := range trustedProxies {
+		var ipNet *net.IPNet
+		if ipNet, err = parseIPNet(proxy); err != nil {
+			return Resolver{}, err
+		}
+		resolver.trusted = append(resolver.trusted, ipNet)
+	}
+
+	return resolver, nil
+}
+
+// ClientIP determines the IP address of the client that made the request. If the request came from a trusted proxy, the
+// RFC 7239 Forwarded, X-Forwarded-For, and X-Real-IP headers are checked in that order. Chains of proxies are followed
+// from the nearest proxy until an address that is not a trusted proxy is found. Otherwise, the remote address of the
+// request is used. The port is never included.
+func (r Resolver) ClientIP(request *http.Request) (ip string) {
+
+	// Get the address of the direct peer.
+	ip = host(request.RemoteAddr)
+	if !r.isTrusted(ip) {
+		return ip
+	}
+
+	// Follow the chain of proxies in the first forwarding header present.
+	if values := request.Header.Values("Forwarded"); len(values) != 0 {
+		return r.followChain(ip, forwardedFor(values))
+	}
+	if values := request.Header.Values("X-Forwarded-For"); len(values) != 0 {
+		return r.followChain(ip, splitList(values))
+	}
+
+	// Use the X-Real-IP header, which does not have a chain.
+	if realIP := host(strings.TrimSpace(request.Header.Get("X-Real-IP"))); net.ParseIP(realIP) != nil {
+		return realIP
+	}
+
+	return ip
+}
+
+// followChain follows the chain of proxies from the last address, which the trusted proxy at the remote address added.
+// The first address that is not a trusted proxy is returned. If an address is not valid, the last valid address is
+// returned, because the addresses before it cannot be trusted.
+func (r Resolver) followChain(remote string, chain []string) (ip string) {
+	ip = remote
+	for i := len(chain) - 1; i >= 0; i-- {
+		address := host(chain[i])
+		if net.ParseIP(address) == nil {
+			break
+		}
+		ip = address
+		if !r.isTrusted(ip) {
+			break
+		}
+	}
+	return ip
+}
+
+// isTrusted determines if the IP address belongs to a trusted proxy.
+func (r Resolver) isTrusted(address string) (trusted bool) {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return false
+	}
+	for _, ipNet := range r.trusted {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// forwardedFor returns the for parameters of the elements in the given RFC 7239 Forwarded header values, in order.
+// Elements without a for parameter are given an empty address, which ends a chain.
+func forwardedFor(values []string) (addresses []string) {
+	for _, element := range splitList(values) {
+		var address string
+		for _, pair := range strings.Split(element, ";") {
+			parts := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+			if len(parts) == 2 && strings.EqualFold(parts[0], "for") {
+				address = strings.Trim(parts[1], `"`)
+			}
+		}
+		addresses = append(addresses, address)
+	}
+	return addresses
+}
+
+// host removes the port and IPv6 brackets from the given address, if present.
+func host(address string) (h string) {
+	if h, _, err := net.SplitHostPort(address); err == nil {
+		return h
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(address, "["), "]")
+}
+
+// parseIPNet parses an IP address or CIDR block into a network. An IP address is parsed as a network that only contains
+// itself.
+func parseIPNet(s string) (ipNet *net.IPNet, err error) {
+
+	// Parse a CIDR block.
+	if strings.Contains(s, "/") {
+		_, ipNet, err = net.ParseCIDR(s)
+		return ipNet, err
+	}
+
+	// Parse an IP address.
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, &net.ParseError{Type: "IP address", Text: s}
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}, nil
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+}
+
+// splitList splits the comma separated values of a header into trimmed elements, in order.
+func splitList(values []string) (elements []string) {
+	for _, value := range values {
+		for _, element := range strings.Split(value, ",") {
+			elements = append(elements, strings.TrimSpace(element))
+		}
+	}
+	return elements
+}
@@ -0,0 +1,108 @@
+package clientip
+
+import (
+	"net/http"
+	"testing"
+)
+
+// TestResolver_ClientIP confirms the forwarding headers are only used when the request came from a trusted proxy, and
+// chains of proxies are followed until an address that is not trusted.
+func TestResolver_ClientIP(t *testing.T) {
+	resolver, err := NewResolver([]string{"10.0.0.0/8", "192.168.1.1", "fd00::/8"})
+	if err != nil {
+		t.Fatalf("failed to create resolver: %s", err.Error())
+	}
+
+	testCases := []struct {
+		name       string
+		untrusted  bool
+		remoteAddr string
+		headers    map[string][]string
+		expected   string
+	}{
+		{name: "remote", remoteAddr: "1.2.3.4:5678", expected: "1.2.3.4"},
+		{name: "remote without port", remoteAddr: "1.2.3.4", expected: "1.2.3.4"},
+		{name: "remote IPv6", remoteAddr: "[2001:db8::1]:5678", expected: "2001:db8::1"},
+		{name: "untrusted X-Forwarded-For", remoteAddr: "1.2.3.4:5678", headers: map[string][]string{"X-Forwarded-For": {"5.6.7.8"}}, expected: "1.2.3.4"},
+		{name: "untrusted Forwarded", remoteAddr: "1.2.3.4:5678", headers: map[string][]string{"Forwarded": {"for=5.6.7.8"}}, expected: "1.2.3.4"},
+		{name: "untrusted X-Real-IP", remoteAddr: "1.2.3.4:5678", headers: map[string][]string{"X-Real-IP": {"5.6.7.8"}}, expected: "1.2.3.4"},
+		{name: "no trusted proxies", untrusted: true, remoteAddr: "10.0.0.1:5678", headers: map[string][]string{"X-Forwarded-For": {"5.6.7.8"}}, expected: "10.0.0.1"},
+		{name: "trusted without headers", remoteAddr: "10.0.0.1:5678", expected: "10.0.0.1"},
+		{name: "trusted single address", remoteAddr: "192.168.1.1:5678", headers: map[string][]string{"X-Forwarded-For": {"5.6.7.8"}}, expected: "5.6.7.8"},
+		{name: "untrusted neighbor of single address", remoteAddr: "192.168.1.2:5678", headers: map[string][]string{"X-Forwarded-For": {"5.6.7.8"}}, expected: "192.168.1.2"},
+		{name: "trusted IPv6", remoteAddr: "[fd00::1]:443", headers: map[string][]string{"X-Forwarded-For": {"5.6.7.8"}}, expected: "5.6.7.8"},
+		{name: "X-Forwarded-For", remoteAddr: "10.0.0.1:5678", headers: map[string][]string{"X-Forwarded-For": {"5.6.7.8"}}, expected: "5.6.7.8"},
+		{name: "X-Forwarded-For chain", remoteAddr: "10.0.0.1:5678", headers: map[string][]string{"X-Forwarded-For": {"5.6.7.8, 10.0.0.2"}}, expected: "5.6.7.8"},
+		{name: "X-Forwarded-For spoofed", remoteAddr: "10.0.0.1:5678", headers: map[string][]string{"X-Forwarded-For": {"6.6.6.6, 5.6.7.8"}}, expected: "5.6.7.8"},
+		{name: "X-Forwarded-For all trusted", remoteAddr: "10.0.0.1:5678", headers: map[string][]string{"X-Forwarded-For": {"10.0.0.3, 10.0.0.2"}}, expected: "10.0.0.3"},
+		{name: "X-Forwarded-For multiple headers", remoteAddr: "10.0.0.1:5678", headers: map[string][]string{"X-Forwarded-For": {"5.6.7.8", "10.0.0.2"}}, expected: "5.6.7.8"},
+		{name: "X-Forwarded-For with port", remoteAddr: "10.0.0.1:5678", headers: map[string][]string{"X-Forwarded-For": {"5.6.7.8:1234"}}, expected: "5.6.7.8"},
+		{name: "X-Forwarded-For IPv6", remoteAddr: "10.0.0.1:5678", headers: map[string][]string{"X-Forwarded-For": {"2001:db8::1"}}, expected: "2001:db8::1"},
+		{name: "X-Forwarded-For invalid nearest", remoteAddr: "10.0.0.1:5678", headers: map[string][]string{"X-Forwarded-For": {"5.6.7.8, garbage"}}, expected: "10.0.0.1"},
+		{name: "X-Forwarded-For invalid farther", remoteAddr: "10.0.0.1:5678", headers: map[string][]string{"X-Forwarded-For": {"garbage, 10.0.0.2"}}, expected: "10.0.0.2"},
+		{name: "X-Forwarded-For empty", remoteAddr: "10.0.0.1:5678", headers: map[string][]string{"X-Forwarded-For": {""}}, expected: "10.0.0.1"},
+		{name: "Forwarded", remoteAddr: "10.0.0.1:5678", headers: map[string][]string{"Forwarded": {"for=5.6.7.8"}}, expected: "5.6.7.8"},
+		{name: "Forwarded case insensitive", remoteAddr: "10.0.0.1:5678", headers: map[string][]string{"Forwarded": {"For=5.6.7.8"}}, expected: "5.6.7.8"},
+		{name: "Forwarded parameters", remoteAddr: "10.0.0.1:5678", headers: map[string][]string{"Forwarded": {"proto=https;for=5.6.7.8;by=10.0.0.1"}}, expected: "5.6.7.8"},
+		{name: "Forwarded quoted IPv6 with port", remoteAddr: "10.0.0.1:5678", headers: map[string][]string{"Forwarded": {`for="[2001:db8:cafe::17]:4711"`}}, expected: "2001:db8:cafe::17"},
+		{name: "Forwarded chain", remoteAddr: "10.0.0.1:5678", headers: map[string][]string{"Forwarded": {"for=5.6.7.8, for=10.0.0.2"}}, expected: "5.6.7.8"},
+		{name: "Forwarded spoofed", remoteAddr: "10.0.0.1:5678", headers: map[string][]string{"Forwarded": {"for=6.6.6.6, for=5.6.7.8"}}, expected: "5.6.7.8"},
+		{name: "Forwarded without for", remoteAddr: "10.0.0.1:5678", headers: map[string][]string{"Forwarded": {"proto=https"}}, expected: "10.0.0.1"},
+		{name: "Forwarded obfuscated", remoteAddr: "10.0.0.1:5678", headers: map[string][]string{"Forwarded": {"for=_hidden, for=10.0.0.2"}}, expected: "10.0.0.2"},
+		{name: "Forwarded before X-Forwarded-For", remoteAddr: "10.0.0.1:5678", headers: map[string][]string{"Forwarded": {"for=5.6.7.8"}, "X-Forwarded-For": {"6.6.6.6"}}, expected: "5.6.7.8"},
+		{name: "X-Forwarded-For before X-Real-IP", remoteAddr: "10.0.0.1:5678", headers: map[string][]string{"X-Forwarded-For": {"5.6.7.8"}, "X-Real-IP": {"6.6.6.6"}}, expected: "5.6.7.8"},
+		{name: "X-Real-IP", remoteAddr: "10.0.0.1:5678", headers: map[string][]string{"X-Real-IP": {"5.6.7.8"}}, expected: "5.6.7.8"},
+		{name: "X-Real-IP with port", remoteAddr: "10.0.0.1:5678", headers: map[string][]string{"X-Real-IP": {" [2001:db8::1]:80 "}}, expected: "2001:db8::1"},
+		{name: "X-Real-IP invalid", remoteAddr: "10.0.0.1:5678", headers: map[string][]string{"X-Real-IP": {"garbage"}}, expected: "10.0.0.1"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := resolver
+			if testCase.untrusted {
+				r = Resolver{}
+			}
+			request := &http.Request{
+				Header:     http.Header{},
+				RemoteAddr: testCase.remoteAddr,
+			}
+			for key, values := range testCase.headers {
+				for _, value := range values {
+					request.Header.Add(key, value)
+				}
+			}
+			if ip := r.ClientIP(request); ip != testCase.expected {
+				t.Fatalf("expected %q, got %q", testCase.expected, ip)
+			}
+		})
+	}
+}
+
+// TestNewResolver confirms trusted proxies must be IP addresses or CIDR blocks.
+func TestNewResolver(t *testing.T) {
+	testCases := []struct {
+		name    string
+		proxies []string
+		valid   bool
+	}{
+		{name: "none", valid: true},
+		{name: "IPv4", proxies: []string{"10.0.0.1"}, valid: true},
+		{name: "IPv6", proxies: []string{"::1"}, valid: true},
+		{name: "CIDR", proxies: []string{"10.0.0.0/8", "fd00::/8"}, valid: true},
+		{name: "hostname", proxies: []string{"proxy.example.com"}},
+		{name: "invalid CIDR", proxies: []string{"10.0.0.0/33"}},
+		{name: "one invalid", proxies: []string{"10.0.0.1", "garbage"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := NewResolver(testCase.proxies)
+			if testCase.valid && err != nil {
+				t.Fatalf("expected no error, got %s", err.Error())
+			}
+			if !testCase.valid && err == nil {
+				t.Fatalf("expected an error")
+			}
+		})
+	}
+}
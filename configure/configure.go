@@ -2,19 +2,31 @@ package configure
 
 import (
 	"context"
+	"encoding/json"
 	"html/template"
 	"io/ioutil"
 
 	"github.com/MicahParks/ctxerrgroup"
-	"github.com/teris-io/shortid"
 	"go.uber.org/zap"
 
 	"github.com/MicahParks/terseurl"
+	"github.com/MicahParks/terseurl/auth"
+	"github.com/MicahParks/terseurl/clientip"
+	"github.com/MicahParks/terseurl/generate"
+	"github.com/MicahParks/terseurl/geoip"
+	"github.com/MicahParks/terseurl/jobs"
+	"github.com/MicahParks/terseurl/privacy"
 	"github.com/MicahParks/terseurl/storage"
 )
 
 const (
 
+	// configPathAuthorizationStore is the location to find the AuthorizationStore JSON configuration file.
+	configPathAuthorizationStore = "authorizationStore.json"
+
+	// configPathGenerator is the location to find the Generator JSON configuration file.
+	configPathGenerator = "generator.json"
+
 	// configPathTerseStore is the location to find the TerseStore JSON configuration file.
 	configPathTerseStore = "terseStore.json"
 
@@ -27,16 +39,23 @@ const (
 
 // Configuration is the Go structure that contains all needed configurations gathered on startup.
 type Configuration struct {
-	ErrChan         chan error
-	Logger          *zap.SugaredLogger
-	InvalidPaths    []string
-	JWKSURL         string
-	Prefix          string
-	ShortID         *shortid.Shortid
-	ShortIDParanoid bool
-	StoreManager    storage.StoreManager
-	Template        *template.Template
-	UseAuth         bool
+	ClaimPaths         auth.ClaimPaths
+	ClientIP           clientip.Resolver
+	Dedupe             bool
+	ErrChan            chan error
+	Generator          generate.Paranoid
+	GonePage           []byte
+	Logger             *zap.SugaredLogger
+	InvalidPaths       []string
+	JWKSURL            string
+	Locator            *geoip.Locator
+	Policy             auth.Policy
+	Prefix             string
+	PreviewBots        bool
+	ShortenedMaxLength uint
+	StoreManager       storage.StoreManager
+	Template           *template.Template
+	UseAuth            bool
 }
 
 // Configure gathers all startup configurations, formats them, and returns them as a Go struct.
@@ -60,11 +79,56 @@ func Configure() (config Configuration, err error) {
 		return Configuration{}, err // Should be unreachable.
 	}
 
+	// Create the client IP resolver. It only trusts the forwarding headers of the trusted proxies.
+	if config.ClientIP, err = clientip.NewResolver(rawConfig.TrustedProxies); err != nil {
+		logger.Fatalw("Failed to parse trusted proxies.",
+			"error", err.Error(),
+		)
+		return Configuration{}, err // Should be unreachable.
+	}
+
+	// Open the GeoIP databases used to locate visits, if given.
+	if len(rawConfig.GeoIPPaths) != 0 {
+		if config.Locator, err = geoip.NewLocator(rawConfig.GeoIPPaths); err != nil {
+			logger.Fatalw("Failed to open GeoIP databases.",
+				"paths", rawConfig.GeoIPPaths,
+				"error", err.Error(),
+			)
+			return Configuration{}, err // Should be unreachable.
+		}
+		logger.Infow("GeoIP databases opened.",
+			"paths", rawConfig.GeoIPPaths,
+		)
+	}
+
+	// Locate visits, if GeoIP databases are configured. This is done while the visit is stored, so the redirect does not
+	// wait for it, unless targeting rules need the country. Visits must be located before personal data are removed.
+	var visitProcess []storage.VisitProcessor
+	if config.Locator != nil {
+		visitProcess = append(visitProcess, config.Locator.Visit)
+	}
+
+	// Remove personal data from visits, if privacy mode is on.
+	if rawConfig.PrivacyMode {
+		visitProcess = append(visitProcess, privacy.NewAnonymizer(rawConfig.PrivacyHeaders, rawConfig.PrivacySaltRotation).Visit)
+		logger.Infow("Privacy mode is on.",
+			"headers", rawConfig.PrivacyHeaders,
+			"saltRotation", rawConfig.PrivacySaltRotation.String(),
+		)
+	}
+
 	// Create the HTML template.
 	if config.Template, err = createTemplate(rawConfig.TemplatePath, ""); err != nil {
 		return Configuration{}, err
 	}
 
+	// Read the gone page for expired shortened URLs, if given.
+	if rawConfig.GonePagePath != "" {
+		if config.GonePage, err = ioutil.ReadFile(rawConfig.GonePagePath); err != nil {
+			return Configuration{}, err
+		}
+	}
+
 	// Set the database timeout.
 	defaultTimeout = rawConfig.DefaultTimeout
 
@@ -81,22 +145,62 @@ func Configure() (config Configuration, err error) {
 		)
 	})
 
-	// Create the Terse, Visits, and Summary data stores.
-	if err = createStores(&config, group, logger, rawConfig); err != nil {
+	// Create the authorization policy. It is needed by the StoreManager.
+	config.Policy = auth.Policy{
+		AdminGroups: rawConfig.AdminGroups,
+		AdminRoles:  rawConfig.AdminRoles,
+		Operations:  rawConfig.OperationPolicy,
+	}
+
+	// Create the Authorization, Terse, Visits, and Summary data stores.
+	if err = createStores(&config, group, logger, rawConfig, visitProcess); err != nil {
 		logger.Fatalw("Failed to create data store.",
 			"error", err.Error(),
 		)
 	}
 
-	// Create the short ID generator.
-	if config.ShortID, err = shortid.New(1, shortid.DefaultABC, rawConfig.ShortIDSeed); err != nil { // TODO Configure worker count?
+	// Periodically purge expired shortened URLs on the worker pool, if there is an interval.
+	if rawConfig.ReaperInterval != 0 {
+		jobs.NewReaper(rawConfig.ReaperArchiveDir, DefaultCtx, group, rawConfig.ReaperInterval, logger.Named("Reaper"), config.StoreManager, rawConfig.ReaperOrphans).Start()
+	}
+
+	// Periodically roll up and prune visits older than the retention on the worker pool, if there is a retention.
+	if rawConfig.VisitsRetention != 0 {
+		jobs.NewPruner(DefaultCtx, group, rawConfig.PrunerInterval, logger.Named("Pruner"), config.StoreManager, rawConfig.VisitsRetention).Start()
+	}
+
+	// Get the Generator configuration.
+	var generatorConfig json.RawMessage
+	if generatorConfig, err = readStorageConfig(rawConfig.GeneratorJSON, logger, configPathGenerator); err != nil {
 		return Configuration{}, err
 	}
 
+	// Create the shortened URL generator.
+	generator, generatorType, err := generate.NewGenerator(generatorConfig, rawConfig.ShortIDSeed)
+	if err != nil {
+		logger.Fatalw("Failed to create Generator.",
+			"type", generatorType,
+			"error", err.Error(),
+		)
+		return Configuration{}, err // Should be unreachable.
+	}
+	logger.Infow("Created Generator.",
+		"type", generatorType,
+	)
+
+	// Wrap the generator so it can check for collisions in paranoid mode.
+	config.Generator = generate.NewParanoid(generator, logger.Named("Generator"), config.StoreManager, rawConfig.ShortIDMaxAttempts, rawConfig.ShortIDParanoid)
+
 	// Copy over any other needed raw config info.
+	config.ClaimPaths = auth.ClaimPaths{
+		Groups: rawConfig.GroupClaims,
+		Roles:  rawConfig.RoleClaims,
+	}
+	config.Dedupe = rawConfig.Dedupe
 	config.InvalidPaths = rawConfig.InvalidPaths
-	config.ShortIDParanoid = rawConfig.ShortIDParanoid
+	config.ShortenedMaxLength = rawConfig.ShortenedMaxLength
 	config.Prefix = rawConfig.Prefix
+	config.PreviewBots = rawConfig.PreviewBots
 	config.JWKSURL = rawConfig.JWKSURL
 	config.UseAuth = rawConfig.UseAuth
 
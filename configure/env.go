@@ -1,6 +1,7 @@
 package configure
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -14,9 +15,43 @@ const (
 	// booleanTrue is the string value that evironment variables that represents booleans should have.
 	booleanTrue = "true"
 
+	// defaultAdminRoles is the default comma separated roles that make a principal an administrator.
+	defaultAdminRoles = "admin"
+
+	// defaultGroupClaims is the default comma separated claim paths to find a principal's groups in a JWT.
+	defaultGroupClaims = "groups"
+
+	// defaultOperationPolicy is the default JSON object mapping operation IDs to the roles or groups allowed to perform
+	// them. An empty array means the operation is only allowed for administrators.
+	defaultOperationPolicy = `{"import":[]}`
+
+	// defaultReaperInterval is the default amount of time between purges of expired shortened URLs.
+	defaultReaperInterval = time.Hour
+
+	// defaultPrunerInterval is the default amount of time between prunes of visits older than the retention.
+	defaultPrunerInterval = time.Hour
+
 	// defaultPrefix is the default HTTP prefix for all shortened URLs.
 	defaultPrefix = "https://terseurl.com/"
 
+	// defaultPrivacyHeaders is the default comma separated headers of visits that are kept in privacy mode.
+	defaultPrivacyHeaders = "Referer, User-Agent"
+
+	// defaultPrivacySaltRotation is the default amount of time between rotations of the salt used to hash the IP
+	// addresses of visits in privacy mode. Every rotation inflates the unique visitor estimates.
+	defaultPrivacySaltRotation = 24 * time.Hour
+
+	// defaultRoleClaims is the default comma separated claim paths to find a principal's roles in a JWT. This matches
+	// Keycloak realm roles.
+	defaultRoleClaims = "realm_access.roles"
+
+	// defaultShortenedMaxLength is the default maximum number of characters a client given shortened URL can have.
+	defaultShortenedMaxLength = 64
+
+	// defaultShortIDMaxAttempts is the default number of times to try generating an unused random shortened URL in
+	// paranoid mode.
+	defaultShortIDMaxAttempts = 10
+
 	// defaultWorkerCount is the default amount of workers to have in the ctxerrgroup.
 	defaultWorkerCount = 4
 )
@@ -27,7 +62,7 @@ var (
 	// provided.
 	ErrCantBeZeroOrNegative = errors.New("integer cannot be negative")
 
-	// alwaysInvalidPaths
+	// alwaysInvalidPaths are the paths that are used by the service and can never be assigned to a shortened URL.
 	alwaysInvalidPaths = []string{"api", "docs", "frontend", "favicon.ico", "swagger.json", "robots.txt"}
 
 	// defaultTimeout is the default timeout for any incoming (from clients) and outgoing (to databases) requests.
@@ -36,19 +71,65 @@ var (
 
 // configuration holds all the necessary information for
 type configuration struct {
-	DefaultTimeout   time.Duration
-	InvalidPaths     []string
-	JWKSURL          string
-	Prefix           string
-	ShortIDParanoid  bool
-	ShortIDSeed      uint64
-	TemplatePath     string
-	UseAuth          bool
-	StaticFSDirName  string
-	SummaryStoreJSON string
-	TerseStoreJSON   string
-	VisitsStoreJSON  string
-	WorkerCount      uint
+	AdminGroups            []string
+	AdminRoles             []string
+	AuthorizationStoreJSON string
+	CountBots              bool
+	Dedupe                 bool
+	DefaultTimeout         time.Duration
+	GeneratorJSON          string
+	GeoIPPaths             []string
+	GonePagePath           string
+	GroupClaims            []string
+	InvalidPaths           []string
+	JWKSURL                string
+	OperationPolicy        map[string][]string
+	Prefix                 string
+	PreviewBots            bool
+	PrivacyHeaders         []string
+	PrivacyMode            bool
+	PrivacySaltRotation    time.Duration
+	PrunerInterval         time.Duration
+	ReaperArchiveDir       string
+	ReaperInterval         time.Duration
+	ReaperOrphans          bool
+	RoleClaims             []string
+	ShortenedMaxLength     uint
+	ShortIDMaxAttempts     uint
+	ShortIDParanoid        bool
+	ShortIDSeed            uint64
+	TemplatePath           string
+	UseAuth                bool
+	StaticFSDirName        string
+	SummaryStoreJSON       string
+	TerseStoreJSON         string
+	TrustedProxies         []string
+	VisitsRetention        time.Duration
+	VisitsStoreJSON        string
+	WorkerCount            uint
+}
+
+// commaSeparatedParse parses a comma separated string into a slice of strings. Whitespace prefixes and suffixes are
+// trimmed and empty elements are dropped. If the string is empty, the default is parsed instead.
+func commaSeparatedParse(s, defaultS string) (elements []string) {
+
+	// If not provided, use the default.
+	if s == "" {
+		s = defaultS
+	}
+
+	// Create the return slice.
+	elements = make([]string, 0)
+
+	// Iterate through the split string and append it to the slice.
+	for _, element := range strings.Split(s, ",") {
+		element = strings.TrimSpace(element)
+		if element != "" {
+			elements = append(elements, element)
+		}
+	}
+
+	return elements
 }
 
 // invalidPathsParse parses a comma separated string into a slice of strings. It adds in paths that are always invalid
@@ -58,15 +139,17 @@ func invalidPathsParse(s string) (invalidPaths []string) {
 	// Create the invalid paths slice.
 	invalidPaths = make([]string, 0)
 
-	// Iterate through the split string and append it to the slice.
+	// Iterate through the split string and append it to the slice. Empty paths are dropped.
 	for _, path := range strings.Split(s, ",") {
 		path = strings.TrimSpace(path)
-		invalidPaths = append(invalidPaths, path)
+		if path != "" {
+			invalidPaths = append(invalidPaths, path)
+		}
 	}
 
 	// Make sure all the always invalid paths are in the slice.
 	var have bool
-	for _, alwaysInvalid := range alwaysInvalidPaths { // TODO Validate.
+	for _, alwaysInvalid := range alwaysInvalidPaths {
 		have = false
 		for _, path := range invalidPaths {
 			if alwaysInvalid == path {
@@ -94,11 +177,44 @@ func readEnvVars() (config *configuration, err error) {
 		return nil, fmt.Errorf("%w: %s", err, incomingRequestTimeout)
 	}
 
+	// Transform the reaper interval to seconds. An interval of 0 turns the reaper off.
+	reaperInterval := os.Getenv("REAPER_INTERVAL")
+	if config.ReaperInterval, err = stringToSeconds(reaperInterval, defaultReaperInterval); err != nil {
+		return nil, fmt.Errorf("%w: %s", err, reaperInterval)
+	}
+
+	// Transform the privacy mode salt rotation to seconds. A rotation of 0 would rotate the salt for every visit.
+	privacySaltRotation := os.Getenv("PRIVACY_SALT_ROTATION")
+	if config.PrivacySaltRotation, err = stringToSeconds(privacySaltRotation, defaultPrivacySaltRotation); err != nil {
+		return nil, fmt.Errorf("%w: %s", err, privacySaltRotation)
+	}
+	if config.PrivacySaltRotation == 0 {
+		return nil, fmt.Errorf("%w: PRIVACY_SALT_ROTATION", ErrCantBeZeroOrNegative)
+	}
+
+	// Transform the pruner interval and visits retention to seconds. An empty retention keeps visits forever.
+	prunerInterval := os.Getenv("PRUNER_INTERVAL")
+	if config.PrunerInterval, err = stringToSeconds(prunerInterval, defaultPrunerInterval); err != nil {
+		return nil, fmt.Errorf("%w: %s", err, prunerInterval)
+	}
+	visitsRetention := os.Getenv("VISITS_RETENTION")
+	if config.VisitsRetention, err = stringToSeconds(visitsRetention, 0); err != nil {
+		return nil, fmt.Errorf("%w: %s", err, visitsRetention)
+	}
+
 	// Transform the required value environment variables into unsigned integers.
 	workerCount := os.Getenv("WORKER_COUNT")
 	if config.WorkerCount, err = stringToUint(workerCount, defaultWorkerCount); err != nil {
 		return nil, fmt.Errorf("%w: %s", err, workerCount)
 	}
+	shortIDMaxAttempts := os.Getenv("SHORTID_MAX_ATTEMPTS")
+	if config.ShortIDMaxAttempts, err = stringToUint(shortIDMaxAttempts, defaultShortIDMaxAttempts); err != nil {
+		return nil, fmt.Errorf("%w: %s", err, shortIDMaxAttempts)
+	}
+	shortenedMaxLength := os.Getenv("SHORTENED_MAX_LENGTH")
+	if config.ShortenedMaxLength, err = stringToUint(shortenedMaxLength, defaultShortenedMaxLength); err != nil {
+		return nil, fmt.Errorf("%w: %s", err, shortenedMaxLength)
+	}
 
 	// Transform the short ID seed into a uint64, if given.
 	shortIDSeed := os.Getenv("SHORTID_SEED")
@@ -109,9 +225,30 @@ func readEnvVars() (config *configuration, err error) {
 	}
 
 	// Transform the required environment variables to slices.
+	config.AdminGroups = commaSeparatedParse(os.Getenv("ADMIN_GROUPS"), "")
+	config.AdminRoles = commaSeparatedParse(os.Getenv("ADMIN_ROLES"), defaultAdminRoles)
+	config.GeoIPPaths = commaSeparatedParse(os.Getenv("GEOIP_PATHS"), "")
+	config.GroupClaims = commaSeparatedParse(os.Getenv("GROUP_CLAIMS"), defaultGroupClaims)
 	config.InvalidPaths = invalidPathsParse(os.Getenv("INVALID_PATHS"))
+	config.PrivacyHeaders = commaSeparatedParse(os.Getenv("PRIVACY_HEADERS"), defaultPrivacyHeaders)
+	config.RoleClaims = commaSeparatedParse(os.Getenv("ROLE_CLAIMS"), defaultRoleClaims)
+	config.TrustedProxies = commaSeparatedParse(os.Getenv("TRUSTED_PROXIES"), "")
+
+	// Transform the operation policy JSON into a Go map.
+	operationPolicy := os.Getenv("OPERATION_POLICY")
+	if operationPolicy == "" {
+		operationPolicy = defaultOperationPolicy
+	}
+	if err = json.Unmarshal([]byte(operationPolicy), &config.OperationPolicy); err != nil {
+		return nil, fmt.Errorf("could not parse operation policy: %w", err)
+	}
 
 	// Assign the boolean value configurations.
+	config.CountBots = os.Getenv("COUNT_BOTS") == booleanTrue
+	config.Dedupe = os.Getenv("DEDUPE") == booleanTrue
+	config.PreviewBots = os.Getenv("PREVIEW_BOTS") == booleanTrue
+	config.PrivacyMode = os.Getenv("PRIVACY_MODE") == booleanTrue
+	config.ReaperOrphans = os.Getenv("REAPER_ORPHANS") == booleanTrue
 	config.ShortIDParanoid = os.Getenv("SHORTID_PARANOID") == booleanTrue
 	config.UseAuth = os.Getenv("USE_AUTH") == booleanTrue
 
@@ -120,7 +257,11 @@ func readEnvVars() (config *configuration, err error) {
 	if config.Prefix == "" {
 		config.Prefix = defaultPrefix
 	}
+	config.AuthorizationStoreJSON = os.Getenv("AUTHORIZATION_STORE_JSON")
+	config.GeneratorJSON = os.Getenv("GENERATOR_JSON")
+	config.GonePagePath = os.Getenv("GONE_PAGE_PATH")
 	config.JWKSURL = os.Getenv("JWKS_URL")
+	config.ReaperArchiveDir = os.Getenv("REAPER_ARCHIVE_DIR")
 	config.StaticFSDirName = os.Getenv("FRONTEND_STATIC_DIR")
 	config.SummaryStoreJSON = os.Getenv("SUMMARY_STORE_JSON")
 	config.TemplatePath = os.Getenv("TEMPLATE_PATH")
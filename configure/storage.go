@@ -12,8 +12,10 @@ import (
 	"github.com/MicahParks/terseurl/storage"
 )
 
-// createStores handles the process of creating the SummaryStore, TerseStore, and VisitsStore. TODO
-func createStores(config *Configuration, group ctxerrgroup.Group, logger *zap.SugaredLogger, rawConfig *configuration) (err error) {
+// createStores handles the process of creating the AuthorizationStore, SummaryStore, TerseStore, and VisitsStore. The
+// AuthorizationStore is created last, because its default depends on the TerseStore. The visit processors are given to
+// the StoreManager.
+func createStores(config *Configuration, group ctxerrgroup.Group, logger *zap.SugaredLogger, rawConfig *configuration, visitProcess []storage.VisitProcessor) (err error) {
 
 	// Get the SummaryStore configuration.
 	var summaryConfig json.RawMessage
@@ -72,8 +74,27 @@ func createStores(config *Configuration, group ctxerrgroup.Group, logger *zap.Su
 		"type", terseStoreType,
 	)
 
+	// Get the AuthorizationStore configuration.
+	var authConfig json.RawMessage
+	if authConfig, err = readStorageConfig(rawConfig.AuthorizationStoreJSON, logger, configPathAuthorizationStore); err != nil {
+		return err
+	}
+
+	// Create the AuthorizationStore. It is persistent by default if the TerseStore is.
+	authStore, authStoreType, err := storage.NewAuthorizationStore(authConfig, terseStoreType)
+	if err != nil {
+		logger.Fatalw("Failed to create AuthorizationStore.",
+			"type", authStoreType,
+			"error", err.Error(),
+		)
+		return err // Should be unreachable.
+	}
+	logger.Infow("Created AuthorizationStore.",
+		"type", authStoreType,
+	)
+
 	// Create the store manager.
-	config.StoreManager = storage.NewStoreManager(DefaultCtx, group, summaryStore, terseStore, visitsStore)
+	config.StoreManager = storage.NewStoreManager(config.Policy.Admin, authStore, rawConfig.CountBots, DefaultCtx, group, summaryStore, terseStore, visitProcess, visitsStore)
 
 	// Initialize the SummaryStore.
 	ctx, cancel := DefaultCtx()
@@ -92,6 +113,10 @@ func readStorageConfig(envValue string, logger *zap.SugaredLogger, configPath st
 	// Decide if the configPath is valid. Generate a long message from it.
 	var logMessage string
 	switch configPath {
+	case configPathAuthorizationStore:
+		logMessage = "AuthorizationStore"
+	case configPathGenerator:
+		logMessage = "Generator"
 	case configPathSummaryStore:
 		logMessage = "SummaryStore"
 	case configPathTerseStore:
@@ -0,0 +1,93 @@
+package endpoints
+
+import (
+	"errors"
+	"time"
+	_ "time/tzdata" // Embed the time zone database, because it is missing from the Docker image.
+
+	"github.com/go-openapi/runtime/middleware"
+	"go.uber.org/zap"
+
+	"github.com/MicahParks/terseurl/configure"
+	"github.com/MicahParks/terseurl/models"
+	"github.com/MicahParks/terseurl/restapi/operations/api"
+	"github.com/MicahParks/terseurl/storage"
+)
+
+// HandleAnalytics creates a POST /api/analytics endpoint handler via a closure. It counts the visits to the requested
+// shortened URLs the principal is authorized for in each interval of a time series.
+func HandleAnalytics(logger *zap.SugaredLogger, manager storage.StoreManager) api.AnalyticsHandlerFunc {
+	return func(params api.AnalyticsParams, principal *models.Principal) middleware.Responder {
+
+		// Log the event.
+		logger.Debugw("Creating time series of visit counts.",
+			"from", params.From,
+			"interval", params.Interval,
+			"shortened", params.ShortenedURLs,
+			"timezone", params.Timezone,
+			"to", params.To,
+		)
+
+		// Create the description of the time series.
+		var series storage.Series
+		if params.From != nil {
+			series.From = time.Time(*params.From)
+		}
+		if params.Interval != nil {
+			series.Interval = storage.Interval(*params.Interval)
+		}
+		if params.Timezone != nil {
+			location, err := time.LoadLocation(*params.Timezone)
+			if err != nil {
+				message := "Unknown time zone."
+				logger.Infow(message,
+					"error", err.Error(),
+				)
+				return ErrorResponse(400, message, &api.AnalyticsDefault{})
+			}
+			series.Location = location
+		}
+		if params.To != nil {
+			series.To = time.Time(*params.To)
+		}
+
+		// Create a request context.
+		ctx, cancel := configure.DefaultCtx()
+		defer cancel()
+
+		// Count the visits in storage.
+		analytics, err := manager.Analytics(ctx, principal, params.ShortenedURLs, series)
+		if err != nil {
+
+			// Log at the appropriate level. Assign the response code and message.
+			var code int
+			var message string
+			if errors.Is(err, storage.ErrTooManyPoints) {
+				code = 400
+				message = "Too many intervals. Use a shorter time window or a longer interval."
+				logger.Infow(message,
+					"error", err.Error(),
+				)
+			} else if errors.Is(err, storage.ErrUnauthorized) {
+				code = 403
+				message = "Not authorized for the requested shortened URLs."
+				logger.Infow(message,
+					"error", err.Error(),
+				)
+			} else {
+				code = 500
+				message = "Failed to count visits for the shortened URLs."
+				logger.Errorw(message,
+					"error", err.Error(),
+				)
+			}
+
+			// Report the error to the client.
+			return ErrorResponse(code, message, &api.AnalyticsDefault{})
+		}
+
+		return &api.AnalyticsOK{
+			Payload: analytics,
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package endpoints
 
 import (
+	"errors"
+
 	"github.com/go-openapi/runtime/middleware"
 	"go.uber.org/zap"
 
@@ -10,7 +12,8 @@ import (
 	"github.com/MicahParks/terseurl/storage"
 )
 
-// HandleShortenedDelete TODO
+// HandleShortenedDelete creates a DELETE /api/shortened endpoint handler via a closure. It deletes all data for the
+// requested shortened URLs the principal is authorized for.
 func HandleShortenedDelete(logger *zap.SugaredLogger, manager storage.StoreManager) api.ShortenedDeleteHandlerFunc {
 	return func(params api.ShortenedDeleteParams, principal *models.Principal) middleware.Responder {
 
@@ -19,21 +22,45 @@ func HandleShortenedDelete(logger *zap.SugaredLogger, manager storage.StoreManag
 			"shortenedURLs", params.ShortenedURLs,
 		)
 
+		// Only administrators can delete all shortened URLs at once.
+		if len(params.ShortenedURLs) == 0 && !manager.Admin(principal) {
+
+			// Log at the appropriate level.
+			message := "Only administrators can delete all shortened URLs."
+			logger.Infow(message,
+				"sub", principal.Sub,
+			)
+
+			// Report the error to the client.
+			return ErrorResponse(403, message, &api.ShortenedDeleteDefault{})
+		}
+
 		// Create a new request context.
 		ctx, cancel := configure.DefaultCtx()
 		defer cancel()
 
 		// Delete all data for the requested shortened URLs.
-		if err := manager.DeleteShortened(ctx, params.ShortenedURLs); err != nil {
+		if err := manager.DeleteShortened(ctx, principal, params.ShortenedURLs); err != nil {
 
-			// Log at the appropriate level.
-			message := "Failed to delete data for the requested shortened URLs."
-			logger.Infow(message,
-				"error", err.Error(),
-			)
+			// Log at the appropriate level. Assign the response code and message.
+			var code int
+			var message string
+			if errors.Is(err, storage.ErrUnauthorized) {
+				code = 403
+				message = "Not authorized for the requested shortened URLs."
+				logger.Infow(message,
+					"error", err.Error(),
+				)
+			} else {
+				code = 500
+				message = "Failed to delete data for the requested shortened URLs."
+				logger.Infow(message,
+					"error", err.Error(),
+				)
+			}
 
 			// Report the error to the client.
-			return ErrorResponse(500, message, &api.ShortenedDeleteDefault{})
+			return ErrorResponse(code, message, &api.ShortenedDeleteDefault{})
 		}
 
 		return &api.ShortenedDeleteOK{}
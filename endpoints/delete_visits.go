@@ -1,6 +1,8 @@
 package endpoints
 
 import (
+	"errors"
+
 	"github.com/go-openapi/runtime/middleware"
 	"go.uber.org/zap"
 
@@ -10,30 +12,69 @@ import (
 	"github.com/MicahParks/terseurl/storage"
 )
 
-// HandlerVisitsDelete TODO
+// HandlerVisitsDelete creates a DELETE /api/visits endpoint handler via a closure. It deletes the Visits data for the
+// requested shortened URLs the principal is authorized for. Only the visits in a time window or from certain IP
+// addresses can be deleted.
 func HandlerVisitsDelete(logger *zap.SugaredLogger, manager storage.StoreManager) api.VisitsDeleteHandlerFunc {
 	return func(params api.VisitsDeleteParams, principal *models.Principal) middleware.Responder {
 
 		// Debug info.
 		logger.Debugw("Deleting Visits data.",
+			"from", params.From,
+			"ip", params.IP,
 			"shortenedURLs", params.ShortenedURLs,
+			"to", params.To,
 		)
 
+		// Create the filter for the visits to delete.
+		filter, err := visitsFilter(params.From, params.IP, params.To)
+		if err != nil {
+			message := "Invalid IP address or CIDR block."
+			logger.Infow(message,
+				"error", err.Error(),
+			)
+			return ErrorResponse(400, message, &api.VisitsDeleteDefault{})
+		}
+
+		// Only administrators can delete the Visits data of all shortened URLs at once.
+		if len(params.ShortenedURLs) == 0 && !manager.Admin(principal) {
+
+			// Log at the appropriate level.
+			message := "Only administrators can delete the Visits data of all shortened URLs."
+			logger.Infow(message,
+				"sub", principal.Sub,
+			)
+
+			// Report the error to the client.
+			return ErrorResponse(403, message, &api.VisitsDeleteDefault{})
+		}
+
 		// Create a new request context.
 		ctx, cancel := configure.DefaultCtx()
 		defer cancel()
 
 		// Delete Visits data for the requested shortened URLs.
-		if err := manager.DeleteShortened(ctx, params.ShortenedURLs); err != nil {
+		if err = manager.DeleteVisits(ctx, principal, params.ShortenedURLs, filter); err != nil {
 
-			// Log at the appropriate level.
-			message := "Failed to delete Visits data for the requested shortened URLs."
-			logger.Infow(message,
-				"error", err.Error(),
-			)
+			// Log at the appropriate level. Assign the response code and message.
+			var code int
+			var message string
+			if errors.Is(err, storage.ErrUnauthorized) {
+				code = 403
+				message = "Not authorized for the requested shortened URLs."
+				logger.Infow(message,
+					"error", err.Error(),
+				)
+			} else {
+				code = 500
+				message = "Failed to delete Visits data for the requested shortened URLs."
+				logger.Infow(message,
+					"error", err.Error(),
+				)
+			}
 
 			// Report the error to the client.
-			return ErrorResponse(500, message, &api.VisitsDeleteDefault{})
+			return ErrorResponse(code, message, &api.VisitsDeleteDefault{})
 		}
 
 		return &api.VisitsDeleteOK{}
@@ -1,6 +1,8 @@
 package endpoints
 
 import (
+	"errors"
+
 	"github.com/go-openapi/runtime/middleware"
 	"go.uber.org/zap"
 
@@ -18,6 +20,19 @@ func HandleExport(logger *zap.SugaredLogger, manager storage.StoreManager) api.E
 		// Log the event.
 		logger.Info("Exporting data.")
 
+		// Only administrators can export all shortened URLs at once.
+		if len(params.ShortenedURLs) == 0 && !manager.Admin(principal) {
+
+			// Log at the appropriate level.
+			message := "Only administrators can export all shortened URLs."
+			logger.Infow(message,
+				"sub", principal.Sub,
+			)
+
+			// Report the error to the client.
+			return ErrorResponse(403, message, &api.ExportDefault{})
+		}
+
 		// Create a request context.
 		//
 		// Maybe make a longer context if timing out.
@@ -25,17 +40,28 @@ func HandleExport(logger *zap.SugaredLogger, manager storage.StoreManager) api.E
 		defer cancel()
 
 		// Get the data dump.
-		dump, err := manager.Export(ctx, params.ShortenedURLs)
+		dump, err := manager.Export(ctx, principal, params.ShortenedURLs)
 		if err != nil {
 
-			// Log at the appropriate level.
-			message := "Failed to perform data dump."
-			logger.Warnw(message,
-				"error", err.Error(),
-			)
+			// Log at the appropriate level. Assign the response code and message.
+			var code int
+			var message string
+			if errors.Is(err, storage.ErrUnauthorized) {
+				code = 403
+				message = "Not authorized for the requested shortened URLs."
+				logger.Infow(message,
+					"error", err.Error(),
+				)
+			} else {
+				code = 500
+				message = "Failed to perform data dump."
+				logger.Warnw(message,
+					"error", err.Error(),
+				)
+			}
 
 			// Report the error to the client.
-			return ErrorResponse(500, message, &api.ExportDefault{})
+			return ErrorResponse(code, message, &api.ExportDefault{})
 		}
 
 		return &api.ExportOK{
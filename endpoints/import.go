@@ -1,6 +1,10 @@
 package endpoints
 
 import (
+	"errors"
+	"fmt"
+	"sort"
+
 	"github.com/go-openapi/runtime/middleware"
 	"go.uber.org/zap"
 
@@ -11,28 +15,61 @@ import (
 )
 
 // HandleImport creates and /api/import endpoint handler via a closure. It can import Terse and or Visits data. It will
-// delete existing data before importing, if told to do so.
-func HandleImport(logger *zap.SugaredLogger, manager storage.StoreManager) api.ImportHandlerFunc {
+// delete existing data before importing, if told to do so. Imported shortened URLs are rejected like client given
+// shortened URLs in HandleWrite.
+func HandleImport(logger *zap.SugaredLogger, invalidPaths []string, maxLength uint, manager storage.StoreManager) api.ImportHandlerFunc {
 	return func(params api.ImportParams, principal *models.Principal) middleware.Responder {
 
 		// Log the event.
 		logger.Infow("Importing data.")
 
+		// Confirm the shortened URLs are valid. Check them in order, so the same one is reported every time.
+		shortenedURLs := make([]string, 0, len(params.Import))
+		for shortened := range params.Import {
+			shortenedURLs = append(shortenedURLs, shortened)
+		}
+		sort.Strings(shortenedURLs)
+		for _, shortened := range shortenedURLs {
+			if err := validateShortened(shortened, invalidPaths, maxLength); err != nil {
+
+				// Log at the appropriate level.
+				message := fmt.Sprintf("Invalid shortened URL %q: %s.", shortened, err.Error())
+				logger.Infow("Invalid shortened URL.",
+					"shortenedURL", shortened,
+					"error", err.Error(),
+				)
+
+				// Report the error to the client.
+				return ErrorResponse(400, message, &api.ImportDefault{})
+			}
+		}
+
 		// Create a request context.
 		ctx, cancel := configure.DefaultCtx()
 		defer cancel()
 
 		// Import the given data.
-		if err := manager.Import(ctx, params.Import); err != nil {
+		if err := manager.Import(ctx, principal, params.Import); err != nil {
 
-			// Log at the appropriate level.
-			message := "Failed to import data. Clean up may be necessary."
-			logger.Warnw(message,
-				"error", err.Error(),
-			)
+			// Log at the appropriate level. Assign the response code and message.
+			var code int
+			var message string
+			if errors.Is(err, storage.ErrUnauthorized) {
+				code = 403
+				message = "Not going to overwrite shortened URL owned by another user."
+				logger.Infow(message,
+					"error", err.Error(),
+				)
+			} else {
+				code = 500
+				message = "Failed to import data. Clean up may be necessary."
+				logger.Warnw(message,
+					"error", err.Error(),
+				)
+			}
 
 			// Report the error to the client.
-			return ErrorResponse(500, message, &api.ImportDefault{})
+			return ErrorResponse(code, message, &api.ImportDefault{})
 		}
 
 		return &api.ImportOK{}
@@ -0,0 +1,64 @@
+package endpoints
+
+import (
+	"context"
+	"testing"
+
+	"github.com/MicahParks/ctxerrgroup"
+	"go.uber.org/zap"
+
+	"github.com/MicahParks/terseurl/models"
+	"github.com/MicahParks/terseurl/restapi/operations/api"
+	"github.com/MicahParks/terseurl/storage"
+)
+
+// TestHandleImport confirms imports with a shortened URL that cannot be routed to are rejected before anything is
+// imported.
+func TestHandleImport(t *testing.T) {
+	invalidPaths := []string{"api", "docs"}
+	const maxLength = 8
+
+	testCases := []struct {
+		name      string
+		shortened string
+		expected  int
+	}{
+		{name: "valid", shortened: "abc123", expected: 200},
+		{name: "invalid path", shortened: "api", expected: 400},
+		{name: "dot dot", shortened: "..", expected: 400},
+		{name: "slash", shortened: "a/b", expected: 400},
+		{name: "control", shortened: "a\nb", expected: 400},
+		{name: "too long", shortened: "abcdefghi", expected: 400},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			manager := storage.NewStoreManager(nil, nil, false, nil, ctxerrgroup.Group{}, storage.NewMemSummary(), storage.NewMemTerse(), nil, storage.NewMemVisits())
+			handler := HandleImport(zap.NewNop().Sugar(), invalidPaths, maxLength, manager)
+
+			// Import a valid shortened URL along with the tested one.
+			params := api.ImportParams{Import: map[string]*models.Export{
+				"valid":            {Terse: &models.Terse{OriginalURL: "https://example.com", ShortenedURL: "valid"}},
+				testCase.shortened: {Terse: &models.Terse{OriginalURL: "https://example.com", ShortenedURL: testCase.shortened}},
+			}}
+
+			// Confirm the response code.
+			code := 200
+			if resp, ok := handler(params, nil).(*api.ImportDefault); ok {
+				code = int(resp.Payload.Code)
+			}
+			if code != testCase.expected {
+				t.Fatalf("expected %d, got %d", testCase.expected, code)
+			}
+
+			// Confirm nothing was imported if the import was rejected.
+			terseData, err := manager.Terse(context.Background(), nil, nil)
+			if err != nil {
+				t.Fatalf("failed to read Terse data: %s", err.Error())
+			}
+			if imported := len(terseData) != 0; imported != (testCase.expected == 200) {
+				t.Fatalf("expected imported to be %t, got %t", testCase.expected == 200, imported)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package public
+
+import (
+	"net/url"
+	"path"
+	"strings"
+)
+
+// joinPath appends the suffix to the path of the destination URL. Dot segments in the suffix are removed first, so the
+// suffix cannot leave the destination URL's path. A trailing slash on the suffix is kept. The query and fragment of the
+// destination URL are kept. If the suffix is empty or the destination URL cannot be parsed, it is returned unchanged.
+func joinPath(destination, suffix string) (joined string) {
+
+	// Remove the dot segments of the suffix.
+	cleaned := strings.TrimPrefix(path.Clean("/"+suffix), "/")
+	if cleaned == "" {
+		return destination
+	}
+	if strings.HasSuffix(suffix, "/") {
+		cleaned += "/"
+	}
+
+	// Parse the destination URL.
+	u, err := url.Parse(destination)
+	if err != nil {
+		return destination
+	}
+
+	// Escape each segment of the suffix, so characters like ? and # stay in the path.
+	segments := strings.Split(cleaned, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+
+	// Join the escaped paths with exactly one slash.
+	rawPath := strings.TrimSuffix(u.EscapedPath(), "/") + "/" + strings.Join(segments, "/")
+	if u.Path, err = url.PathUnescape(rawPath); err != nil {
+		return destination
+	}
+	u.RawPath = rawPath
+
+	return u.String()
+}
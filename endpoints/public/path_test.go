@@ -0,0 +1,45 @@
+package public
+
+import (
+	"testing"
+)
+
+// TestJoinPath confirms the suffix is appended to the destination URL's path with exactly one slash, and dot segments
+// cannot leave the destination URL's path.
+func TestJoinPath(t *testing.T) {
+	testCases := []struct {
+		name        string
+		destination string
+		suffix      string
+		expected    string
+	}{
+		{name: "empty suffix", destination: "https://example.com/docs", suffix: "", expected: "https://example.com/docs"},
+		{name: "suffix", destination: "https://example.com/docs", suffix: "guide/install", expected: "https://example.com/docs/guide/install"},
+		{name: "no destination path", destination: "https://example.com", suffix: "guide", expected: "https://example.com/guide"},
+		{name: "destination trailing slash", destination: "https://example.com/docs/", suffix: "guide", expected: "https://example.com/docs/guide"},
+		{name: "suffix trailing slash", destination: "https://example.com/docs", suffix: "guide/", expected: "https://example.com/docs/guide/"},
+		{name: "both trailing slashes", destination: "https://example.com/docs/", suffix: "guide/install/", expected: "https://example.com/docs/guide/install/"},
+		{name: "only slash", destination: "https://example.com/docs", suffix: "/", expected: "https://example.com/docs"},
+		{name: "repeated slashes", destination: "https://example.com/docs", suffix: "guide//install", expected: "https://example.com/docs/guide/install"},
+		{name: "dot", destination: "https://example.com/docs", suffix: "./guide", expected: "https://example.com/docs/guide"},
+		{name: "dot dot", destination: "https://example.com/docs", suffix: "..", expected: "https://example.com/docs"},
+		{name: "dot dot prefix", destination: "https://example.com/docs", suffix: "../../admin", expected: "https://example.com/docs/admin"},
+		{name: "dot dot inside", destination: "https://example.com/docs", suffix: "guide/../../../admin", expected: "https://example.com/docs/admin"},
+		{name: "dot dot trailing slash", destination: "https://example.com/docs", suffix: "../", expected: "https://example.com/docs"},
+		{name: "escaped slash in suffix", destination: "https://example.com/docs", suffix: "a%2Fb", expected: "https://example.com/docs/a%252Fb"},
+		{name: "escaped dot dot in suffix", destination: "https://example.com/docs", suffix: "%2E%2E/admin", expected: "https://example.com/docs/%252E%252E/admin"},
+		{name: "escaped slash in destination", destination: "https://example.com/a%2Fb", suffix: "c", expected: "https://example.com/a%2Fb/c"},
+		{name: "query and fragment characters", destination: "https://example.com/docs", suffix: "a?b#c", expected: "https://example.com/docs/a%3Fb%23c"},
+		{name: "space", destination: "https://example.com/docs", suffix: "a b", expected: "https://example.com/docs/a%20b"},
+		{name: "destination query and fragment", destination: "https://example.com/docs?x=1#top", suffix: "guide", expected: "https://example.com/docs/guide?x=1#top"},
+		{name: "invalid destination", destination: "https://example.com/%zz", suffix: "guide", expected: "https://example.com/%zz"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if joined := joinPath(testCase.destination, testCase.suffix); joined != testCase.expected {
+				t.Fatalf("expected %q, got %q", testCase.expected, joined)
+			}
+		})
+	}
+}
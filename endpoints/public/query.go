@@ -0,0 +1,55 @@
+package public
+
+import (
+	"net/url"
+
+	"github.com/MicahParks/terseurl/models"
+)
+
+// addQuery adds the shortened URL's default query parameters and, if configured, the incoming query parameters of the
+// visit to the destination URL. Defaults never replace query parameters already in the destination URL. Incoming query
+// parameters that conflict with those in the destination URL or its defaults are handled by the shortened URL's query
+// conflict setting. If the destination URL cannot be parsed or there is nothing to add, it is returned unchanged.
+func addQuery(destination string, terse models.Terse, incoming url.Values) (withQuery string) {
+
+	// Only forward the incoming query parameters if configured.
+	if !terse.ForwardQuery {
+		incoming = nil
+	}
+	if len(terse.QueryDefaults) == 0 && len(incoming) == 0 {
+		return destination
+	}
+
+	// Parse the destination URL.
+	u, err := url.Parse(destination)
+	if err != nil {
+		return destination
+	}
+	query := u.Query()
+
+	// Add the default query parameters that are not already present.
+	for key, value := range terse.QueryDefaults {
+		if _, ok := query[key]; !ok {
+			query.Set(key, value)
+		}
+	}
+
+	// Add the incoming query parameters, resolving any conflicts.
+	for key, values := range incoming {
+		if _, ok := query[key]; ok {
+			switch terse.QueryConflict {
+			case models.QueryConflictAppend:
+				query[key] = append(query[key], values...)
+			case models.QueryConflictOriginal:
+				// Keep the value already present.
+			default:
+				query[key] = values
+			}
+			continue
+		}
+		query[key] = values
+	}
+	u.RawQuery = query.Encode()
+
+	return u.String()
+}
@@ -0,0 +1,53 @@
+package public
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/MicahParks/terseurl/models"
+)
+
+// TestAddQuery confirms defaults never replace query parameters already in the destination URL, and conflicts with
+// incoming query parameters are handled by the query conflict setting.
+func TestAddQuery(t *testing.T) {
+	defaults := map[string]string{"a": "default", "utm_source": "terse"}
+
+	testCases := []struct {
+		name        string
+		destination string
+		terse       models.Terse
+		incoming    string
+		expected    string
+	}{
+		{name: "nothing to add", destination: "https://example.com/?b=2&a=1", terse: models.Terse{ForwardQuery: true}, expected: "https://example.com/?b=2&a=1"},
+		{name: "not forwarded", destination: "https://example.com/?b=2&a=1", incoming: "c=3", expected: "https://example.com/?b=2&a=1"},
+		{name: "invalid destination", destination: "https://example.com/%zz", terse: models.Terse{ForwardQuery: true}, incoming: "c=3", expected: "https://example.com/%zz"},
+		{name: "defaults", destination: "https://example.com/", terse: models.Terse{QueryDefaults: defaults}, expected: "https://example.com/?a=default&utm_source=terse"},
+		{name: "defaults do not override", destination: "https://example.com/?a=1", terse: models.Terse{QueryDefaults: defaults}, expected: "https://example.com/?a=1&utm_source=terse"},
+		{name: "defaults do not override empty", destination: "https://example.com/?a=", terse: models.Terse{QueryDefaults: defaults}, expected: "https://example.com/?a=&utm_source=terse"},
+		{name: "defaults keep fragment", destination: "https://example.com/page#section", terse: models.Terse{QueryDefaults: map[string]string{"b": "2"}}, expected: "https://example.com/page?b=2#section"},
+		{name: "defaults not forwarded", destination: "https://example.com/?a=1", terse: models.Terse{QueryDefaults: defaults}, incoming: "a=2&utm_source=x", expected: "https://example.com/?a=1&utm_source=terse"},
+		{name: "forwarded", destination: "https://example.com/?a=1", terse: models.Terse{ForwardQuery: true}, incoming: "c=3&c=4", expected: "https://example.com/?a=1&c=3&c=4"},
+		{name: "empty conflict", destination: "https://example.com/?a=1", terse: models.Terse{ForwardQuery: true}, incoming: "a=2", expected: "https://example.com/?a=2"},
+		{name: "incoming conflict", destination: "https://example.com/?a=1", terse: models.Terse{ForwardQuery: true, QueryConflict: models.QueryConflictIncoming}, incoming: "a=2&a=3", expected: "https://example.com/?a=2&a=3"},
+		{name: "original conflict", destination: "https://example.com/?a=1", terse: models.Terse{ForwardQuery: true, QueryConflict: models.QueryConflictOriginal}, incoming: "a=2&b=3", expected: "https://example.com/?a=1&b=3"},
+		{name: "append conflict", destination: "https://example.com/?a=1", terse: models.Terse{ForwardQuery: true, QueryConflict: models.QueryConflictAppend}, incoming: "a=2", expected: "https://example.com/?a=1&a=2"},
+		{name: "incoming conflict with default", destination: "https://example.com/", terse: models.Terse{ForwardQuery: true, QueryConflict: models.QueryConflictIncoming, QueryDefaults: defaults}, incoming: "utm_source=x", expected: "https://example.com/?a=default&utm_source=x"},
+		{name: "original conflict with default", destination: "https://example.com/", terse: models.Terse{ForwardQuery: true, QueryConflict: models.QueryConflictOriginal, QueryDefaults: defaults}, incoming: "utm_source=x", expected: "https://example.com/?a=default&utm_source=terse"},
+		{name: "append conflict with default", destination: "https://example.com/", terse: models.Terse{ForwardQuery: true, QueryConflict: models.QueryConflictAppend, QueryDefaults: defaults}, incoming: "utm_source=x", expected: "https://example.com/?a=default&utm_source=terse&utm_source=x"},
+		{name: "original conflict keeps destination over default", destination: "https://example.com/?a=1", terse: models.Terse{ForwardQuery: true, QueryConflict: models.QueryConflictOriginal, QueryDefaults: defaults}, incoming: "a=2", expected: "https://example.com/?a=1&utm_source=terse"},
+		{name: "escaped", destination: "https://example.com/", terse: models.Terse{ForwardQuery: true}, incoming: "q=a+b%26c", expected: "https://example.com/?q=a+b%26c"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			incoming, err := url.ParseQuery(testCase.incoming)
+			if err != nil {
+				t.Fatalf("failed to parse incoming query: %s", err.Error())
+			}
+			if withQuery := addQuery(testCase.destination, testCase.terse, incoming); withQuery != testCase.expected {
+				t.Fatalf("expected %q, got %q", testCase.expected, withQuery)
+			}
+		})
+	}
+}
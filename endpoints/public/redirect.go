@@ -6,27 +6,68 @@ import (
 	"fmt"
 	"html/template"
 	"io/ioutil"
+	"net/http"
+	"net/url"
 	"time"
 
+	"github.com/go-openapi/runtime"
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/go-openapi/strfmt"
 	"go.uber.org/zap"
 
+	"github.com/MicahParks/terseurl/clientip"
 	"github.com/MicahParks/terseurl/configure"
+	"github.com/MicahParks/terseurl/geoip"
 	"github.com/MicahParks/terseurl/meta"
 	"github.com/MicahParks/terseurl/models"
 	"github.com/MicahParks/terseurl/restapi/operations/public"
 	"github.com/MicahParks/terseurl/storage"
+	"github.com/MicahParks/terseurl/useragent"
+)
+
+const (
+
+	// variantCookie is the name of the cookie that keeps a visitor on the same variant of a shortened URL. Its path is the
+	// shortened URL, so each shortened URL has its own.
+	variantCookie = "terseurl-variant"
+
+	// variantCookieMaxAge is the number of seconds a visitor is kept on the same variant.
+	variantCookieMaxAge = 365 * 24 * 60 * 60
 )
 
 // HandleRedirect creates and /{shortenedURL} endpoint handler via a closure. It can perform redirects based on the
-// shortened URL's Terse data. It will add visits to the VisitStore, if it exists.
-func HandleRedirect(logger *zap.SugaredLogger, tmpl *template.Template, manager storage.StoreManager) public.PublicRedirectHandlerFunc {
+// shortened URL's Terse data. See redirector.
+func HandleRedirect(logger *zap.SugaredLogger, tmpl *template.Template, gonePage []byte, locator *geoip.Locator, manager storage.StoreManager, previewBots bool, resolver clientip.Resolver) public.PublicRedirectHandlerFunc {
+	redirect := redirector(logger, tmpl, gonePage, locator, manager, previewBots, resolver)
 	return func(params public.PublicRedirectParams) middleware.Responder {
+		return redirect(params.HTTPRequest, params.ShortenedURL, "")
+	}
+}
+
+// HandleRedirectSuffix creates and /{shortenedURL}/{suffix} endpoint handler via a closure. It performs the same
+// redirects as HandleRedirect, but appends the suffix to the path of the URL redirected to. If the shortened URL does
+// not forward paths, a 404 is returned. See redirector.
+func HandleRedirectSuffix(logger *zap.SugaredLogger, tmpl *template.Template, gonePage []byte, locator *geoip.Locator, manager storage.StoreManager, previewBots bool, resolver clientip.Resolver) public.PublicRedirectSuffixHandlerFunc {
+	redirect := redirector(logger, tmpl, gonePage, locator, manager, previewBots, resolver)
+	return func(params public.PublicRedirectSuffixParams) middleware.Responder {
+		return redirect(params.HTTPRequest, params.ShortenedURL, params.Suffix)
+	}
+}
+
+// redirector creates a function that performs redirects based on the shortened URL's Terse data. It will add visits to
+// the VisitStore, if it exists. If the shortened URL has expired, the gonePage is returned with a 410, if given.
+// Otherwise, a 404 is returned. If previewBots is true, automated clients like link preview unfurlers are always given
+// the media preview HTML page, if the shortened URL has a media preview. The IP address of the visit comes from the
+// resolver. If the shortened URL's targeting rules have countries, the visit is located with the locator, if given,
+// before the rules pick the URL to redirect to. If no rule matches, the shortened URL's variant is used. A non-empty suffix is appended to the path of
+// the URL redirected to.
+func redirector(logger *zap.SugaredLogger, tmpl *template.Template, gonePage []byte, locator *geoip.Locator, manager storage.StoreManager, previewBots bool, resolver clientip.Resolver) func(request *http.Request, shortened, suffix string) middleware.Responder {
+	return func(request *http.Request, shortened, suffix string) middleware.Responder {
 
 		// Debug info.
 		logger.Debugw("Parameters",
-			"shortened", params.ShortenedURL,
+			"shortened", shortened,
+			"suffix", suffix,
 		)
 
 		// Create a new request context.
@@ -36,26 +77,45 @@ func HandleRedirect(logger *zap.SugaredLogger, tmpl *template.Template, manager
 		// Get the current time in the desired format.
 		visitTime := strfmt.DateTime(time.Now())
 
+		// Get the IP address of the client. Forwarding headers are only trusted from trusted proxies.
+		ip := resolver.ClientIP(request)
+
 		// Create the visit to represent this request.
 		visit := models.Visit{
 			Accessed: &visitTime,
-			Headers:  params.HTTPRequest.Header,
-			IP:       &params.HTTPRequest.RemoteAddr, // TODO Use X-Forwarded-For if configured to do so.
+			Bot:      useragent.IsBot(request.UserAgent()),
+			Headers:  request.Header,
+			IP:       &ip,
+		}
+
+		// Get the variant the visitor was assigned before, if any. An invalid cookie is not used.
+		var assigned string
+		if cookie, cookieErr := request.Cookie(variantCookie); cookieErr == nil {
+			assigned, _ = url.QueryUnescape(cookie.Value) // Ignore any error.
 		}
 
 		// Get the Terse from the TerseStore.
-		terse, err := manager.Redirect(ctx, params.ShortenedURL, visit)
+		terse, variant, err := manager.Redirect(ctx, shortened, assigned, visit.Bot)
 		if err != nil {
 
 			// Log at the appropriate level.
 			if errors.Is(err, storage.ErrShortenedNotFound) {
 				logger.Infow("Shortened URL not found.",
-					"shortened", params.ShortenedURL,
+					"shortened", shortened,
 					"error", err.Error(),
 				)
+			} else if errors.Is(err, storage.ErrShortenedExpired) {
+				logger.Infow("Shortened URL expired.",
+					"shortened", shortened,
+				)
+
+				// Use the gone page, if configured.
+				if len(gonePage) != 0 {
+					return &public.PublicRedirectGone{Payload: ioutil.NopCloser(bytes.NewReader(gonePage))}
+				}
 			} else {
 				logger.Errorw("Failed to get original URL from shortened.",
-					"shortened", params.ShortenedURL,
+					"shortened", shortened,
 					"error", err.Error(),
 				)
 			}
@@ -64,15 +124,69 @@ func HandleRedirect(logger *zap.SugaredLogger, tmpl *template.Template, manager
 			return &public.PublicRedirectNotFound{}
 		}
 
+		// Only forward the rest of the path if the shortened URL opts in.
+		if suffix != "" && !terse.ForwardPath {
+			logger.Infow("Shortened URL does not forward paths.",
+				"shortened", shortened,
+				"suffix", suffix,
+			)
+			manager.ReleaseVisit(shortened, visit.Bot)
+			return &public.PublicRedirectNotFound{}
+		}
+
+		// Add the country and ASN of the client now, if the targeting rules need the country and GeoIP databases are
+		// configured. Otherwise, the visit is located after the response with the other visit processing.
+		if locator != nil && countryRules(*terse) {
+			visit = locator.Visit(visit)
+		}
+
+		// Pick the URL to redirect to with the targeting rules, then the variant. Keep track of the visit and the rule or
+		// variant that was used.
+		fallback := terse.OriginalURL
+		if variant != nil {
+			fallback = variant.OriginalURL
+		}
+		var destination string
+		destination, visit.TargetRule = target(*terse, newClient(visit), fallback)
+		if visit.TargetRule == "" && variant != nil {
+			visit.Variant = variant.Name
+		}
+		manager.RecordVisit(shortened, visit)
+
+		// Add the rest of the path and the default and forwarded query parameters to the URL, if configured. This applies
+		// to every redirect type.
+		destination = joinPath(destination, suffix)
+		destination = addQuery(destination, *terse, request.URL.Query())
+
+		// Keep the visitor on the same variant with a cookie, if configured.
+		var cookie *http.Cookie
+		if terse.StickyVariants && visit.Variant != "" && visit.Variant != assigned {
+			cookie = &http.Cookie{
+				Name:     variantCookie,
+				Value:    url.QueryEscape(visit.Variant),
+				Path:     "/" + url.PathEscape(shortened),
+				MaxAge:   variantCookieMaxAge,
+				HttpOnly: true,
+				SameSite: http.SameSiteLaxMode,
+			}
+		}
+
 		// TODO Validate OriginalURL, if needed. Like if empty.
 
+		// Give automated clients the media preview, if configured. Use a meta redirect in case the client is a human.
+		redirectType := terse.RedirectType
+		crawlerPreview := previewBots && visit.Bot && terse.MediaPreview != nil
+		if crawlerPreview {
+			redirectType = models.RedirectTypeMeta
+		}
+
 		// Check to see if a 301 redirect needs to be issued.
-		if terse.RedirectType == models.RedirectTypeNr301 {
-			return &public.PublicRedirectMovedPermanently{Location: terse.OriginalURL}
+		if redirectType == models.RedirectTypeNr301 {
+			return withCookie(&public.PublicRedirectMovedPermanently{Location: destination}, cookie)
 		}
 
 		// Check to see if an HTML file should be returned instead.
-		if terse.MediaPreview != nil && terse.JavascriptTracking || terse.RedirectType == models.RedirectTypeJs || terse.RedirectType == models.RedirectTypeMeta { // TODO Verify logic behind this if statement.
+		if crawlerPreview || terse.MediaPreview != nil && terse.JavascriptTracking || terse.RedirectType == models.RedirectTypeJs || terse.RedirectType == models.RedirectTypeMeta { // TODO Verify logic behind this if statement.
 
 			// Create a buffer to write the populated HTML template with.
 			buf := bytes.NewBuffer(nil)
@@ -80,8 +194,8 @@ func HandleRedirect(logger *zap.SugaredLogger, tmpl *template.Template, manager
 			// Create the proper metadata for the HTML page.
 			previewMeta := meta.Preview{
 				MediaPreview: *terse.MediaPreview,
-				Redirect:     terse.OriginalURL,
-				RedirectType: terse.RedirectType,
+				Redirect:     destination,
+				RedirectType: redirectType,
 			}
 
 			logger.Debugw("",
@@ -91,12 +205,12 @@ func HandleRedirect(logger *zap.SugaredLogger, tmpl *template.Template, manager
 
 			// If there is no error in populating the HTML template, return an HTML document to the client.
 			if err = tmpl.Execute(buf, previewMeta); err == nil {
-				return &public.PublicRedirectOK{Payload: ioutil.NopCloser(buf)}
+				return withCookie(&public.PublicRedirectOK{Payload: ioutil.NopCloser(buf)}, cookie)
 			}
 
 			// Failed to execute HTML template. Log the event. Reassign the error to nil. Perform the default redirect.
 			logger.Warnw("Failed to execute template.",
-				"shortened", params.ShortenedURL,
+				"shortened", shortened,
 				"error", err.Error(),
 			)
 			err = nil
@@ -110,8 +224,19 @@ func HandleRedirect(logger *zap.SugaredLogger, tmpl *template.Template, manager
 		}
 
 		// Issue a standard temporary redirect.
-		return &public.PublicRedirectFound{
-			Location: terse.OriginalURL,
-		}
+		return withCookie(&public.PublicRedirectFound{
+			Location: destination,
+		}, cookie)
+	}
+}
+
+// withCookie sets the cookie when the responder writes its response. If the cookie is nil, the responder is unchanged.
+func withCookie(responder middleware.Responder, cookie *http.Cookie) middleware.Responder {
+	if cookie == nil {
+		return responder
 	}
+	return middleware.ResponderFunc(func(writer http.ResponseWriter, producer runtime.Producer) {
+		http.SetCookie(writer, cookie)
+		responder.WriteResponse(writer, producer)
+	})
 }
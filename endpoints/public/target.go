@@ -0,0 +1,146 @@
+package public
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/MicahParks/terseurl/models"
+	"github.com/MicahParks/terseurl/useragent"
+)
+
+// client is the information about a client that targeting rules match against.
+type client struct {
+	country  string
+	language string
+	platform models.Platform
+}
+
+// newClient gathers the information about the client that made the visit. The country comes from the visit, so it must
+// already be located.
+func newClient(visit models.Visit) (c client) {
+	headers := http.Header(visit.Headers)
+	return client{
+		country:  visit.Country,
+		language: preferredLanguage(headers.Get("Accept-Language")),
+		platform: platform(headers.Get("User-Agent")),
+	}
+}
+
+// target picks the URL to redirect the client to. The first targeting rule that matches the client is used. If no rule
+// matches, the fallback URL is used and the rule's name is empty.
+func target(terse models.Terse, c client, fallback string) (destination, rule string) {
+	for _, targetRule := range terse.Targets {
+		if c.matches(targetRule) {
+			return targetRule.OriginalURL, targetRule.Name
+		}
+	}
+	return fallback, ""
+}
+
+// countryRules determines if any of the Terse data's targeting rules need the client's country.
+func countryRules(terse models.Terse) (needed bool) {
+	for _, rule := range terse.Targets {
+		if len(rule.Countries) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// matches determines if the client matches all the criteria of the targeting rule. Empty criteria match any client.
+func (c client) matches(rule models.TargetRule) (match bool) {
+
+	// Check the country.
+	if len(rule.Countries) != 0 && !anyMatch(len(rule.Countries), func(i int) bool {
+		return c.country != "" && strings.EqualFold(rule.Countries[i], c.country)
+	}) {
+		return false
+	}
+
+	// Check the language.
+	if len(rule.Languages) != 0 && !anyMatch(len(rule.Languages), func(i int) bool {
+		return languageMatches(rule.Languages[i], c.language)
+	}) {
+		return false
+	}
+
+	// Check the platform.
+	if len(rule.Platforms) != 0 && !anyMatch(len(rule.Platforms), func(i int) bool {
+		return c.platform != "" && rule.Platforms[i] == c.platform
+	}) {
+		return false
+	}
+
+	return true
+}
+
+// anyMatch determines if the matcher is true for any of the first n indices.
+func anyMatch(n int, matcher func(i int) bool) (match bool) {
+	for i := 0; i < n; i++ {
+		if matcher(i) {
+			return true
+		}
+	}
+	return false
+}
+
+// languageMatches determines if the client's language tag matches the language tag of a targeting rule. A tag without a
+// region also matches tags with a region. The comparison is case insensitive.
+func languageMatches(ruleTag, clientTag string) (match bool) {
+	if clientTag == "" {
+		return false
+	}
+	ruleTag = strings.ToLower(ruleTag)
+	clientTag = strings.ToLower(clientTag)
+	return clientTag == ruleTag || strings.HasPrefix(clientTag, ruleTag+"-")
+}
+
+// platform determines the platform of the client from its User-Agent. Platforms other than iOS, Android, and desktop
+// are empty.
+func platform(userAgent string) (p models.Platform) {
+	agent := useragent.Parse(userAgent)
+	switch {
+	case agent.OS == "iOS":
+		return models.PlatformIos
+	case agent.OS == "Android":
+		return models.PlatformAndroid
+	case agent.Device == useragent.DeviceDesktop:
+		return models.PlatformDesktop
+	}
+	return ""
+}
+
+// preferredLanguage returns the language tag with the highest quality value in the Accept-Language header. Tags that
+// appear first win ties. The wildcard tag is ignored.
+func preferredLanguage(header string) (tag string) {
+	best := 0.
+	for _, element := range strings.Split(header, ",") {
+
+		// Split the language tag from its parameters.
+		parts := strings.Split(element, ";")
+		language := strings.TrimSpace(parts[0])
+		if language == "" || language == "*" {
+			continue
+		}
+
+		// Find the quality value. It defaults to 1.
+		quality := 1.
+		for _, param := range parts[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				var err error
+				if quality, err = strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err != nil {
+					quality = 0
+				}
+			}
+		}
+
+		// Keep the language tag if it is preferred.
+		if quality > best {
+			best = quality
+			tag = language
+		}
+	}
+	return tag
+}
@@ -0,0 +1,185 @@
+package public
+
+import (
+	"testing"
+
+	"github.com/MicahParks/terseurl/models"
+)
+
+const (
+
+	// userAgentAndroid is the User-Agent of an Android phone.
+	userAgentAndroid = "Mozilla/5.0 (Linux; Android 11; Pixel 5) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/90.0.4430.91 Mobile Safari/537.36"
+
+	// userAgentBot is the User-Agent of a link preview unfurler.
+	userAgentBot = "Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)"
+
+	// userAgentDesktop is the User-Agent of a desktop browser.
+	userAgentDesktop = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/90.0.4430.93 Safari/537.36"
+
+	// userAgentIPad is the User-Agent of an iPad.
+	userAgentIPad = "Mozilla/5.0 (iPad; CPU OS 14_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/14.1 Mobile/15E148 Safari/604.1"
+
+	// userAgentIPhone is the User-Agent of an iPhone.
+	userAgentIPhone = "Mozilla/5.0 (iPhone; CPU iPhone OS 14_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/14.1 Mobile/15E148 Safari/604.1"
+)
+
+// TestLanguageMatches confirms a language tag matches itself and tags with a region, case insensitively.
+func TestLanguageMatches(t *testing.T) {
+	testCases := []struct {
+		ruleTag   string
+		clientTag string
+		match     bool
+	}{
+		{ruleTag: "en", clientTag: "en", match: true},
+		{ruleTag: "en", clientTag: "en-US", match: true},
+		{ruleTag: "EN", clientTag: "en-us", match: true},
+		{ruleTag: "en-US", clientTag: "en-us", match: true},
+		{ruleTag: "zh", clientTag: "zh-Hant-TW", match: true},
+		{ruleTag: "en-US", clientTag: "en"},
+		{ruleTag: "en-US", clientTag: "en-GB"},
+		{ruleTag: "en", clientTag: "eng"},
+		{ruleTag: "e", clientTag: "en"},
+		{ruleTag: "en", clientTag: ""},
+		{ruleTag: "", clientTag: ""},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.ruleTag+" "+testCase.clientTag, func(t *testing.T) {
+			if match := languageMatches(testCase.ruleTag, testCase.clientTag); match != testCase.match {
+				t.Fatalf("expected %t, got %t", testCase.match, match)
+			}
+		})
+	}
+}
+
+// TestPreferredLanguage confirms the language tag with the highest quality value is used and the first wins ties.
+func TestPreferredLanguage(t *testing.T) {
+	testCases := []struct {
+		name     string
+		header   string
+		expected string
+	}{
+		{name: "empty", header: "", expected: ""},
+		{name: "single", header: "fr-CH", expected: "fr-CH"},
+		{name: "first wins ties", header: "fr, en", expected: "fr"},
+		{name: "quality", header: "fr;q=0.5, en;q=0.9, de;q=0.7", expected: "en"},
+		{name: "default quality", header: "fr;q=0.9, en", expected: "en"},
+		{name: "wildcard", header: "*, fr;q=0.5", expected: "fr"},
+		{name: "only wildcard", header: "*", expected: ""},
+		{name: "zero quality", header: "fr;q=0", expected: ""},
+		{name: "invalid quality", header: "fr;q=high, en;q=0.1", expected: "en"},
+		{name: "whitespace", header: "  fr-CH ;  q=0.8 ,  en ; q=0.9 ", expected: "en"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if tag := preferredLanguage(testCase.header); tag != testCase.expected {
+				t.Fatalf("expected %q, got %q", testCase.expected, tag)
+			}
+		})
+	}
+}
+
+// TestPlatform confirms the platform is detected from the User-Agent.
+func TestPlatform(t *testing.T) {
+	testCases := []struct {
+		name      string
+		userAgent string
+		expected  models.Platform
+	}{
+		{name: "android", userAgent: userAgentAndroid, expected: models.PlatformAndroid},
+		{name: "desktop", userAgent: userAgentDesktop, expected: models.PlatformDesktop},
+		{name: "iPad", userAgent: userAgentIPad, expected: models.PlatformIos},
+		{name: "iPhone", userAgent: userAgentIPhone, expected: models.PlatformIos},
+		{name: "bot", userAgent: userAgentBot, expected: ""},
+		{name: "empty", userAgent: "", expected: ""},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if p := platform(testCase.userAgent); p != testCase.expected {
+				t.Fatalf("expected %q, got %q", testCase.expected, p)
+			}
+		})
+	}
+}
+
+// TestTarget confirms the first targeting rule that matches the client is used, and the fallback URL is used when none
+// match.
+func TestTarget(t *testing.T) {
+	terse := models.Terse{
+		Targets: []models.TargetRule{
+			{Name: "german iOS", OriginalURL: "https://example.com/de/ios", Languages: []string{"de"}, Platforms: []models.Platform{models.PlatformIos}},
+			{Name: "mobile", OriginalURL: "https://example.com/mobile", Platforms: []models.Platform{models.PlatformIos, models.PlatformAndroid}},
+			{Name: "canada", OriginalURL: "https://example.com/ca", Countries: []string{"CA"}},
+			{Name: "french", OriginalURL: "https://example.com/fr", Languages: []string{"fr"}},
+			{Name: "french desktop", OriginalURL: "https://example.com/fr/desktop", Languages: []string{"fr"}, Platforms: []models.Platform{models.PlatformDesktop}},
+		},
+	}
+	const fallback = "https://example.com"
+
+	testCases := []struct {
+		name        string
+		visit       models.Visit
+		destination string
+		rule        string
+	}{
+		{name: "no match", visit: testVisit("US", "en-US", userAgentDesktop), destination: fallback},
+		{name: "all criteria", visit: testVisit("DE", "de-DE", userAgentIPhone), destination: "https://example.com/de/ios", rule: "german iOS"},
+		{name: "not all criteria", visit: testVisit("DE", "de-DE", userAgentDesktop), destination: fallback},
+		{name: "any platform", visit: testVisit("US", "en", userAgentAndroid), destination: "https://example.com/mobile", rule: "mobile"},
+		{name: "first rule wins", visit: testVisit("CA", "fr-CA", userAgentAndroid), destination: "https://example.com/mobile", rule: "mobile"},
+		{name: "country case insensitive", visit: testVisit("ca", "en", userAgentDesktop), destination: "https://example.com/ca", rule: "canada"},
+		{name: "unknown country", visit: testVisit("", "en", userAgentDesktop), destination: fallback},
+		{name: "language prefix", visit: testVisit("FR", "fr-FR", userAgentDesktop), destination: "https://example.com/fr", rule: "french"},
+		{name: "preferred language", visit: testVisit("US", "en;q=0.5, fr-BE;q=0.8", userAgentDesktop), destination: "https://example.com/fr", rule: "french"},
+		{name: "unknown platform", visit: testVisit("US", "en", userAgentBot), destination: fallback},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			destination, rule := target(terse, newClient(testCase.visit), fallback)
+			if destination != testCase.destination || rule != testCase.rule {
+				t.Fatalf("expected %q from rule %q, got %q from rule %q", testCase.destination, testCase.rule, destination, rule)
+			}
+		})
+	}
+
+	// Without targeting rules, the fallback is always used.
+	if destination, rule := target(models.Terse{}, newClient(testVisit("DE", "de", userAgentIPhone)), fallback); destination != fallback || rule != "" {
+		t.Fatalf("expected the fallback without targeting rules, got %q from rule %q", destination, rule)
+	}
+}
+
+// testVisit creates a visit from the given country, Accept-Language header, and User-Agent.
+func testVisit(country, acceptLanguage, userAgent string) (v models.Visit) {
+	return models.Visit{
+		Country: country,
+		Headers: map[string][]string{
+			"Accept-Language": {acceptLanguage},
+			"User-Agent":      {userAgent},
+		},
+	}
+}
+
+// TestCountryRules confirms the client is only located before redirecting if a targeting rule has countries.
+func TestCountryRules(t *testing.T) {
+	testCases := []struct {
+		name     string
+		targets  []models.TargetRule
+		expected bool
+	}{
+		{name: "no rules"},
+		{name: "no countries", targets: []models.TargetRule{{Name: "german", Languages: []string{"de"}}}},
+		{name: "countries", targets: []models.TargetRule{{Name: "german", Languages: []string{"de"}}, {Name: "canada", Countries: []string{"CA"}}}, expected: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if needed := countryRules(models.Terse{Targets: testCase.targets}); needed != testCase.expected {
+				t.Fatalf("expected %t, got %t", testCase.expected, needed)
+			}
+		})
+	}
+}
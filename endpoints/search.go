@@ -0,0 +1,58 @@
+package endpoints
+
+import (
+	"errors"
+
+	"github.com/go-openapi/runtime/middleware"
+	"go.uber.org/zap"
+
+	"github.com/MicahParks/terseurl/configure"
+	"github.com/MicahParks/terseurl/models"
+	"github.com/MicahParks/terseurl/restapi/operations/api"
+	"github.com/MicahParks/terseurl/storage"
+)
+
+// HandleSearch creates a /api/search endpoint handler via a closure. It can find the Terse data whose original URL
+// matches the search query.
+func HandleSearch(logger *zap.SugaredLogger, manager storage.StoreManager) api.SearchHandlerFunc {
+	return func(params api.SearchParams, principal *models.Principal) middleware.Responder {
+
+		// Debug info.
+		logger.Debugw("Requested search.",
+			"after", params.Query.After,
+			"limit", params.Query.Limit,
+			"match", params.Query.Match,
+			"value", params.Query.Value,
+		)
+
+		// Create a new request context.
+		ctx, cancel := configure.DefaultCtx()
+		defer cancel()
+
+		// Search for the matching Terse data.
+		results, err := manager.Search(ctx, principal, *params.Query)
+		if err != nil {
+
+			// Log at the appropriate level. Assign the response code and message.
+			var code int
+			var message string
+			if errors.Is(err, storage.ErrUnauthorized) {
+				code = 403
+				message = "Not authorized to search shortened URLs."
+			} else {
+				code = 500
+				message = "Failed to search shortened URLs."
+			}
+			logger.Infow(message,
+				"error", err.Error(),
+			)
+
+			// Report the error to the client.
+			return ErrorResponse(code, message, &api.SearchDefault{})
+		}
+
+		return &api.SearchOK{
+			Payload: results,
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package endpoints
 
 import (
+	"errors"
+
 	"github.com/go-openapi/runtime/middleware"
 	"go.uber.org/zap"
 
@@ -11,31 +13,49 @@ import (
 )
 
 // HandleShortenedSummary creates a /api/summary endpoint handler via a closure. It can provide Summary data for the
-// requested shortened URLs.
+// requested shortened URLs, sorted and paginated.
 func HandleShortenedSummary(logger *zap.SugaredLogger, manager storage.StoreManager) api.ShortenedSummaryHandlerFunc {
 	return func(params api.ShortenedSummaryParams, principal *models.Principal) middleware.Responder {
 
 		// Debug info.
 		logger.Debugw("Requested summary data.",
+			"cursor", params.Cursor,
+			"descending", params.Descending,
+			"limit", params.Limit,
 			"shortenedURLs", params.ShortenedURLs,
+			"sort", params.Sort,
 		)
 
 		// Create a new request context.
 		ctx, cancel := configure.DefaultCtx()
 		defer cancel()
 
-		// Gather the summary information for the requested shortened URLs.
-		summaries, err := manager.Summary(ctx, params.ShortenedURLs)
+		// Gather the page of summary information for the requested shortened URLs.
+		summaries, err := manager.SummaryPage(ctx, principal, params.ShortenedURLs, page(params.Cursor, params.Descending, params.Limit, params.Sort))
 		if err != nil {
 
-			// Log at the appropriate level.
-			message := "Failed to gather summary information for requested shortened URLs."
+			// Log at the appropriate level. Assign the response code and message.
+			var code int
+			var message string
+			if errors.Is(err, storage.ErrInvalidCursor) {
+				code = 400
+				message = "Invalid cursor for the requested sort."
+			} else if errors.Is(err, storage.ErrShortenedNotFound) {
+				code = 400
+				message = "Shortened URL not found."
+			} else if errors.Is(err, storage.ErrUnauthorized) {
+				code = 403
+				message = "Not authorized for the requested shortened URLs."
+			} else {
+				code = 500
+				message = "Failed to gather summary information for requested shortened URLs."
+			}
 			logger.Infow(message,
 				"error", err.Error(),
 			)
 
 			// Report the error to the client.
-			return ErrorResponse(500, message, &api.ShortenedSummaryDefault{})
+			return ErrorResponse(code, message, &api.ShortenedSummaryDefault{})
 		}
 
 		return &api.ShortenedSummaryOK{
@@ -13,32 +13,54 @@ import (
 )
 
 // HandleTerseRead creates and /api/terse/{shortened} endpoint handler via a closure. It can perform exports of a single
-// shortened URL's Terse data.
+// shortened URL's Terse data, or a sorted page of many shortened URLs' Terse data.
 func HandleTerseRead(logger *zap.SugaredLogger, manager storage.StoreManager) api.TerseReadHandlerFunc {
 	return func(params api.TerseReadParams, principal *models.Principal) middleware.Responder {
 
 		// Log the event.
 		logger.Infow("Reading a shortened URL's Terse data.",
+			"cursor", params.Cursor,
+			"descending", params.Descending,
+			"limit", params.Limit,
 			"shortenedURLs", params.ShortenedURLs,
+			"sort", params.Sort,
 		)
 
 		// Create a new request context.
 		ctx, cancel := configure.DefaultCtx()
 		defer cancel()
 
-		// Get the Terse.
-		terse, err := manager.Terse(ctx, params.ShortenedURLs)
+		// Get the page of Terse.
+		terse, err := manager.TersePage(ctx, principal, params.ShortenedURLs, page(params.Cursor, params.Descending, params.Limit, params.Sort))
 		if err != nil {
 
 			// Log at the appropriate level. Assign the response code and message.
 			var code int
 			var message string
-			if errors.Is(err, storage.ErrShortenedNotFound) {
+			if errors.Is(err, storage.ErrInvalidCursor) {
+				code = 400
+				message = "Invalid cursor for the requested sort."
+				logger.Infow(message,
+					"error", err.Error(),
+				)
+			} else if errors.Is(err, storage.ErrShortenedNotFound) {
 				code = 400
 				message = "Shortened URL not found."
 				logger.Infow(message,
 					"error", err.Error(),
 				)
+			} else if errors.Is(err, storage.ErrUnsupportedSort) {
+				code = 400
+				message = "Sorting by visit count requires a SummaryStore."
+				logger.Infow(message,
+					"error", err.Error(),
+				)
+			} else if errors.Is(err, storage.ErrUnauthorized) {
+				code = 403
+				message = "Not authorized for the requested shortened URLs."
+				logger.Infow(message,
+					"error", err.Error(),
+				)
 			} else {
 				code = 500
 				message = "Failed to get Terse from shortened URL."
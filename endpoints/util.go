@@ -7,6 +7,7 @@ import (
 	"github.com/go-openapi/runtime/middleware"
 
 	"github.com/MicahParks/terseurl/models"
+	"github.com/MicahParks/terseurl/storage"
 )
 
 // defaultResponse is an interface used to pass different types of default responses and return an error responder.
@@ -30,3 +31,20 @@ func ErrorResponse(code int, message string, resp defaultResponse) middleware.Re
 
 	return resp
 }
+
+// page creates a storage.Page from the optional pagination query parameters.
+func page(cursor *string, descending *bool, limit *uint64, sort *string) (p storage.Page) {
+	if cursor != nil {
+		p.Cursor = *cursor
+	}
+	if descending != nil {
+		p.Descending = *descending
+	}
+	if limit != nil {
+		p.Limit = *limit
+	}
+	if sort != nil {
+		p.Sort = storage.PageSort(*sort)
+	}
+	return p
+}
@@ -0,0 +1,57 @@
+package endpoints
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+var (
+
+	// ErrShortenedControl indicates the shortened URL contains a control character.
+	ErrShortenedControl = errors.New("the shortened URL contains a control character")
+
+	// ErrShortenedReserved indicates the shortened URL is reserved by the service.
+	ErrShortenedReserved = errors.New("the shortened URL is reserved")
+
+	// ErrShortenedSlash indicates the shortened URL contains a slash.
+	ErrShortenedSlash = errors.New("the shortened URL contains a slash")
+
+	// ErrShortenedTooLong indicates the shortened URL has more characters than allowed.
+	ErrShortenedTooLong = errors.New("the shortened URL is too long")
+)
+
+// validateShortened confirms the given shortened URL can be routed to. It must not be a reserved path, contain a slash
+// or control character, or have more than maxLength characters.
+func validateShortened(shortened string, invalidPaths []string, maxLength uint) (err error) {
+
+	// Confirm the shortened URL is not a reserved path. The relative paths are always reserved.
+	if shortened == "." || shortened == ".." {
+		return ErrShortenedReserved
+	}
+	for _, path := range invalidPaths {
+		if shortened == path {
+			return ErrShortenedReserved
+		}
+	}
+
+	// Confirm the shortened URL is not too long.
+	if length := len([]rune(shortened)); uint(length) > maxLength {
+		return fmt.Errorf("%w: %d characters is more than the maximum of %d", ErrShortenedTooLong, length, maxLength)
+	}
+
+	// Confirm the shortened URL does not contain a slash.
+	if strings.ContainsAny(shortened, `/\`) {
+		return ErrShortenedSlash
+	}
+
+	// Confirm the shortened URL does not contain a control character.
+	for _, r := range shortened {
+		if unicode.IsControl(r) {
+			return ErrShortenedControl
+		}
+	}
+
+	return nil
+}
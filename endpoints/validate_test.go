@@ -0,0 +1,58 @@
+package endpoints
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestValidateShortened confirms shortened URLs that cannot be routed to are rejected with the appropriate error.
+func TestValidateShortened(t *testing.T) {
+	invalidPaths := []string{"api", "docs", "favicon.ico"}
+	const maxLength = 8
+
+	testCases := []struct {
+		name      string
+		shortened string
+		expected  error
+	}{
+		{name: "valid", shortened: "abc123"},
+		{name: "valid with dots", shortened: "a.b..c"},
+		{name: "valid three dots", shortened: "..."},
+		{name: "valid unicode", shortened: "héllo"},
+		{name: "valid max length", shortened: strings.Repeat("a", maxLength)},
+		{name: "valid max length in runes", shortened: strings.Repeat("é", maxLength)},
+		{name: "valid invalid path prefix", shortened: "apis"},
+		{name: "valid invalid path different case", shortened: "API"},
+		{name: "dot", shortened: ".", expected: ErrShortenedReserved},
+		{name: "dot dot", shortened: "..", expected: ErrShortenedReserved},
+		{name: "invalid path", shortened: "api", expected: ErrShortenedReserved},
+		{name: "invalid path with dot", shortened: "favicon.ico", expected: ErrShortenedReserved},
+		{name: "too long", shortened: strings.Repeat("a", maxLength+1), expected: ErrShortenedTooLong},
+		{name: "too long in runes", shortened: strings.Repeat("é", maxLength+1), expected: ErrShortenedTooLong},
+		{name: "slash", shortened: "a/b", expected: ErrShortenedSlash},
+		{name: "leading slash", shortened: "/a", expected: ErrShortenedSlash},
+		{name: "trailing slash", shortened: "a/", expected: ErrShortenedSlash},
+		{name: "backslash", shortened: `a\b`, expected: ErrShortenedSlash},
+		{name: "null", shortened: "a\x00b", expected: ErrShortenedControl},
+		{name: "newline", shortened: "a\nb", expected: ErrShortenedControl},
+		{name: "tab", shortened: "a\tb", expected: ErrShortenedControl},
+		{name: "delete", shortened: "a\x7fb", expected: ErrShortenedControl},
+		{name: "C1 control", shortened: "a\u0085b", expected: ErrShortenedControl},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := validateShortened(testCase.shortened, invalidPaths, maxLength)
+			if testCase.expected == nil {
+				if err != nil {
+					t.Fatalf("expected no error, got %s", err.Error())
+				}
+				return
+			}
+			if !errors.Is(err, testCase.expected) {
+				t.Fatalf("expected %v, got %v", testCase.expected, err)
+			}
+		})
+	}
+}
@@ -2,8 +2,10 @@ package endpoints
 
 import (
 	"errors"
+	"time"
 
 	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
 	"go.uber.org/zap"
 
 	"github.com/MicahParks/terseurl/configure"
@@ -13,23 +15,47 @@ import (
 )
 
 // HandleVisitsRead creates and /api/visits/{shortened} endpoint handler via a closure. It can perform exports of a
-// single shortened URL's Visits data.
+// single shortened URL's Visits data. The Visits data can be filtered by time, IP address, and header.
 func HandleVisitsRead(logger *zap.SugaredLogger, manager storage.StoreManager) api.VisitsReadHandlerFunc {
 	return func(params api.VisitsReadParams, principal *models.Principal) middleware.Responder {
 
 		// Log the event.
 		logger.Debugw("Reading shortened URL Visits data.",
+			"from", params.From,
+			"headerContains", params.HeaderContains,
+			"headerName", params.HeaderName,
+			"ip", params.IP,
+			"limit", params.Limit,
 			"shortened", params.ShortenedURLs,
+			"to", params.To,
 		)
 
+		// Create the filter for the Visits data.
+		filter, err := visitsFilter(params.From, params.IP, params.To)
+		if err != nil {
+			message := "Invalid IP address or CIDR block."
+			logger.Infow(message,
+				"error", err.Error(),
+			)
+			return ErrorResponse(400, message, &api.VisitsReadDefault{})
+		}
+		if params.HeaderContains != nil {
+			filter.HeaderContains = *params.HeaderContains
+		}
+		if params.HeaderName != nil {
+			filter.HeaderName = *params.HeaderName
+		}
+		if params.Limit != nil {
+			filter.Limit = *params.Limit
+		}
+
 		// Create a request context.
 		ctx, cancel := configure.DefaultCtx()
 		defer cancel()
 
 		// Get the visits from storage.
-		var err error
 		var visits map[string][]models.Visit
-		if visits, err = manager.Visits(ctx, params.ShortenedURLs); err != nil {
+		if visits, err = manager.Visits(ctx, principal, params.ShortenedURLs, filter); err != nil {
 
 			// Log at the appropriate level. Assign the response code and message.
 			var code int
@@ -40,6 +66,12 @@ func HandleVisitsRead(logger *zap.SugaredLogger, manager storage.StoreManager) a
 				logger.Infow(message,
 					"error", err.Error(),
 				)
+			} else if errors.Is(err, storage.ErrUnauthorized) {
+				code = 403
+				message = "Not authorized for the requested shortened URLs."
+				logger.Infow(message,
+					"error", err.Error(),
+				)
 			} else {
 				code = 500
 				message = "Failed to read Visits data for the shortened URLs."
@@ -57,3 +89,21 @@ func HandleVisitsRead(logger *zap.SugaredLogger, manager storage.StoreManager) a
 		}
 	}
 }
+
+// visitsFilter creates a storage.VisitsFilter for the given time window and IP addresses or CIDR blocks.
+func visitsFilter(from *strfmt.DateTime, ips []string, to *strfmt.DateTime) (filter storage.VisitsFilter, err error) {
+	if from != nil {
+		filter.From = time.Time(*from)
+	}
+	for _, ip := range ips {
+		ipNet, err := storage.ParseIPNet(ip)
+		if err != nil {
+			return storage.VisitsFilter{}, err
+		}
+		filter.IPNets = append(filter.IPNets, ipNet)
+	}
+	if to != nil {
+		filter.To = time.Time(*to)
+	}
+	return filter, nil
+}
@@ -2,20 +2,26 @@ package endpoints
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/go-openapi/runtime/middleware"
-	"github.com/teris-io/shortid"
 	"go.uber.org/zap"
 
 	"github.com/MicahParks/terseurl/configure"
+	"github.com/MicahParks/terseurl/generate"
 	"github.com/MicahParks/terseurl/models"
 	"github.com/MicahParks/terseurl/restapi/operations/api"
 	"github.com/MicahParks/terseurl/storage"
 )
 
 // HandleWrite creates and /api/write/{operation} endpoint handler via a closure. It can perform write operations on a
-// single shortened URL's Terse data.
-func HandleWrite(logger *zap.SugaredLogger, shortID *shortid.Shortid, manager storage.StoreManager) api.TerseWriteHandlerFunc {
+// single shortened URL's Terse data. The generator creates shortened URLs for Terse data without one. In dedupe mode,
+// existing Terse data with the same original URL and redirect type are returned instead of creating a shortened URL, if
+// neither has any other option set.
+// Client given shortened URLs are rejected if they are in invalidPaths, contain a slash or control character, or have
+// more than maxLength characters. Generated shortened URLs are regenerated if they would be rejected.
+func HandleWrite(logger *zap.SugaredLogger, dedupe bool, generator generate.Paranoid, invalidPaths []string, maxLength uint, manager storage.StoreManager) api.TerseWriteHandlerFunc {
 	return func(params api.TerseWriteParams, principal *models.Principal) middleware.Responder {
 
 		// Debug info.
@@ -34,6 +40,8 @@ func HandleWrite(logger *zap.SugaredLogger, shortID *shortid.Shortid, manager st
 
 		// Iterate through the input Terse data.
 		terseMap := make(map[string]*models.Terse)
+		unnamed := make([]*models.Terse, 0)
+		invalid := make([]string, 0)
 		var err error
 		for _, terseInput := range params.Terse {
 
@@ -41,40 +49,143 @@ func HandleWrite(logger *zap.SugaredLogger, shortID *shortid.Shortid, manager st
 
 			// Create the Terse data structure.
 			terse := &models.Terse{
+				ExpiresAt:          terseInput.ExpiresAt,
+				ForwardPath:        terseInput.ForwardPath,
+				ForwardQuery:       terseInput.ForwardQuery,
 				JavascriptTracking: terseInput.JavascriptTracking,
+				MaxVisits:          terseInput.MaxVisits,
 				MediaPreview:       terseInput.MediaPreview,
 				OriginalURL:        terseInput.OriginalURL,
+				QueryConflict:      terseInput.QueryConflict,
+				QueryDefaults:      terseInput.QueryDefaults,
 				RedirectType:       terseInput.RedirectType,
 				ShortenedURL:       terseInput.ShortenedURL,
+				StickyVariants:     terseInput.StickyVariants,
+				Targets:            terseInput.Targets,
+				Variants:           terseInput.Variants,
 			}
 
-			// If no shortened URL was given, create one.
+			// If no shortened URL was given, one is created later. Otherwise, confirm the given one is valid.
 			if terseInput.ShortenedURL == "" {
-				if terse.ShortenedURL, err = shortID.Generate(); err != nil { // TODO Loop this in paranoid mode?
+				unnamed = append(unnamed, terse)
+				continue
+			} else if err = validateShortened(terseInput.ShortenedURL, invalidPaths, maxLength); err != nil {
+				invalid = append(invalid, fmt.Sprintf("%q: %s", terseInput.ShortenedURL, err.Error()))
+				continue
+			}
+
+			// Add the Terse data to the map of Terse data to write.
+			terseMap[terse.ShortenedURL] = terse
+		}
+
+		// Report every invalid shortened URL to the client.
+		if len(invalid) != 0 {
+
+			// Log at the appropriate level.
+			message := fmt.Sprintf("Invalid shortened URLs. %s.", strings.Join(invalid, "; "))
+			logger.Infow("Invalid shortened URLs.",
+				"invalid", invalid,
+			)
+
+			// Report the error to the client.
+			return ErrorResponse(400, message, &api.TerseWriteDefault{})
+		}
 
-					// Log at the appropriate level.
-					message := "Failed to create random shortened URL."
+		// In dedupe mode, find the existing Terse data for the Terse data without a shortened URL.
+		existing := make(map[string]*models.Terse)
+		if dedupe && len(unnamed) != 0 {
+			keys := make([]string, 0, len(unnamed))
+			for _, terse := range unnamed {
+				if storage.Deduplicable(*terse) {
+					keys = append(keys, storage.DedupeKey(terse.OriginalURL, terse.RedirectType))
+				}
+			}
+			if existing, err = manager.Deduplicate(ctx, principal, keys); err != nil {
+
+				// Log at the appropriate level.
+				message := "Failed to find existing Terse data."
+				logger.Errorw(message,
+					"error", err.Error(),
+				)
+
+				// Report the error to the client.
+				return ErrorResponse(500, message, &api.TerseWriteDefault{})
+			}
+		}
+
+		// The shortened URLs in the request are already in use.
+		pending := make(map[string]*models.Terse, len(terseMap))
+		for shortened, terse := range terseMap {
+			pending[shortened] = terse
+		}
+
+		// Create a shortened URL for the Terse data without one, unless existing Terse data can be reused.
+		deduped := make(map[string]*models.Terse)
+		for _, terse := range unnamed {
+
+			// Reuse the existing Terse data, if found.
+			key := storage.DedupeKey(terse.OriginalURL, terse.RedirectType)
+			deduplicable := storage.Deduplicable(*terse)
+			if dedupe && deduplicable {
+				if existingTerse, ok := existing[key]; ok {
+					deduped[existingTerse.ShortenedURL] = existingTerse
+					continue
+				}
+			}
+
+			// Create the shortened URL. It must pass the same validation as client given shortened URLs.
+			var reused *models.Terse
+			if terse.ShortenedURL, reused, err = generator.Generate(ctx, principal, pending, terse, func(shortened string) error {
+				return validateShortened(shortened, invalidPaths, maxLength)
+			}); err != nil {
+
+				// Log at the appropriate level. Assign the response code and message.
+				var code int
+				var message string
+				if errors.Is(err, generate.ErrMaxAttempts) {
+					code = 503
+					message = "Failed to create an unused random shortened URL. Try again or provide a shortened URL."
+					logger.Warnw(message,
+						"error", err.Error(),
+					)
+				} else {
+					code = 500
+					message = "Failed to create random shortened URL."
 					logger.Errorw(message,
 						"error", err.Error(),
 					)
+				}
 
-					// Report the error to the client.
-					return ErrorResponse(500, message, &api.TerseWriteDefault{})
+				// Report the error to the client.
+				return ErrorResponse(code, message, &api.TerseWriteDefault{})
+			}
+
+			// Reuse the existing Terse data, if the generator found it.
+			if reused != nil {
+				if _, ok := terseMap[reused.ShortenedURL]; !ok {
+					deduped[reused.ShortenedURL] = reused
 				}
+				existing[key] = reused
+				continue
 			}
 
-			// Add the Terse data to the map of Terse data to write.
+			// Add the Terse data to the map of Terse data to write. Later Terse data in the request with the same dedupe
+			// key will reuse it, if it can be deduplicated.
+			pending[terse.ShortenedURL] = terse
 			terseMap[terse.ShortenedURL] = terse
+			if deduplicable {
+				existing[key] = terse
+			}
 		}
 
 		// Decide which operation to do.
 		switch params.Operation {
 		case "insert":
-			err = manager.WriteTerse(ctx, terseMap, storage.Insert)
+			err = manager.WriteTerse(ctx, principal, terseMap, storage.Insert)
 		case "update":
-			err = manager.WriteTerse(ctx, terseMap, storage.Update)
+			err = manager.WriteTerse(ctx, principal, terseMap, storage.Update)
 		case "upsert":
-			err = manager.WriteTerse(ctx, terseMap, storage.Upsert)
+			err = manager.WriteTerse(ctx, principal, terseMap, storage.Upsert)
 		}
 
 		// Check for an error when writing to the TerseStore.
@@ -95,6 +206,12 @@ func HandleWrite(logger *zap.SugaredLogger, shortID *shortid.Shortid, manager st
 				logger.Infow(message,
 					"error", err.Error(),
 				)
+			} else if errors.Is(err, storage.ErrUnauthorized) {
+				code = 403
+				message = "Not going to overwrite shortened URL owned by another user."
+				logger.Infow(message,
+					"error", err.Error(),
+				)
 			} else {
 				code = 500
 				message = "Failed to write Terse."
@@ -107,6 +224,11 @@ func HandleWrite(logger *zap.SugaredLogger, shortID *shortid.Shortid, manager st
 			return ErrorResponse(code, message, &api.TerseWriteDefault{})
 		}
 
+		// Include the reused Terse data in the response.
+		for shortened, terse := range deduped {
+			terseMap[shortened] = terse
+		}
+
 		return &api.TerseWriteOK{
 			Payload: terseMap,
 		}
@@ -5,7 +5,7 @@ async function summarize(shortenedURLs) {
         reason => console.error('failed to load the spec: ' + reason)
     )
         .then(
-            shortenedSummaryResult => resultPromise = JSON.parse(shortenedSummaryResult.data),
+            shortenedSummaryResult => resultPromise = JSON.parse(shortenedSummaryResult.data).summaries,
             reason => console.error('failed on api call: ' + reason)
         );
     await promise;
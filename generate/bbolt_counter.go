@@ -0,0 +1,59 @@
+package generate
+
+import (
+	"context"
+	"math/big"
+
+	"go.etcd.io/bbolt"
+
+	"github.com/MicahParks/terseurl/models"
+)
+
+// BboltCounter is a Generator implementation that encodes a monotonic counter persisted in a bbolt file. The shortened
+// URLs are as short as possible and never repeat, as long as the bbolt file is kept.
+type BboltCounter struct {
+	alphabet      []rune
+	counterBucket []byte
+	db            *bbolt.DB
+	length        uint
+}
+
+// NewBboltCounter creates a new BboltCounter given the required assets. If the alphabet is empty, base62 is used. The
+// shortened URLs are left padded to have at least length characters.
+func NewBboltCounter(alphabet string, db *bbolt.DB, counterBucket []byte, length uint) (generator Generator) {
+
+	// Use the default alphabet, if none was given.
+	if alphabet == "" {
+		alphabet = alphabetBase62
+	}
+
+	return BboltCounter{
+		alphabet:      []rune(alphabet),
+		counterBucket: counterBucket,
+		db:            db,
+		length:        length,
+	}
+}
+
+// Close closes the connection to the underlying storage.
+func (b BboltCounter) Close(_ context.Context) (err error) {
+
+	// Close the bbolt database file.
+	return b.db.Close()
+}
+
+// Generate increments the counter and encodes it as a shortened URL. The attempt and Terse data are not used because
+// every call creates a new shortened URL.
+func (b BboltCounter) Generate(_ context.Context, _ uint, _ *models.Terse) (shortened string, err error) {
+
+	// Increment the counter in the bbolt database.
+	var count uint64
+	if err = b.db.Update(func(tx *bbolt.Tx) (err error) {
+		count, err = tx.Bucket(b.counterBucket).NextSequence()
+		return err
+	}); err != nil {
+		return "", err
+	}
+
+	return encode(b.alphabet, b.length, new(big.Int).SetUint64(count)), nil
+}
@@ -0,0 +1,48 @@
+package generate
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+)
+
+// TestBboltCounter_Generate confirms the counter creates left padded shortened URLs in order and keeps counting after
+// the bbolt file is reopened.
+func TestBboltCounter_Generate(t *testing.T) {
+	ctx := context.Background()
+	configJSON, err := json.Marshal(configuration{
+		Type:      generatorCounter,
+		BboltPath: filepath.Join(t.TempDir(), "counter.bbolt"),
+		Length:    4,
+	})
+	if err != nil {
+		t.Fatalf("failed to create configuration: %s", err.Error())
+	}
+
+	// Generate shortened URLs, reopening the bbolt file part way through.
+	expected := []string{"0001", "0002", "0003", "0004"}
+	shortenedURLs := make([]string, 0, len(expected))
+	for _, count := range []int{2, 2} {
+		generator, _, err := NewGenerator(configJSON, 0)
+		if err != nil {
+			t.Fatalf("failed to create generator: %s", err.Error())
+		}
+		for i := 0; i < count; i++ {
+			shortened, err := generator.Generate(ctx, 1, nil)
+			if err != nil {
+				t.Fatalf("failed to generate shortened URL: %s", err.Error())
+			}
+			shortenedURLs = append(shortenedURLs, shortened)
+		}
+		if err = generator.Close(ctx); err != nil {
+			t.Fatalf("failed to close generator: %s", err.Error())
+		}
+	}
+
+	for i, shortened := range shortenedURLs {
+		if shortened != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, shortenedURLs)
+		}
+	}
+}
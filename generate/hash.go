@@ -0,0 +1,62 @@
+package generate
+
+import (
+	"context"
+	"crypto/sha256"
+	"math/big"
+	"strconv"
+
+	"github.com/MicahParks/terseurl/models"
+)
+
+// Hash is a Generator implementation that creates shortened URLs from a hash of the original URL and redirect type.
+// Shortening the same Terse data again gives the same shortened URL.
+type Hash struct {
+	alphabet []rune
+	length   uint
+}
+
+// NewHash creates a new Hash given the required assets. If the alphabet is empty, base62 is used. If length is zero, a
+// default length is used.
+func NewHash(alphabet string, length uint) (generator Generator) {
+
+	// Use the default alphabet, if none was given.
+	if alphabet == "" {
+		alphabet = alphabetBase62
+	}
+
+	// Use the default length, if none was given.
+	if length == 0 {
+		length = defaultHashLength
+	}
+
+	return Hash{
+		alphabet: []rune(alphabet),
+		length:   length,
+	}
+}
+
+// Close closes the connection to any underlying storage. There is no underlying storage for the Hash.
+func (h Hash) Close(_ context.Context) (err error) {
+	return nil
+}
+
+// Generate hashes the original URL and redirect type of the Terse data and encodes the hash as a shortened URL. After
+// the first attempt, the attempt is added to the hash so a different shortened URL is created.
+func (h Hash) Generate(_ context.Context, attempt uint, terse *models.Terse) (shortened string, err error) {
+
+	// Hash the Terse data. The null byte separates the values so they cannot be confused.
+	hash := sha256.New()
+	hash.Write([]byte(terse.RedirectType))
+	hash.Write([]byte{0})
+	hash.Write([]byte(terse.OriginalURL))
+	if attempt > 1 {
+		hash.Write([]byte{0})
+		hash.Write([]byte(strconv.FormatUint(uint64(attempt), 10)))
+	}
+
+	// Encode the hash in the alphabet and only keep the required number of characters.
+	encoded := []rune(encode(h.alphabet, h.length, new(big.Int).SetBytes(hash.Sum(nil))))
+
+	return string(encoded[:h.length]), nil
+}
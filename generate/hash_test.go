@@ -0,0 +1,73 @@
+package generate
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/MicahParks/terseurl/models"
+)
+
+// TestHash_Generate confirms the same Terse data always gives the same shortened URL and different Terse data or
+// attempts give different ones.
+func TestHash_Generate(t *testing.T) {
+	ctx := context.Background()
+	generator := NewHash("", 0)
+	terse := &models.Terse{OriginalURL: "https://example.com", RedirectType: models.RedirectTypeNr302}
+
+	// Create the shortened URL to compare against.
+	shortened, err := generator.Generate(ctx, 1, terse)
+	if err != nil {
+		t.Fatalf("failed to generate shortened URL: %s", err.Error())
+	}
+	if length := uint(len(shortened)); length != defaultHashLength {
+		t.Fatalf("expected %d characters, got %d", defaultHashLength, length)
+	}
+	for _, r := range shortened {
+		if !strings.ContainsRune(alphabetBase62, r) {
+			t.Fatalf("unexpected character %q in %q", r, shortened)
+		}
+	}
+
+	testCases := []struct {
+		name      string
+		generator Generator
+		attempt   uint
+		terse     *models.Terse
+		same      bool
+	}{
+		{name: "same", generator: generator, attempt: 1, terse: terse, same: true},
+		{name: "new generator", generator: NewHash("", 0), attempt: 1, terse: terse, same: true},
+		{name: "other fields ignored", generator: generator, attempt: 1, terse: &models.Terse{OriginalURL: terse.OriginalURL, RedirectType: terse.RedirectType, MaxVisits: 1}, same: true},
+		{name: "second attempt", generator: generator, attempt: 2, terse: terse},
+		{name: "third attempt", generator: generator, attempt: 3, terse: terse},
+		{name: "redirect type", generator: generator, attempt: 1, terse: &models.Terse{OriginalURL: terse.OriginalURL, RedirectType: models.RedirectTypeNr301}},
+		{name: "original URL", generator: generator, attempt: 1, terse: &models.Terse{OriginalURL: "https://example.org", RedirectType: terse.RedirectType}},
+		{name: "separated values", generator: generator, attempt: 1, terse: &models.Terse{OriginalURL: "2https://example.com", RedirectType: "30"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			generated, err := testCase.generator.Generate(ctx, testCase.attempt, testCase.terse)
+			if err != nil {
+				t.Fatalf("failed to generate shortened URL: %s", err.Error())
+			}
+			if (generated == shortened) != testCase.same {
+				t.Fatalf("expected same to be %t, got %q and %q", testCase.same, shortened, generated)
+			}
+		})
+	}
+}
+
+// TestHash_GenerateLength confirms the shortened URL is cut to the given length in the given alphabet.
+func TestHash_GenerateLength(t *testing.T) {
+	for _, length := range []uint{1, 4, 16, 43} {
+		shortened, err := NewHash("ab", length).Generate(context.Background(), 1, &models.Terse{OriginalURL: "https://example.com"})
+		if err != nil {
+			t.Fatalf("failed to generate shortened URL: %s", err.Error())
+		}
+		if uint(len(shortened)) != length || strings.Trim(shortened, "ab") != "" {
+			t.Fatalf("expected %d characters of the alphabet, got %q", length, shortened)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package generate
+
+import (
+	"context"
+
+	"github.com/MicahParks/terseurl/models"
+)
+
+// Generator is the shortened URL generation interface. It allows for shortened URLs to be created for Terse data
+// without knowing how they are generated.
+type Generator interface {
+
+	// Close closes the connection to any underlying storage.
+	Close(ctx context.Context) (err error)
+
+	// Generate creates a shortened URL for the given Terse data. The attempt starts at 1 and is incremented every time
+	// the previously generated shortened URL was already in use. Deterministic implementations must use the attempt to
+	// create a different shortened URL.
+	Generate(ctx context.Context, attempt uint, terse *models.Terse) (shortened string, err error)
+}
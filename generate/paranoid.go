@@ -0,0 +1,161 @@
+package generate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+
+	"github.com/MicahParks/terseurl/models"
+	"github.com/MicahParks/terseurl/storage"
+)
+
+var (
+
+	// ErrMaxAttempts indicates that a valid and unused shortened URL could not be generated within the maximum number of
+	// attempts.
+	ErrMaxAttempts = errors.New("could not generate a valid and unused shortened URL within the maximum number of attempts")
+)
+
+// CollisionMetrics holds the counts of what happened while generating shortened URLs in paranoid mode.
+type CollisionMetrics struct {
+
+	// Collisions is the number of generated shortened URLs that were already in use.
+	Collisions uint64
+
+	// Exhausted is the number of times a valid and unused shortened URL could not be generated within the maximum number
+	// of attempts.
+	Exhausted uint64
+
+	// Generated is the number of unused shortened URLs that were generated.
+	Generated uint64
+}
+
+// Paranoid wraps a Generator. Every generated shortened URL is validated and regenerated if it is not valid, like a
+// reserved path. In paranoid mode, every generated shortened URL is also checked against the TerseStore and regenerated
+// if it is already in use. Deterministic Generators give the same shortened URL for the same Terse data, so their
+// shortened URLs are always checked and existing duplicate Terse data is reused.
+type Paranoid struct {
+	collisions    *uint64
+	deterministic bool
+	exhausted     *uint64
+	generated     *uint64
+	generator     Generator
+	logger        *zap.SugaredLogger
+	manager       storage.StoreManager
+	maxAttempts   uint
+	paranoid      bool
+}
+
+// NewParanoid creates a new Paranoid given the required assets. If paranoid is false, generated shortened URLs are not
+// checked against the TerseStore.
+func NewParanoid(generator Generator, logger *zap.SugaredLogger, manager storage.StoreManager, maxAttempts uint, paranoid bool) (paranoidGenerator Paranoid) {
+	_, deterministic := generator.(Hash)
+	return Paranoid{
+		collisions:    new(uint64),
+		deterministic: deterministic,
+		exhausted:     new(uint64),
+		generated:     new(uint64),
+		generator:     generator,
+		logger:        logger,
+		manager:       manager,
+		maxAttempts:   maxAttempts,
+		paranoid:      paranoid,
+	}
+}
+
+// Close closes the connection to the wrapped Generator's underlying storage.
+func (p Paranoid) Close(ctx context.Context) (err error) {
+	return p.generator.Close(ctx)
+}
+
+// Generate creates a shortened URL for the given Terse data with the wrapped Generator. The valid function must return
+// an error for shortened URLs that cannot be used, like reserved paths. The pending Terse data are treated as in use, so
+// a batch of writes will not contain a collision. If the Generator is deterministic and the shortened URL already has
+// duplicate Terse data that can be reused by the principal, that Terse data is returned as reused and should not be
+// written again. See storage.Duplicate. The error will be ErrMaxAttempts if no valid shortened URL could be found or, in paranoid
+// mode, no valid and unused shortened URL could be found.
+func (p Paranoid) Generate(ctx context.Context, principal *models.Principal, pending map[string]*models.Terse, terse *models.Terse, valid func(shortened string) (err error)) (shortened string, reused *models.Terse, err error) {
+
+	// Keep generating shortened URLs until a valid and unused one is found.
+	var exists bool
+	for attempt := uint(1); attempt <= p.maxAttempts; attempt++ {
+
+		// Generate a shortened URL.
+		if shortened, err = p.generator.Generate(ctx, attempt, terse); err != nil {
+			return "", nil, err
+		}
+
+		// Generate another shortened URL if this one is not valid.
+		if err = valid(shortened); err != nil {
+			p.logger.Warnw("Generated shortened URL is not valid.",
+				"attempt", attempt,
+				"error", err.Error(),
+				"shortenedURL", shortened,
+			)
+			continue
+		}
+
+		// If not in paranoid mode, use any valid shortened URL that is not from a deterministic Generator.
+		if !p.paranoid && !p.deterministic {
+			return shortened, nil, nil
+		}
+
+		// Check if the shortened URL is in use. For a deterministic Generator, reuse duplicate Terse data.
+		var pendingTerse *models.Terse
+		if pendingTerse, exists = pending[shortened]; exists {
+			if p.deterministic && storage.Duplicate(*pendingTerse, *terse) {
+				return shortened, pendingTerse, nil
+			}
+		} else if p.deterministic {
+			if reused, exists, err = p.manager.Reusable(ctx, principal, shortened, terse); err != nil {
+				return "", nil, err
+			}
+			if reused != nil {
+				return shortened, reused, nil
+			}
+		} else if exists, err = p.manager.ShortenedExists(ctx, shortened); err != nil {
+			return "", nil, err
+		}
+
+		// If not in paranoid mode, use the shortened URL, even if it is in use.
+		if !p.paranoid {
+			return shortened, nil, nil
+		}
+
+		// Use the shortened URL if it is not in use.
+		if !exists {
+			atomic.AddUint64(p.generated, 1)
+			return shortened, nil, nil
+		}
+
+		// Keep track of the collision.
+		collisions := atomic.AddUint64(p.collisions, 1)
+		p.logger.Warnw("Generated shortened URL already in use.",
+			"attempt", attempt,
+			"collisions", collisions,
+			"shortenedURL", shortened,
+		)
+	}
+
+	// Keep track of running out of attempts.
+	exhausted := atomic.AddUint64(p.exhausted, 1)
+	p.logger.Errorw("Ran out of attempts to generate a valid and unused shortened URL.",
+		"exhausted", exhausted,
+		"maxAttempts", p.maxAttempts,
+	)
+
+	return "", nil, fmt.Errorf("%w: %d", ErrMaxAttempts, p.maxAttempts)
+}
+
+// Metrics returns the counts of what happened while generating shortened URLs. Only the exhausted count is kept track of
+// outside of paranoid mode.
+func (p Paranoid) Metrics() (metrics CollisionMetrics) {
+	return CollisionMetrics{
+		Collisions: atomic.LoadUint64(p.collisions),
+		Exhausted:  atomic.LoadUint64(p.exhausted),
+		Generated:  atomic.LoadUint64(p.generated),
+	}
+}
@@ -0,0 +1,168 @@
+package generate
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/MicahParks/ctxerrgroup"
+	"go.uber.org/zap"
+
+	"github.com/MicahParks/terseurl/models"
+	"github.com/MicahParks/terseurl/storage"
+)
+
+// sequence is a Generator that creates the shortened URL at the index of the attempt.
+type sequence []string
+
+// Close closes the connection to any underlying storage. There is no underlying storage for the sequence.
+func (s sequence) Close(_ context.Context) (err error) {
+	return nil
+}
+
+// Generate returns the shortened URL for the attempt.
+func (s sequence) Generate(_ context.Context, attempt uint, _ *models.Terse) (shortened string, err error) {
+	return s[attempt-1], nil
+}
+
+// reserved is a validation function that rejects the shortened URL "api".
+func reserved(shortened string) (err error) {
+	if shortened == "api" {
+		return errors.New("reserved")
+	}
+	return nil
+}
+
+// newTestManager creates a StoreManager whose TerseStore has the given Terse data.
+func newTestManager(t *testing.T, terseData map[string]*models.Terse) (manager storage.StoreManager) {
+	terseStore := storage.NewMemTerse()
+	if err := terseStore.Write(context.Background(), terseData, storage.Insert); err != nil {
+		t.Fatalf("failed to write Terse data: %s", err.Error())
+	}
+	return storage.NewStoreManager(nil, nil, false, nil, ctxerrgroup.Group{}, nil, terseStore, nil, nil)
+}
+
+// TestParanoid_Generate confirms invalid shortened URLs are always regenerated, shortened URLs in use are only
+// regenerated in paranoid mode, and ErrMaxAttempts is returned when the attempts run out.
+func TestParanoid_Generate(t *testing.T) {
+	testCases := []struct {
+		name       string
+		generator  sequence
+		paranoid   bool
+		pending    []string
+		expected   string
+		err        error
+		collisions uint64
+		exhausted  uint64
+		generated  uint64
+	}{
+		{name: "first", generator: sequence{"a", "b", "c"}, expected: "a"},
+		{name: "reserved", generator: sequence{"api", "b", "c"}, expected: "b"},
+		{name: "all reserved", generator: sequence{"api", "api", "api"}, err: ErrMaxAttempts, exhausted: 1},
+		{name: "in use", generator: sequence{"used", "b", "c"}, expected: "used"},
+		{name: "paranoid first", generator: sequence{"a", "b", "c"}, paranoid: true, expected: "a", generated: 1},
+		{name: "paranoid reserved", generator: sequence{"api", "b", "c"}, paranoid: true, expected: "b", generated: 1},
+		{name: "paranoid in use", generator: sequence{"used", "b", "c"}, paranoid: true, expected: "b", collisions: 1, generated: 1},
+		{name: "paranoid pending", generator: sequence{"pending", "used", "c"}, paranoid: true, pending: []string{"pending"}, expected: "c", collisions: 2, generated: 1},
+		{name: "paranoid all in use", generator: sequence{"used", "api", "pending"}, paranoid: true, pending: []string{"pending"}, err: ErrMaxAttempts, collisions: 2, exhausted: 1},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			manager := newTestManager(t, map[string]*models.Terse{"used": {OriginalURL: "https://example.com", ShortenedURL: "used"}})
+			generator := NewParanoid(testCase.generator, zap.NewNop().Sugar(), manager, uint(len(testCase.generator)), testCase.paranoid)
+
+			pending := make(map[string]*models.Terse)
+			for _, shortened := range testCase.pending {
+				pending[shortened] = &models.Terse{OriginalURL: "https://example.com", ShortenedURL: shortened}
+			}
+
+			shortened, reused, err := generator.Generate(context.Background(), nil, pending, &models.Terse{OriginalURL: "https://example.com"}, reserved)
+			if testCase.err != nil {
+				if !errors.Is(err, testCase.err) {
+					t.Fatalf("expected %v, got %v", testCase.err, err)
+				}
+			} else if err != nil {
+				t.Fatalf("failed to generate shortened URL: %s", err.Error())
+			}
+			if shortened != testCase.expected {
+				t.Fatalf("expected %q, got %q", testCase.expected, shortened)
+			}
+			if reused != nil {
+				t.Fatalf("expected nothing reused, got %q", reused.ShortenedURL)
+			}
+
+			expected := CollisionMetrics{
+				Collisions: testCase.collisions,
+				Exhausted:  testCase.exhausted,
+				Generated:  testCase.generated,
+			}
+			if metrics := generator.Metrics(); metrics != expected {
+				t.Fatalf("expected metrics %+v, got %+v", expected, metrics)
+			}
+		})
+	}
+}
+
+// TestParanoid_GenerateDeterministic confirms duplicate Terse data from a deterministic Generator is reused, with or
+// without paranoid mode, and Terse data that is not a duplicate is a collision.
+func TestParanoid_GenerateDeterministic(t *testing.T) {
+	ctx := context.Background()
+	hash := NewHash("", 0)
+	terse := &models.Terse{OriginalURL: "https://example.com", RedirectType: models.RedirectTypeNr302}
+	first, err := hash.Generate(ctx, 1, terse)
+	if err != nil {
+		t.Fatalf("failed to generate shortened URL: %s", err.Error())
+	}
+	second, err := hash.Generate(ctx, 2, terse)
+	if err != nil {
+		t.Fatalf("failed to generate shortened URL: %s", err.Error())
+	}
+
+	testCases := []struct {
+		name     string
+		paranoid bool
+		existing *models.Terse
+		pending  bool
+		expected string
+		reused   bool
+	}{
+		{name: "unused", expected: first},
+		{name: "existing", existing: &models.Terse{OriginalURL: terse.OriginalURL, RedirectType: terse.RedirectType}, expected: first, reused: true},
+		{name: "paranoid existing", paranoid: true, existing: &models.Terse{OriginalURL: terse.OriginalURL, RedirectType: terse.RedirectType}, expected: first, reused: true},
+		{name: "pending", pending: true, existing: &models.Terse{OriginalURL: terse.OriginalURL, RedirectType: terse.RedirectType}, expected: first, reused: true},
+		{name: "existing with option", existing: &models.Terse{OriginalURL: terse.OriginalURL, RedirectType: terse.RedirectType, MaxVisits: 1}, expected: first},
+		{name: "paranoid existing with option", paranoid: true, existing: &models.Terse{OriginalURL: terse.OriginalURL, RedirectType: terse.RedirectType, MaxVisits: 1}, expected: second},
+		{name: "paranoid existing other redirect type", paranoid: true, existing: &models.Terse{OriginalURL: terse.OriginalURL, RedirectType: models.RedirectTypeNr301}, expected: second},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			terseData := make(map[string]*models.Terse)
+			pending := make(map[string]*models.Terse)
+			if testCase.existing != nil {
+				testCase.existing.ShortenedURL = first
+				if testCase.pending {
+					pending[first] = testCase.existing
+				} else {
+					terseData[first] = testCase.existing
+				}
+			}
+			generator := NewParanoid(hash, zap.NewNop().Sugar(), newTestManager(t, terseData), 2, testCase.paranoid)
+
+			shortened, reused, err := generator.Generate(ctx, nil, pending, terse, reserved)
+			if err != nil {
+				t.Fatalf("failed to generate shortened URL: %s", err.Error())
+			}
+			if shortened != testCase.expected {
+				t.Fatalf("expected %q, got %q", testCase.expected, shortened)
+			}
+			if (reused != nil) != testCase.reused {
+				t.Fatalf("expected reused to be %t, got %v", testCase.reused, reused)
+			}
+			if reused != nil && reused.ShortenedURL != first {
+				t.Fatalf("expected %q to be reused, got %q", first, reused.ShortenedURL)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package generate
+
+import (
+	"context"
+
+	"github.com/teris-io/shortid"
+
+	"github.com/MicahParks/terseurl/models"
+)
+
+// ShortID is a Generator implementation that creates random shortened URLs with the teris-io/shortid package. The
+// length of the shortened URLs is decided by the package.
+type ShortID struct {
+	shortID *shortid.Shortid
+}
+
+// NewShortID creates a new ShortID given the required assets. The alphabet must have exactly 64 unique characters. If
+// the alphabet is empty, the package's default alphabet is used.
+func NewShortID(alphabet string, seed uint64) (generator Generator, err error) {
+
+	// Use the default alphabet, if none was given.
+	if alphabet == "" {
+		alphabet = shortid.DefaultABC
+	}
+
+	// Create the short ID generator.
+	var shortID *shortid.Shortid
+	if shortID, err = shortid.New(1, alphabet, seed); err != nil { // TODO Configure worker count?
+		return nil, err
+	}
+
+	return ShortID{
+		shortID: shortID,
+	}, nil
+}
+
+// Close closes the connection to any underlying storage. There is no underlying storage for the ShortID.
+func (s ShortID) Close(_ context.Context) (err error) {
+	return nil
+}
+
+// Generate creates a random shortened URL. The attempt and Terse data are not used.
+func (s ShortID) Generate(_ context.Context, _ uint, _ *models.Terse) (shortened string, err error) {
+	return s.shortID.Generate()
+}
@@ -0,0 +1,195 @@
+package generate
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"unicode"
+
+	"go.etcd.io/bbolt"
+)
+
+const (
+
+	// alphabetBase62 is the default alphabet for generators that encode numbers.
+	alphabetBase62 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+	// defaultCounterBboltPath is the default bbolt file to persist the counter in.
+	defaultCounterBboltPath = "counter.bbolt"
+
+	// defaultHashLength is the default number of characters for a shortened URL created from a hash.
+	defaultHashLength = 8
+
+	// defaultWordsLength is the default number of words for a shortened URL created from a wordlist.
+	defaultWordsLength = 3
+
+	// generatorCounter is the constant used when describing a monotonic counter generator persisted in bbolt.
+	generatorCounter = "counter"
+
+	// generatorHash is the constant used when describing a deterministic hash generator.
+	generatorHash = "hash"
+
+	// generatorShortID is the constant used when describing a random short ID generator.
+	generatorShortID = "shortid"
+
+	// generatorWords is the constant used when describing a random wordlist generator.
+	generatorWords = "words"
+)
+
+var (
+
+	// ErrInvalidAlphabet indicates the alphabet has too few characters, repeated characters, or characters that cannot be
+	// in a shortened URL.
+	ErrInvalidAlphabet = errors.New("the alphabet must have at least two unique characters and no slashes, control characters, or whitespace")
+
+	// bboltCounterBucket is the bbolt bucket to use for the counter.
+	bboltCounterBucket = []byte("terseCounter")
+)
+
+// configuration represents the configuration data gathered from the user to create a generator.
+type configuration struct {
+	Type         string `json:"type"`
+	Alphabet     string `json:"alphabet"`
+	BboltPath    string `json:"bboltPath"`
+	Length       uint   `json:"length"`
+	Separator    string `json:"separator"`
+	WordlistPath string `json:"wordlistPath"`
+}
+
+// NewGenerator creates a new Generator from the given configJSON. The seed is only used by the short ID generator. The
+// generatorType return value is used for logging.
+func NewGenerator(configJSON json.RawMessage, seed uint64) (generator Generator, generatorType string, err error) {
+
+	// Create the configuration.
+	config := &configuration{}
+
+	// If no JSON was given, use the short ID generator.
+	if len(configJSON) == 0 {
+		config.Type = generatorShortID
+	} else {
+
+		// Turn the configuration JSON into a Go structure.
+		if err = json.Unmarshal(configJSON, config); err != nil {
+			return nil, "", err
+		}
+	}
+
+	// Confirm the alphabet can be used, if given.
+	if config.Alphabet != "" {
+		if err = validateAlphabet(config.Alphabet); err != nil {
+			return nil, "", err
+		}
+	}
+
+	// Create the appropriate Generator.
+	switch config.Type {
+
+	// Use a monotonic counter persisted in a bbolt file.
+	case generatorCounter:
+
+		// Use the default bbolt file, if none was given.
+		if config.BboltPath == "" {
+			config.BboltPath = defaultCounterBboltPath
+		}
+
+		// Open the bbolt database file.
+		var db *bbolt.DB
+		if db, err = bbolt.Open(config.BboltPath, 0666, nil); err != nil {
+			return nil, "", err
+		}
+
+		// Create the bucket.
+		if err = db.Update(func(tx *bbolt.Tx) error {
+			_, err := tx.CreateBucketIfNotExists(bboltCounterBucket)
+			return err
+		}); err != nil {
+			return nil, "", err
+		}
+
+		// Assign the interface implementation.
+		generator = NewBboltCounter(config.Alphabet, db, bboltCounterBucket, config.Length)
+
+	// Use a deterministic hash of the original URL.
+	case generatorHash:
+		generator = NewHash(config.Alphabet, config.Length)
+
+	// Use random words from a wordlist.
+	case generatorWords:
+
+		// Read the wordlist, if given.
+		var words []string
+		if config.WordlistPath != "" {
+			if words, err = readWordlist(config.WordlistPath); err != nil {
+				return nil, "", err
+			}
+		}
+
+		// Assign the interface implementation.
+		if generator, err = NewWords(config.Length, config.Separator, words); err != nil {
+			return nil, "", err
+		}
+
+	// Use a random short ID generator by default.
+	default:
+		config.Type = generatorShortID
+		if generator, err = NewShortID(config.Alphabet, seed); err != nil {
+			return nil, "", err
+		}
+	}
+
+	return generator, config.Type, nil
+}
+
+// encode encodes the given number in the given alphabet, most significant character first. The result is left padded
+// with the first character of the alphabet until it has at least length characters.
+func encode(alphabet []rune, length uint, number *big.Int) (encoded string) {
+
+	// Repeatedly divide the number by the base to get each character, least significant first.
+	base := big.NewInt(int64(len(alphabet)))
+	remainder := new(big.Int)
+	n := new(big.Int).Set(number)
+	runes := make([]rune, 0, length)
+	for n.Sign() > 0 {
+		n.DivMod(n, base, remainder)
+		runes = append(runes, alphabet[remainder.Int64()])
+	}
+
+	// Pad the characters to the required length.
+	for uint(len(runes)) < length {
+		runes = append(runes, alphabet[0])
+	}
+
+	// Reverse the characters so the most significant is first.
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+
+	return string(runes)
+}
+
+// validateAlphabet confirms the alphabet has at least two unique characters and none of them are slashes, control
+// characters, or whitespace.
+func validateAlphabet(alphabet string) (err error) {
+
+	// Confirm every character is unique and usable.
+	seen := make(map[rune]struct{})
+	for _, r := range alphabet {
+		if _, ok := seen[r]; ok || unusable(r) {
+			return ErrInvalidAlphabet
+		}
+		seen[r] = struct{}{}
+	}
+
+	// Confirm there are enough characters to encode a number.
+	if len(seen) < 2 {
+		return ErrInvalidAlphabet
+	}
+
+	return nil
+}
+
+// unusable determines if the character cannot be in a generated shortened URL. Slashes, control characters, and
+// whitespace are unusable.
+func unusable(r rune) bool {
+	return r == '/' || r == '\\' || unicode.IsControl(r) || unicode.IsSpace(r)
+}
@@ -0,0 +1,41 @@
+package generate
+
+import (
+	"math/big"
+	"testing"
+)
+
+// TestEncode confirms numbers are encoded in the alphabet, most significant character first, and left padded to the
+// length.
+func TestEncode(t *testing.T) {
+	testCases := []struct {
+		name     string
+		alphabet string
+		length   uint
+		number   uint64
+		expected string
+	}{
+		{name: "zero", alphabet: alphabetBase62, number: 0, expected: ""},
+		{name: "zero padded", alphabet: alphabetBase62, length: 3, number: 0, expected: "000"},
+		{name: "one", alphabet: alphabetBase62, number: 1, expected: "1"},
+		{name: "last character", alphabet: alphabetBase62, number: 61, expected: "z"},
+		{name: "carry", alphabet: alphabetBase62, number: 62, expected: "10"},
+		{name: "carry padded", alphabet: alphabetBase62, length: 4, number: 62, expected: "0010"},
+		{name: "longer than length", alphabet: alphabetBase62, length: 2, number: 62 * 62, expected: "100"},
+		{name: "max uint64", alphabet: alphabetBase62, number: ^uint64(0), expected: "LygHa16AHYF"},
+		{name: "binary", alphabet: "ab", number: 5, expected: "bab"},
+		{name: "unicode", alphabet: "αβγ", length: 3, number: 5, expected: "αβγ"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			encoded := encode([]rune(testCase.alphabet), testCase.length, new(big.Int).SetUint64(testCase.number))
+			if encoded != testCase.expected {
+				t.Fatalf("expected %q, got %q", testCase.expected, encoded)
+			}
+			if length := uint(len([]rune(encoded))); length < testCase.length {
+				t.Fatalf("expected at least %d characters, got %d", testCase.length, length)
+			}
+		})
+	}
+}
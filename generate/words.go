@@ -0,0 +1,137 @@
+package generate
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/MicahParks/terseurl/models"
+)
+
+const (
+
+	// defaultWordsSeparator is the default string between the words of a shortened URL.
+	defaultWordsSeparator = "-"
+)
+
+var (
+
+	// ErrInvalidWordlist indicates the wordlist has too few unique words or a word that cannot be in a shortened URL.
+	ErrInvalidWordlist = errors.New("the wordlist must have at least two unique words and no slashes, control characters, or whitespace")
+
+	// defaultWordlist is the wordlist used when none is given. The words are short and easy to pronounce.
+	defaultWordlist = []string{
+		"acorn", "amber", "anchor", "apple", "arrow", "aspen", "atlas", "autumn", "badge", "bamboo", "banjo", "basil",
+		"beacon", "berry", "birch", "bison", "blossom", "bramble", "breeze", "brook", "buffalo", "butter", "cabin",
+		"cactus", "camel", "candle", "canyon", "cargo", "cedar", "cello", "cherry", "cider", "citrus", "clover", "cobalt",
+		"comet", "copper", "coral", "cosmos", "cotton", "coyote", "crane", "cricket", "crystal", "daisy", "dawn", "delta",
+		"denim", "desert", "dingo", "dolphin", "domino", "dragon", "dune", "eagle", "echo", "ember", "emerald", "falcon",
+		"fern", "fiddle", "fjord", "flint", "forest", "fossil", "fox", "galaxy", "garnet", "gecko", "ginger", "glacier",
+		"gopher", "granite", "grove", "harbor", "hazel", "heron", "hickory", "honey", "horizon", "iris", "island",
+		"ivory", "jade", "jaguar", "jasper", "jelly", "juniper", "kayak", "kettle", "kiwi", "koala", "lagoon", "lantern",
+		"lemon", "lilac", "lotus", "lunar", "magnet", "mango", "maple", "marble", "meadow", "melon", "meteor", "mint",
+		"mocha", "moose", "mosaic", "nectar", "noodle", "nova", "oasis", "ocean", "olive", "onyx", "orbit", "orchid",
+		"otter", "panda", "papaya", "pebble", "pepper", "pine", "planet", "plum", "pollen", "poppy", "prairie", "pumpkin",
+		"quartz", "quill", "rabbit", "radar", "raven", "reef", "river", "robin", "rocket", "ruby", "saddle", "saffron",
+		"salmon", "sapphire", "scarlet", "sequoia", "shadow", "sierra", "silver", "sparrow", "spruce", "summit", "sunset",
+		"tango", "thistle", "thunder", "tiger", "topaz", "tulip", "tundra", "valley", "velvet", "violet", "walnut",
+		"willow", "winter", "yarrow", "zebra", "zephyr",
+	}
+)
+
+// Words is a Generator implementation that creates pronounceable shortened URLs by joining random words from a
+// wordlist.
+type Words struct {
+	length    uint
+	separator string
+	words     []string
+}
+
+// NewWords creates a new Words given the required assets. The length is the number of words in a shortened URL. If
+// length is zero, separator is empty, or words is empty, the defaults are used.
+func NewWords(length uint, separator string, words []string) (generator Generator, err error) {
+
+	// Use the defaults for anything not given.
+	if length == 0 {
+		length = defaultWordsLength
+	}
+	if separator == "" {
+		separator = defaultWordsSeparator
+	}
+	if len(words) == 0 {
+		words = defaultWordlist
+	}
+
+	// Confirm the separator and words can be in a shortened URL.
+	if strings.IndexFunc(separator, unusable) != -1 {
+		return nil, ErrInvalidWordlist
+	}
+	unique := make(map[string]struct{}, len(words))
+	for _, word := range words {
+		if word == "" || strings.IndexFunc(word, unusable) != -1 {
+			return nil, ErrInvalidWordlist
+		}
+		unique[word] = struct{}{}
+	}
+	if len(unique) < 2 {
+		return nil, ErrInvalidWordlist
+	}
+
+	return Words{
+		length:    length,
+		separator: separator,
+		words:     words,
+	}, nil
+}
+
+// Close closes the connection to any underlying storage. There is no underlying storage for the Words.
+func (w Words) Close(_ context.Context) (err error) {
+	return nil
+}
+
+// Generate joins random words from the wordlist into a shortened URL. The attempt and Terse data are not used.
+func (w Words) Generate(_ context.Context, _ uint, _ *models.Terse) (shortened string, err error) {
+
+	// Pick a random word for each position.
+	max := big.NewInt(int64(len(w.words)))
+	chosen := make([]string, w.length)
+	var index *big.Int
+	for i := range chosen {
+		if index, err = rand.Int(rand.Reader, max); err != nil {
+			return "", err
+		}
+		chosen[i] = w.words[index.Int64()]
+	}
+
+	return strings.Join(chosen, w.separator), nil
+}
+
+// readWordlist reads a wordlist file with one word per line. Whitespace prefixes and suffixes are trimmed and empty lines
+// are dropped.
+func readWordlist(filePath string) (words []string, err error) {
+
+	// Open the wordlist file.
+	var file *os.File
+	if file, err = os.Open(filePath); err != nil {
+		return nil, err
+	}
+	defer file.Close() // Ignore any error.
+
+	// Read every line of the file as a word.
+	words = make([]string, 0)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if word := strings.TrimSpace(scanner.Text()); word != "" {
+			words = append(words, word)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return words, nil
+}
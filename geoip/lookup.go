@@ -0,0 +1,89 @@
+package geoip
+
+import (
+	"net"
+
+	"github.com/oschwald/maxminddb-golang"
+
+	"github.com/MicahParks/terseurl/models"
+)
+
+// record is the data looked up in a MaxMind format database. Country databases have the country and ASN databases have
+// the autonomous system number.
+type record struct {
+	ASN     uint64 `maxminddb:"autonomous_system_number"`
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+}
+
+// Locator adds the country and ASN of the client to visits. It looks up IP addresses in local MaxMind format databases,
+// so no network requests are made.
+type Locator struct {
+	readers []*maxminddb.Reader
+}
+
+// NewLocator creates a new Locator from the MaxMind format database files at the given paths. Each database is checked
+// in order, so a country database and an ASN database can be combined.
+func NewLocator(paths []string) (locator *Locator, err error) {
+
+	// Open the databases.
+	locator = &Locator{}
+	for _, path := range paths {
+		var reader *maxminddb.Reader
+		if reader, err = maxminddb.Open(path); err != nil {
+			_ = locator.Close() // Ignore any error.
+			return nil, err
+		}
+		locator.readers = append(locator.readers, reader)
+	}
+
+	return locator, nil
+}
+
+// Close closes the databases.
+func (l *Locator) Close() (err error) {
+	for _, reader := range l.readers {
+		if closeErr := reader.Close(); closeErr != nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// Visit returns a copy of the visit with the country and ASN of its IP address. Information that is not found is left
+// empty. Visits that already have a country or ASN are not looked up again.
+func (l *Locator) Visit(visit models.Visit) (located models.Visit) {
+
+	// Copy the visit.
+	located = visit
+
+	// Parse the IP address, which may have a port.
+	if visit.IP == nil || visit.Country != "" || visit.Asn != 0 {
+		return located
+	}
+	address := *visit.IP
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		address = host
+	}
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return located
+	}
+
+	// Look up the IP address in each database. Keep the first information found.
+	for _, reader := range l.readers {
+		var r record
+		if err := reader.Lookup(ip, &r); err != nil {
+			continue
+		}
+		if located.Asn == 0 {
+			located.Asn = r.ASN
+		}
+		if located.Country == "" {
+			located.Country = r.Country.ISOCode
+		}
+	}
+
+	return located
+}
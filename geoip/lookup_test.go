@@ -0,0 +1,66 @@
+package geoip
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/MicahParks/terseurl/models"
+)
+
+// TestLocator_Visit confirms visits are located with the first information found in each database. The fixtures are
+// tiny IPv4 MaxMind format databases. The country database has 81.2.69.0/24 in GB and 216.160.83.0/24 in the US. The
+// ASN database has 81.2.69.0/24 in AS20712 and 1.128.0.0/11 in AS1221.
+func TestLocator_Visit(t *testing.T) {
+	locator, err := NewLocator([]string{filepath.Join("testdata", "country.mmdb"), filepath.Join("testdata", "asn.mmdb")})
+	if err != nil {
+		t.Fatalf("failed to open the GeoIP databases: %s", err.Error())
+	}
+	defer locator.Close()
+
+	testCases := []struct {
+		name    string
+		ip      *string
+		located models.Visit
+		country string
+		asn     uint64
+	}{
+		{name: "both databases", ip: address("81.2.69.142"), country: "GB", asn: 20712},
+		{name: "with port", ip: address("81.2.69.142:8080"), country: "GB", asn: 20712},
+		{name: "country database only", ip: address("216.160.83.56"), country: "US"},
+		{name: "ASN database only", ip: address("1.128.0.1"), asn: 1221},
+		{name: "not found", ip: address("192.0.2.1")},
+		{name: "IPv6 not found", ip: address("2001:db8::1")},
+		{name: "not an IP address", ip: address("unknown")},
+		{name: "no IP address"},
+		{name: "already located", ip: address("81.2.69.142"), located: models.Visit{Country: "CA"}, country: "CA"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			visit := testCase.located
+			visit.IP = testCase.ip
+			located := locator.Visit(visit)
+			if located.Country != testCase.country {
+				t.Fatalf("expected %q, got %q", testCase.country, located.Country)
+			}
+			if located.Asn != testCase.asn {
+				t.Fatalf("expected %d, got %d", testCase.asn, located.Asn)
+			}
+			if located.IP != visit.IP {
+				t.Fatalf("expected the IP address to be unchanged")
+			}
+		})
+	}
+}
+
+// TestNewLocator confirms a database that cannot be opened is an error.
+func TestNewLocator(t *testing.T) {
+	if _, err := NewLocator([]string{filepath.Join("testdata", "missing.mmdb")}); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
+
+// address returns a pointer to the given IP address.
+func address(ip string) (pointer *string) {
+	return &ip
+}
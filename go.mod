@@ -17,6 +17,7 @@ require (
 	github.com/google/go-cmp v0.5.3 // indirect
 	github.com/jessevdk/go-flags v1.4.0
 	github.com/mailru/easyjson v0.7.7 // indirect
+	github.com/oschwald/maxminddb-golang v1.8.0
 	github.com/patrickmn/go-cache v2.1.0+incompatible // indirect
 	github.com/teris-io/shortid v0.0.0-20201117134242-e59966efd125
 	go.etcd.io/bbolt v1.3.5
@@ -0,0 +1,92 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/MicahParks/ctxerrgroup"
+	"go.uber.org/zap"
+
+	"github.com/MicahParks/terseurl/storage"
+)
+
+// Pruner periodically enforces the retention policy for Visits data. Visits older than the retention are rolled up into
+// daily visit counts, which are kept, then the raw Visits data are deleted.
+type Pruner struct {
+	createCtx storage.CtxCreator
+	group     ctxerrgroup.Group
+	interval  time.Duration
+	logger    *zap.SugaredLogger
+	manager   storage.StoreManager
+	retention time.Duration
+}
+
+// NewPruner creates a new Pruner given the required assets.
+func NewPruner(createCtx storage.CtxCreator, group ctxerrgroup.Group, interval time.Duration, logger *zap.SugaredLogger, manager storage.StoreManager, retention time.Duration) (pruner Pruner) {
+	return Pruner{
+		createCtx: createCtx,
+		group:     group,
+		interval:  interval,
+		logger:    logger,
+		manager:   manager,
+		retention: retention,
+	}
+}
+
+// Prune rolls up and deletes the visits that are older than the retention.
+func (p Pruner) Prune(ctx context.Context) (err error) {
+
+	// Find the cutoff for raw Visits data.
+	cutoff := time.Now().Add(-p.retention)
+
+	// Roll up and delete the visits before the cutoff.
+	pruned, err := p.manager.PruneVisits(ctx, cutoff)
+	if err != nil {
+		return err
+	}
+
+	// Check for the empty case.
+	if pruned == 0 {
+		p.logger.Debug("No visits older than the retention found.")
+		return nil
+	}
+
+	// Log what was removed.
+	p.logger.Infow("Pruned visits older than the retention.",
+		"cutoff", cutoff,
+		"pruned", pruned,
+	)
+
+	return nil
+}
+
+// Start launches a goroutine that adds a Prune work item to the ctxerrgroup every interval. It stops when the
+// ctxerrgroup dies.
+func (p Pruner) Start() {
+	go func() {
+
+		// Create a ticker for the interval.
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+
+			// Stop when the worker pool has been killed.
+			case <-p.group.Death():
+				return
+
+			// Prune the old visits on the worker pool.
+			case <-ticker.C:
+				ctx, cancel := p.createCtx()
+				p.group.AddWorkItem(ctx, cancel, func(workCtx context.Context) (err error) {
+					if err = p.Prune(workCtx); err != nil {
+						return fmt.Errorf("failed to prune visits: %w", err)
+					}
+					return nil
+				})
+			}
+		}
+	}()
+}
@@ -0,0 +1,161 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/MicahParks/ctxerrgroup"
+	"go.uber.org/zap"
+
+	"github.com/MicahParks/terseurl/models"
+	"github.com/MicahParks/terseurl/storage"
+)
+
+// Reaper periodically purges expired shortened URLs from all data stores. It can archive the expired shortened URLs in
+// the export format before they are deleted.
+type Reaper struct {
+	archiveDir string
+	createCtx  storage.CtxCreator
+	group      ctxerrgroup.Group
+	interval   time.Duration
+	logger     *zap.SugaredLogger
+	manager    storage.StoreManager
+	orphans    bool
+}
+
+// NewReaper creates a new Reaper given the required assets. If archiveDir is empty, expired shortened URLs will not be
+// archived. If orphans is true, Summary data and Visits data without Terse data are also deleted.
+func NewReaper(archiveDir string, createCtx storage.CtxCreator, group ctxerrgroup.Group, interval time.Duration, logger *zap.SugaredLogger, manager storage.StoreManager, orphans bool) (reaper Reaper) {
+	return Reaper{
+		archiveDir: archiveDir,
+		createCtx:  createCtx,
+		group:      group,
+		interval:   interval,
+		logger:     logger,
+		manager:    manager,
+		orphans:    orphans,
+	}
+}
+
+// Reap finds the expired shortened URLs, archives them if configured to, then deletes all of their data if they are
+// still expired. Orphaned data is only deleted if configured to.
+func (r Reaper) Reap(ctx context.Context) (err error) {
+
+	// Find the expired shortened URLs. Only find the orphaned data if configured to.
+	now := time.Now()
+	expired, orphaned, err := r.manager.Expired(ctx, now, r.orphans)
+	if err != nil {
+		return err
+	}
+
+	// Check for the empty case.
+	if len(expired) == 0 && len(orphaned) == 0 {
+		r.logger.Debug("No expired shortened URLs found.")
+		return nil
+	}
+
+	// Archive the expired shortened URLs, if required. Orphaned shortened URLs do not have any Terse data to archive.
+	if r.archiveDir != "" && len(expired) != 0 {
+		var filePath string
+		if filePath, err = r.archive(ctx, expired); err != nil {
+			return err
+		}
+		r.logger.Infow("Archived expired shortened URLs.",
+			"filePath", filePath,
+			"shortenedURLs", expired,
+		)
+	}
+
+	// Delete all the data for the expired and orphaned shortened URLs. They are checked again, because they may have
+	// been updated since they were found.
+	if expired, orphaned, err = r.manager.DeleteExpired(ctx, now, expired, orphaned); err != nil {
+		return err
+	}
+
+	// Log what was removed.
+	r.logger.Infow("Deleted expired shortened URLs.",
+		"expired", expired,
+		"orphaned", orphaned,
+	)
+
+	return nil
+}
+
+// Start launches a goroutine that adds a Reap work item to the ctxerrgroup every interval. It stops when the ctxerrgroup
+// dies.
+func (r Reaper) Start() {
+	go func() {
+
+		// Create a ticker for the interval.
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+
+			// Stop when the worker pool has been killed.
+			case <-r.group.Death():
+				return
+
+			// Reap the expired shortened URLs on the worker pool.
+			case <-ticker.C:
+				ctx, cancel := r.createCtx()
+				r.group.AddWorkItem(ctx, cancel, func(workCtx context.Context) (err error) {
+					if err = r.Reap(workCtx); err != nil {
+						return fmt.Errorf("failed to reap expired shortened URLs: %w", err)
+					}
+					return nil
+				})
+			}
+		}
+	}()
+}
+
+// archive writes the export of the given shortened URLs to a JSON file in the archive directory. The file path is
+// returned.
+func (r Reaper) archive(ctx context.Context, shortenedURLs []string) (filePath string, err error) {
+
+	// Export the data for the shortened URLs.
+	var export map[string]*models.Export
+	if export, err = r.manager.Export(ctx, nil, shortenedURLs); err != nil {
+		return "", err
+	}
+
+	// Turn the export into JSON.
+	var data []byte
+	if data, err = json.Marshal(export); err != nil {
+		return "", err
+	}
+
+	// Create a new file named after the current time. Add a number if a file with the name already exists, so archives
+	// are never overwritten.
+	name := "expired-" + time.Now().UTC().Format("20060102T150405Z")
+	var file *os.File
+	for i := 0; ; i++ {
+		filePath = filepath.Join(r.archiveDir, name+".json")
+		if i != 0 {
+			filePath = filepath.Join(r.archiveDir, fmt.Sprintf("%s-%d.json", name, i))
+		}
+		if file, err = os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600); err == nil {
+			break
+		}
+		if !os.IsExist(err) {
+			return "", err
+		}
+	}
+
+	// Write the JSON to the file.
+	if _, err = file.Write(data); err != nil {
+		_ = file.Close() // Ignore any error.
+		return "", err
+	}
+	if err = file.Close(); err != nil {
+		return "", err
+	}
+
+	return filePath, nil
+}
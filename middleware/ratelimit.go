@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/didip/tollbooth"
+	"github.com/didip/tollbooth/limiter"
+
+	"github.com/MicahParks/terseurl/clientip"
+)
+
+// RateLimitMiddleware is the middleware used to rate limit clients by their IP address. The IP address comes from the
+// given resolver, so forwarding headers are only trusted from trusted proxies.
+func RateLimitMiddleware(limit *limiter.Limiter, next http.Handler, resolver clientip.Resolver) (handler http.HandlerFunc) {
+
+	// Create the HTTP handler via a closure.
+	return func(writer http.ResponseWriter, request *http.Request) {
+
+		// Check if the client has reached the limit.
+		if httpError := tollbooth.LimitByKeys(limit, []string{resolver.ClientIP(request)}); httpError != nil {
+
+			// Report the rate limit to the client.
+			limit.ExecOnLimitReached(writer, request)
+			writer.Header().Add("Content-Type", limit.GetMessageContentType())
+			writer.WriteHeader(httpError.StatusCode)
+			_, _ = writer.Write([]byte(httpError.Message)) // Ignore any error.
+			return
+		}
+
+		// Serve the next handler.
+		next.ServeHTTP(writer, request)
+	}
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+)
+
+const (
+
+	// apiPrefix is the URL prefix of the API endpoints. Their paths are never changed.
+	apiPrefix = "/api/"
+)
+
+// SuffixMiddleware is the middleware used to route paths under a shortened URL, like /docs-xyz/guide/install, to the
+// /{shortenedURL}/{suffix} endpoint. The router only matches one path segment per parameter, so the slashes in the rest
+// of the path are escaped.
+func SuffixMiddleware(next http.Handler) (handler http.HandlerFunc) {
+
+	// Create the HTTP handler via a closure.
+	return func(writer http.ResponseWriter, request *http.Request) {
+
+		// Split the shortened URL from the rest of the path. Only change paths with more than two segments.
+		escaped := request.URL.EscapedPath()
+		if request.Method == http.MethodGet && !strings.HasPrefix(escaped, apiPrefix) {
+			parts := strings.SplitN(strings.TrimPrefix(escaped, "/"), "/", 2)
+			if len(parts) == 2 && strings.Contains(parts[1], "/") {
+
+				// Escape the slashes in the rest of the path. The unescaped path is unchanged.
+				request.URL.RawPath = "/" + parts[0] + "/" + strings.ReplaceAll(parts[1], "/", "%2F")
+			}
+		}
+
+		// Follow the HTTP middleware pattern.
+		next.ServeHTTP(writer, request)
+	}
+}
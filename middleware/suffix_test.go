@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// TestSuffixMiddleware confirms the rest of the path after a shortened URL is routed as one escaped path segment, and
+// other paths are unchanged.
+func TestSuffixMiddleware(t *testing.T) {
+	testCases := []struct {
+		name     string
+		method   string
+		target   string
+		expected []string
+	}{
+		{name: "shortened URL", target: "/short", expected: []string{"short"}},
+		{name: "shortened URL trailing slash", target: "/short/", expected: []string{"short", ""}},
+		{name: "one segment suffix", target: "/short/guide", expected: []string{"short", "guide"}},
+		{name: "suffix", target: "/short/guide/install", expected: []string{"short", "guide/install"}},
+		{name: "suffix trailing slash", target: "/short/guide/", expected: []string{"short", "guide/"}},
+		{name: "suffix repeated slashes", target: "/short/guide//install", expected: []string{"short", "guide//install"}},
+		{name: "escaped slash", target: "/short/a%2Fb", expected: []string{"short", "a/b"}},
+		{name: "escaped slash in suffix", target: "/short/guide/a%2Fb", expected: []string{"short", "guide/a/b"}},
+		{name: "escaped percent", target: "/short/guide/a%252Fb", expected: []string{"short", "guide/a%2Fb"}},
+		{name: "dot dot", target: "/short/guide/../../admin", expected: []string{"short", "guide/../../admin"}},
+		{name: "API", target: "/api/summary/more", expected: []string{"api", "summary", "more"}},
+		{name: "not GET", method: http.MethodPost, target: "/short/guide/install", expected: []string{"short", "guide", "install"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			method := testCase.method
+			if method == "" {
+				method = http.MethodGet
+			}
+
+			// Keep the request the next handler is given.
+			var routed *http.Request
+			handler := SuffixMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, request *http.Request) {
+				routed = request
+			}))
+			request := httptest.NewRequest(method, testCase.target, nil)
+			path := request.URL.Path
+			handler.ServeHTTP(httptest.NewRecorder(), request)
+
+			// The unescaped path is never changed.
+			if routed.URL.Path != path {
+				t.Fatalf("expected the path %q, got %q", path, routed.URL.Path)
+			}
+
+			// Split the escaped path into segments and unescape each, like the router does.
+			segments := strings.Split(strings.TrimPrefix(routed.URL.EscapedPath(), "/"), "/")
+			for i, segment := range segments {
+				var err error
+				if segments[i], err = url.PathUnescape(segment); err != nil {
+					t.Fatalf("failed to unescape path segment: %s", err.Error())
+				}
+			}
+			if len(segments) != len(testCase.expected) {
+				t.Fatalf("expected the segments %q, got %q", testCase.expected, segments)
+			}
+			for i, segment := range segments {
+				if segment != testCase.expected[i] {
+					t.Fatalf("expected the segments %q, got %q", testCase.expected, segments)
+				}
+			}
+		})
+	}
+}
@@ -17,6 +17,12 @@ import (
 // swagger:model Principal
 type Principal struct {
 
+	// groups
+	Groups []string `json:"groups"`
+
+	// roles
+	Roles []string `json:"roles"`
+
 	// sub
 	Sub string `json:"sub,omitempty"`
 }
@@ -7,6 +7,7 @@ package models
 
 import (
 	"context"
+	"strconv"
 
 	"github.com/go-openapi/errors"
 	"github.com/go-openapi/strfmt"
@@ -19,9 +20,26 @@ import (
 // swagger:model Terse
 type Terse struct {
 
+	// The time the shortened URL was created. It is set by the server.
+	// Format: date-time
+	CreatedAt *strfmt.DateTime `json:"createdAt,omitempty"`
+
+	// The time after which the shortened URL no longer redirects. If empty, it never expires by time.
+	// Format: date-time
+	ExpiresAt *strfmt.DateTime `json:"expiresAt,omitempty"`
+
+	// Append the rest of the path after the shortened URL to the URL the client is redirected to, like /docs-xyz/guide/install.
+	ForwardPath bool `json:"forwardPath,omitempty"`
+
+	// Forward the query parameters of the visit to the URL the client is redirected to.
+	ForwardQuery bool `json:"forwardQuery,omitempty"`
+
 	// javascript tracking
 	JavascriptTracking bool `json:"javascriptTracking,omitempty"`
 
+	// The number of visits after which the shortened URL no longer redirects. If empty, it never expires by visits.
+	MaxVisits uint64 `json:"maxVisits,omitempty"`
+
 	// media preview
 	MediaPreview *MediaPreview `json:"mediaPreview,omitempty"`
 
@@ -29,18 +47,41 @@ type Terse struct {
 	// Required: true
 	OriginalURL string `json:"originalURL"`
 
+	// query conflict
+	QueryConflict QueryConflict `json:"queryConflict,omitempty"`
+
+	// Query parameters added to the URL the client is redirected to, like UTM parameters. They do not replace query parameters already in the URL.
+	QueryDefaults map[string]string `json:"queryDefaults,omitempty"`
+
 	// redirect type
 	RedirectType RedirectType `json:"redirectType,omitempty"`
 
 	// shortened URL
 	// Required: true
 	ShortenedURL string `json:"shortenedURL"`
+
+	// Keep each visitor on the variant they were first redirected to with a cookie.
+	StickyVariants bool `json:"stickyVariants,omitempty"`
+
+	// Rules that redirect some clients to a different URL than the original URL. The first rule that matches the client is used. If no rule matches, the original URL is used.
+	Targets []TargetRule `json:"targets"`
+
+	// Destinations that visits are split between by weight, like for A/B tests. If empty, the original URL is used.
+	Variants []Variant `json:"variants"`
 }
 
 // Validate validates this terse
 func (m *Terse) Validate(formats strfmt.Registry) error {
 	var res []error
 
+	if err := m.validateCreatedAt(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateExpiresAt(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.validateMediaPreview(formats); err != nil {
 		res = append(res, err)
 	}
@@ -49,6 +90,10 @@ func (m *Terse) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validateQueryConflict(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.validateRedirectType(formats); err != nil {
 		res = append(res, err)
 	}
@@ -57,12 +102,44 @@ func (m *Terse) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validateTargets(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateVariants(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
 	return nil
 }
 
+func (m *Terse) validateCreatedAt(formats strfmt.Registry) error {
+	if swag.IsZero(m.CreatedAt) { // not required
+		return nil
+	}
+
+	if err := validate.FormatOf("createdAt", "body", "date-time", m.CreatedAt.String(), formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *Terse) validateExpiresAt(formats strfmt.Registry) error {
+	if swag.IsZero(m.ExpiresAt) { // not required
+		return nil
+	}
+
+	if err := validate.FormatOf("expiresAt", "body", "date-time", m.ExpiresAt.String(), formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (m *Terse) validateMediaPreview(formats strfmt.Registry) error {
 	if swag.IsZero(m.MediaPreview) { // not required
 		return nil
@@ -89,6 +166,21 @@ func (m *Terse) validateOriginalURL(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *Terse) validateQueryConflict(formats strfmt.Registry) error {
+	if swag.IsZero(m.QueryConflict) { // not required
+		return nil
+	}
+
+	if err := m.QueryConflict.Validate(formats); err != nil {
+		if ve, ok := err.(*errors.Validation); ok {
+			return ve.ValidateName("queryConflict")
+		}
+		return err
+	}
+
+	return nil
+}
+
 func (m *Terse) validateRedirectType(formats strfmt.Registry) error {
 	if swag.IsZero(m.RedirectType) { // not required
 		return nil
@@ -113,6 +205,44 @@ func (m *Terse) validateShortenedURL(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *Terse) validateTargets(formats strfmt.Registry) error {
+	if swag.IsZero(m.Targets) { // not required
+		return nil
+	}
+
+	for i := 0; i < len(m.Targets); i++ {
+
+		if err := m.Targets[i].Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("targets" + "." + strconv.Itoa(i))
+			}
+			return err
+		}
+
+	}
+
+	return nil
+}
+
+func (m *Terse) validateVariants(formats strfmt.Registry) error {
+	if swag.IsZero(m.Variants) { // not required
+		return nil
+	}
+
+	for i := 0; i < len(m.Variants); i++ {
+
+		if err := m.Variants[i].Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("variants" + "." + strconv.Itoa(i))
+			}
+			return err
+		}
+
+	}
+
+	return nil
+}
+
 // ContextValidate validate this terse based on the context it is used
 func (m *Terse) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
 	var res []error
@@ -121,10 +251,22 @@ func (m *Terse) ContextValidate(ctx context.Context, formats strfmt.Registry) er
 		res = append(res, err)
 	}
 
+	if err := m.contextValidateQueryConflict(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.contextValidateRedirectType(ctx, formats); err != nil {
 		res = append(res, err)
 	}
 
+	if err := m.contextValidateTargets(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.contextValidateVariants(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
@@ -145,6 +287,18 @@ func (m *Terse) contextValidateMediaPreview(ctx context.Context, formats strfmt.
 	return nil
 }
 
+func (m *Terse) contextValidateQueryConflict(ctx context.Context, formats strfmt.Registry) error {
+
+	if err := m.QueryConflict.ContextValidate(ctx, formats); err != nil {
+		if ve, ok := err.(*errors.Validation); ok {
+			return ve.ValidateName("queryConflict")
+		}
+		return err
+	}
+
+	return nil
+}
+
 func (m *Terse) contextValidateRedirectType(ctx context.Context, formats strfmt.Registry) error {
 
 	if err := m.RedirectType.ContextValidate(ctx, formats); err != nil {
@@ -157,6 +311,38 @@ func (m *Terse) contextValidateRedirectType(ctx context.Context, formats strfmt.
 	return nil
 }
 
+func (m *Terse) contextValidateTargets(ctx context.Context, formats strfmt.Registry) error {
+
+	for i := 0; i < len(m.Targets); i++ {
+
+		if err := m.Targets[i].ContextValidate(ctx, formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("targets" + "." + strconv.Itoa(i))
+			}
+			return err
+		}
+
+	}
+
+	return nil
+}
+
+func (m *Terse) contextValidateVariants(ctx context.Context, formats strfmt.Registry) error {
+
+	for i := 0; i < len(m.Variants); i++ {
+
+		if err := m.Variants[i].ContextValidate(ctx, formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("variants" + "." + strconv.Itoa(i))
+			}
+			return err
+		}
+
+	}
+
+	return nil
+}
+
 // MarshalBinary interface implementation
 func (m *Terse) MarshalBinary() ([]byte, error) {
 	if m == nil {
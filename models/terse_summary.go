@@ -11,6 +11,7 @@ import (
 	"github.com/go-openapi/errors"
 	"github.com/go-openapi/strfmt"
 	"github.com/go-openapi/swag"
+	"github.com/go-openapi/validate"
 )
 
 // TerseSummary terse summary
@@ -18,6 +19,17 @@ import (
 // swagger:model TerseSummary
 type TerseSummary struct {
 
+	// created at
+	// Format: date-time
+	CreatedAt *strfmt.DateTime `json:"createdAt,omitempty"`
+
+	// expires at
+	// Format: date-time
+	ExpiresAt *strfmt.DateTime `json:"expiresAt,omitempty"`
+
+	// max visits
+	MaxVisits uint64 `json:"maxVisits,omitempty"`
+
 	// original URL
 	OriginalURL string `json:"originalURL,omitempty"`
 
@@ -32,6 +44,14 @@ type TerseSummary struct {
 func (m *TerseSummary) Validate(formats strfmt.Registry) error {
 	var res []error
 
+	if err := m.validateCreatedAt(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateExpiresAt(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.validateRedirectType(formats); err != nil {
 		res = append(res, err)
 	}
@@ -42,6 +62,30 @@ func (m *TerseSummary) Validate(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *TerseSummary) validateCreatedAt(formats strfmt.Registry) error {
+	if swag.IsZero(m.CreatedAt) { // not required
+		return nil
+	}
+
+	if err := validate.FormatOf("createdAt", "body", "date-time", m.CreatedAt.String(), formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *TerseSummary) validateExpiresAt(formats strfmt.Registry) error {
+	if swag.IsZero(m.ExpiresAt) { // not required
+		return nil
+	}
+
+	if err := validate.FormatOf("expiresAt", "body", "date-time", m.ExpiresAt.String(), formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (m *TerseSummary) validateRedirectType(formats strfmt.Registry) error {
 	if swag.IsZero(m.RedirectType) { // not required
 		return nil
@@ -24,12 +24,27 @@ type Visit struct {
 	// Format: date-time
 	Accessed *strfmt.DateTime `json:"accessed"`
 
+	// The autonomous system number of the IP address, if a GeoIP database is configured.
+	Asn uint64 `json:"asn,omitempty"`
+
+	// The visit was from an automated client, like a crawler or link preview unfurler, based on its User-Agent. Bot visits are not in the visit count unless configured.
+	Bot bool `json:"bot,omitempty"`
+
+	// The ISO 3166-1 alpha-2 code of the country of the IP address, if a GeoIP database is configured.
+	Country string `json:"country,omitempty"`
+
 	// headers
 	Headers map[string][]string `json:"headers,omitempty"`
 
 	// ip
 	// Required: true
 	IP *string `json:"ip"`
+
+	// The name of the targeting rule that picked the URL the client was redirected to. If empty, the original URL was used.
+	TargetRule string `json:"targetRule,omitempty"`
+
+	// The name of the variant the client was redirected to, if the shortened URL has variants and no targeting rule matched.
+	Variant string `json:"variant,omitempty"`
 }
 
 // Validate validates this visit
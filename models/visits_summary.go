@@ -17,7 +17,37 @@ import (
 // swagger:model VisitsSummary
 type VisitsSummary struct {
 
-	// visit count
+	// The number of visits from automated clients, like crawlers and link preview unfurlers.
+	BotCount uint64 `json:"botCount,omitempty"`
+
+	// The number of visits from each browser family, such as Chrome or Firefox.
+	Browsers map[string]uint64 `json:"browsers,omitempty"`
+
+	// The number of visits from each country, keyed by ISO 3166-1 alpha-2 code. Only visits with a country are counted.
+	Countries map[string]uint64 `json:"countries,omitempty"`
+
+	// The number of visits on each UTC day, keyed by the date in YYYY-MM-DD format. Daily counts are kept after the raw Visits data are pruned by the retention policy.
+	DailyCounts map[string]uint64 `json:"dailyCounts,omitempty"`
+
+	// The number of visits from each device type. The device types are bot, desktop, mobile, tablet, and other.
+	Devices map[string]uint64 `json:"devices,omitempty"`
+
+	// The number of visits that are not from automated clients.
+	HumanCount uint64 `json:"humanCount,omitempty"`
+
+	// The number of visits from each OS family, such as Windows or iOS.
+	OperatingSystems map[string]uint64 `json:"operatingSystems,omitempty"`
+
+	// The number of visits referred by each host, from the Referer header. Only the top 100 hosts are kept. Visits from the rest are counted under other.
+	Referrers map[string]uint64 `json:"referrers,omitempty"`
+
+	// The estimated number of unique visitors. A visitor is identified by a hash of their IP address and User-Agent, which is only kept in a HyperLogLog sketch. The estimate has a standard error of about 1.6%.
+	UniqueVisitors uint64 `json:"uniqueVisitors,omitempty"`
+
+	// The number of visits redirected to each variant, keyed by the variant name. Visits redirected by a targeting rule are not counted.
+	Variants map[string]uint64 `json:"variants,omitempty"`
+
+	// The number of visits. Visits from automated clients are not counted unless configured.
 	VisitCount uint64 `json:"visitCount,omitempty"`
 }
 
@@ -0,0 +1,132 @@
+package privacy
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MicahParks/terseurl/models"
+)
+
+const (
+
+	// ipv4PrefixBits is the number of leading bits kept when truncating an IPv4 address.
+	ipv4PrefixBits = 24
+
+	// ipv6PrefixBits is the number of leading bits kept when truncating an IPv6 address.
+	ipv6PrefixBits = 48
+
+	// saltLength is the number of random bytes in a salt.
+	saltLength = 32
+)
+
+// Anonymizer removes personal data from visits before they are stored. IP addresses are truncated, then hashed with a
+// salt that rotates periodically. Old salts are forgotten, so hashes from different periods cannot be linked. This also
+// means a visitor returning after a rotation is a new unique visitor in the Summary data. Only allow-listed headers are
+// kept.
+type Anonymizer struct {
+	headers  map[string]bool
+	mux      sync.Mutex
+	period   int64
+	rotation time.Duration
+	salt     []byte
+}
+
+// NewAnonymizer creates a new Anonymizer that keeps the given headers and rotates its salt after the given amount of
+// time. Periods start at the Unix epoch, so all instances rotate at the same time. Salts are never stored, so a
+// restart also rotates the salt. A rotation of 0 or less never rotates the salt.
+func NewAnonymizer(headers []string, rotation time.Duration) (anonymizer *Anonymizer) {
+
+	// Canonicalize the allow-listed headers.
+	allowed := make(map[string]bool, len(headers))
+	for _, header := range headers {
+		allowed[http.CanonicalHeaderKey(header)] = true
+	}
+
+	return &Anonymizer{
+		headers:  allowed,
+		rotation: rotation,
+	}
+}
+
+// Visit returns a copy of the visit without personal data. The IP address is replaced by a hex encoded hash of the
+// truncated address and only allow-listed headers are kept.
+func (a *Anonymizer) Visit(visit models.Visit) (anonymized models.Visit) {
+
+	// Copy the visit.
+	anonymized = visit
+
+	// Only keep the allow-listed headers.
+	anonymized.Headers = make(map[string][]string)
+	for key, values := range visit.Headers {
+		if a.headers[http.CanonicalHeaderKey(key)] {
+			anonymized.Headers[key] = append([]string{}, values...)
+		}
+	}
+
+	// Replace the IP address with the hash of its truncation.
+	if visit.IP != nil {
+		hash := a.hash(truncate(*visit.IP), time.Now())
+		anonymized.IP = &hash
+	}
+
+	return anonymized
+}
+
+// hash hashes the truncated IP address with the salt for the period of the given time. The salt is rotated if the
+// period has changed.
+func (a *Anonymizer) hash(truncated string, now time.Time) (hash string) {
+
+	// Lock the salt for async safe use.
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	// Find the period. Without a rotation, there is only one period.
+	var period int64
+	if a.rotation > 0 {
+		period = now.UnixNano() / int64(a.rotation)
+	}
+
+	// Rotate the salt if this is a new period.
+	if a.salt == nil || period != a.period {
+		a.salt = make([]byte, saltLength)
+		if _, err := rand.Read(a.salt); err != nil {
+			panic(err) // The system's secure random number generator should never fail.
+		}
+		a.period = period
+	}
+
+	// Hash the truncated IP address with the salt.
+	mac := hmac.New(sha256.New, a.salt)
+	mac.Write([]byte(truncated)) // Never returns an error.
+
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// truncate zeroes the trailing bits of the IP address. Addresses that cannot be parsed are returned lowercase without
+// their port.
+func truncate(address string) (truncated string) {
+
+	// Remove the port, if present.
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		address = host
+	}
+
+	// Parse the IP address.
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return strings.ToLower(address)
+	}
+
+	// Zero the trailing bits.
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4.Mask(net.CIDRMask(ipv4PrefixBits, 32)).String()
+	}
+	return ip.Mask(net.CIDRMask(ipv6PrefixBits, 128)).String()
+}
@@ -0,0 +1,112 @@
+package privacy
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/MicahParks/terseurl/models"
+)
+
+// TestTruncate confirms IPv4 addresses are truncated to their /24 and IPv6 addresses to their /48.
+func TestTruncate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		address  string
+		expected string
+	}{
+		{name: "IPv4", address: "203.0.113.77", expected: "203.0.113.0"},
+		{name: "IPv4 with port", address: "203.0.113.77:8080", expected: "203.0.113.0"},
+		{name: "IPv4 in IPv6", address: "::ffff:203.0.113.77", expected: "203.0.113.0"},
+		{name: "IPv6", address: "2001:db8:abcd:1234:5678::1", expected: "2001:db8:abcd::"},
+		{name: "IPv6 with port", address: "[2001:db8:abcd:1234::1]:8080", expected: "2001:db8:abcd::"},
+		{name: "IPv6 uppercase", address: "2001:DB8:ABCD:FFFF::1", expected: "2001:db8:abcd::"},
+		{name: "not an IP address", address: "Unknown", expected: "unknown"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if truncated := truncate(testCase.address); truncated != testCase.expected {
+				t.Fatalf("expected %q, got %q", testCase.expected, truncated)
+			}
+		})
+	}
+}
+
+// TestAnonymizer_Visit confirms only the allow-listed headers are kept and the IP address is replaced by a hash of its
+// truncation.
+func TestAnonymizer_Visit(t *testing.T) {
+	anonymizer := NewAnonymizer([]string{"referer", "User-Agent"}, time.Hour)
+
+	ip := "203.0.113.77"
+	visit := models.Visit{
+		Headers: http.Header{
+			"Cookie":     {"session=secret"},
+			"Referer":    {"https://example.com"},
+			"User-Agent": {"Mozilla/5.0"},
+		},
+		IP: &ip,
+	}
+	anonymized := anonymizer.Visit(visit)
+
+	// Confirm only the allow-listed headers were kept.
+	expected := map[string][]string{
+		"Referer":    {"https://example.com"},
+		"User-Agent": {"Mozilla/5.0"},
+	}
+	if !reflect.DeepEqual(anonymized.Headers, expected) {
+		t.Fatalf("expected %v, got %v", expected, anonymized.Headers)
+	}
+	if len(visit.Headers["Cookie"]) == 0 {
+		t.Fatalf("the original visit's headers were changed")
+	}
+
+	// Confirm the IP address was hashed and addresses in the same /24 have the same hash.
+	if anonymized.IP == nil || *anonymized.IP == ip {
+		t.Fatalf("expected the IP address to be hashed")
+	}
+	neighbor := "203.0.113.1"
+	if hash := anonymizer.Visit(models.Visit{IP: &neighbor}).IP; *hash != *anonymized.IP {
+		t.Fatalf("expected %q, got %q", *anonymized.IP, *hash)
+	}
+	other := "203.0.114.77"
+	if hash := anonymizer.Visit(models.Visit{IP: &other}).IP; *hash == *anonymized.IP {
+		t.Fatalf("expected a different hash for a different /24")
+	}
+}
+
+// TestAnonymizer_hash confirms the salt is kept within a period and rotated when the period changes.
+func TestAnonymizer_hash(t *testing.T) {
+	const rotation = time.Hour
+	start := time.Unix(0, 0).Add(100 * rotation)
+
+	testCases := []struct {
+		name     string
+		rotation time.Duration
+		later    time.Time
+		same     bool
+	}{
+		{name: "same period", rotation: rotation, later: start.Add(rotation - time.Nanosecond), same: true},
+		{name: "next period", rotation: rotation, later: start.Add(rotation)},
+		{name: "later period", rotation: rotation, later: start.Add(5 * rotation)},
+		{name: "no rotation", later: start.Add(5 * rotation), same: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			anonymizer := NewAnonymizer(nil, testCase.rotation)
+			first := anonymizer.hash("203.0.113.0", start)
+			if again := anonymizer.hash("203.0.113.0", start); again != first {
+				t.Fatalf("expected %q, got %q", first, again)
+			}
+			later := anonymizer.hash("203.0.113.0", testCase.later)
+			if testCase.same && later != first {
+				t.Fatalf("expected %q, got %q", first, later)
+			}
+			if !testCase.same && later == first {
+				t.Fatalf("expected the salt to be rotated")
+			}
+		})
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/go-openapi/runtime"
 
 	"github.com/MicahParks/terseurl/auth"
+	"github.com/MicahParks/terseurl/clientip"
 	"github.com/MicahParks/terseurl/configure"
 	"github.com/MicahParks/terseurl/endpoints"
 	"github.com/MicahParks/terseurl/endpoints/public"
@@ -59,7 +60,7 @@ func configureAPI(api *operations.TerseurlAPI) http.Handler {
 		// Configure the JWT auth.
 		//
 		// TODO Make HTTP client configurable?
-		api.JWTAuth, err = auth.HandleJWT(ctx, nil, config.JWKSURL, logger.Named("JWT Authenticator"), sleep)
+		api.JWTAuth, err = auth.HandleJWT(ctx, nil, config.ClaimPaths, config.JWKSURL, logger.Named("JWT Authenticator"), sleep)
 		if err != nil {
 			logger.Fatalw("failed to get JWKS", // TODO Remove.
 				"error", err.Error(),
@@ -67,6 +68,9 @@ func configureAPI(api *operations.TerseurlAPI) http.Handler {
 		}
 		cancel()
 		logger.Info("Authentication with JWKS configured.")
+
+		// Enforce the per-operation authorization policy.
+		api.APIAuthorizer = config.Policy
 	} else {
 		api.JWTAuth = func(s string) (*models.Principal, error) {
 			return nil, nil
@@ -75,17 +79,20 @@ func configureAPI(api *operations.TerseurlAPI) http.Handler {
 	}
 
 	// Assign the endpoint handlers.
+	api.APIAnalyticsHandler = endpoints.HandleAnalytics(logger.Named("POST /api/analytics"), config.StoreManager)
 	api.APIExportHandler = endpoints.HandleExport(logger.Named("POST /api/export"), config.StoreManager)
 	api.APIFrontendMetaHandler = endpoints.HandleMeta(logger.Named("POST /api/frontend/meta"))
-	api.APIImportHandler = endpoints.HandleImport(logger.Named("POST /api/import"), config.StoreManager)
+	api.APIImportHandler = endpoints.HandleImport(logger.Named("POST /api/import"), config.InvalidPaths, config.ShortenedMaxLength, config.StoreManager)
+	api.APISearchHandler = endpoints.HandleSearch(logger.Named("POST /api/search"), config.StoreManager)
 	api.APIShortenedDeleteHandler = endpoints.HandleShortenedDelete(logger.Named("DELETE /api/shortened"), config.StoreManager)
 	api.APIShortenedPrefixHandler = endpoints.HandleShortenedPrefix(logger.Named("POST /api/prefix"), config.Prefix)
 	api.APIShortenedSummaryHandler = endpoints.HandleShortenedSummary(logger.Named("POST /api/summary"), config.StoreManager)
 	api.APITerseReadHandler = endpoints.HandleTerseRead(logger.Named("POST /api/terse"), config.StoreManager)
-	api.APITerseWriteHandler = endpoints.HandleWrite(logger.Named("POST /api/write/{operation}"), config.ShortID, config.StoreManager)
+	api.APITerseWriteHandler = endpoints.HandleWrite(logger.Named("POST /api/write/{operation}"), config.Dedupe, config.Generator, config.InvalidPaths, config.ShortenedMaxLength, config.StoreManager)
 	api.APIVisitsDeleteHandler = endpoints.HandlerVisitsDelete(logger.Named("DELETE /api/visits"), config.StoreManager)
 	api.APIVisitsReadHandler = endpoints.HandleVisitsRead(logger.Named("POST /api/visits"), config.StoreManager)
-	api.PublicPublicRedirectHandler = public.HandleRedirect(logger.Named("GET /{shortenedURL}"), config.Template, config.StoreManager)
+	api.PublicPublicRedirectHandler = public.HandleRedirect(logger.Named("GET /{shortenedURL}"), config.Template, config.GonePage, config.Locator, config.StoreManager, config.PreviewBots, config.ClientIP)
+	api.PublicPublicRedirectSuffixHandler = public.HandleRedirectSuffix(logger.Named("GET /{shortenedURL}/{suffix}"), config.Template, config.GonePage, config.Locator, config.StoreManager, config.PreviewBots, config.ClientIP)
 	api.SystemSystemAliveHandler = system.HandleAlive()
 
 	api.PreServerShutdown = func() {}
@@ -105,9 +112,33 @@ func configureAPI(api *operations.TerseurlAPI) http.Handler {
 				"error", err.Error(),
 			)
 		}
+
+		// Close the GeoIP databases, if any.
+		if config.Locator != nil {
+			if err = config.Locator.Close(); err != nil {
+				logger.Errorw("Failed to close the GeoIP databases.",
+					"error", err.Error(),
+				)
+			}
+		}
+
+		// Close the Generator.
+		if err = config.Generator.Close(ctx); err != nil {
+			logger.Errorw("Failed to close the Generator.",
+				"error", err.Error(),
+			)
+		}
+
+		// Log the shortened URL generation metrics.
+		metrics := config.Generator.Metrics()
+		logger.Infow("Shortened URL generation metrics.",
+			"collisions", metrics.Collisions,
+			"exhausted", metrics.Exhausted,
+			"generated", metrics.Generated,
+		)
 	}
 
-	return setupGlobalMiddleware(api.Serve(setupMiddlewares))
+	return setupGlobalMiddleware(api.Serve(setupMiddlewares), config.ClientIP)
 }
 
 // The TLS configuration before HTTPS server starts.
@@ -130,17 +161,18 @@ func setupMiddlewares(handler http.Handler) http.Handler {
 
 // The middleware configuration happens before anything, this middleware also applies to serving the swagger.json document.
 // So this is a good place to plug in a panic handling middleware, logging and metrics.
-func setupGlobalMiddleware(handler http.Handler) http.Handler {
+func setupGlobalMiddleware(handler http.Handler, resolver clientip.Resolver) http.Handler {
 
 	// Create an incoming request rate limiter that only allows 1 request per section and forgets about clients after 1
 	// hour.
 	limit := tollbooth.NewLimiter(1, &limiter.ExpirableOptions{DefaultExpirationTTL: time.Hour})
 
-	// Find the IP of the client in the X-Forwarded-For header, because Caddy will be the server in front of this.
-	limit.SetIPLookups([]string{"X-Forwarded-For"}) // TODO Add string for regular lookup.
+	// Set up the middleware that routes paths under a shortened URL to the suffix endpoint.
+	suffix := middleware.SuffixMiddleware(handler)
 
-	// Set up the rate limiter middleware.
-	toll := tollbooth.LimitHandler(limit, handler) // TODO Logging middleware. Maybe another rate limiter instead.
+	// Set up the rate limiter middleware. The IP of the client is resolved the same way as for visits, so forwarding
+	// headers like X-Forwarded-For are only trusted from trusted proxies, such as Caddy.
+	toll := middleware.RateLimitMiddleware(limit, suffix, resolver) // TODO Logging middleware. Maybe another rate limiter instead.
 
 	// Set up the frontend middleware.
 	frontendMiddleware, err := middleware.FrontendMiddleware(toll)
@@ -50,6 +50,86 @@ func init() {
         }
       }
     },
+    "/api/analytics": {
+      "post": {
+        "security": [
+          {
+            "JWT": []
+          }
+        ],
+        "description": "Count the visits to the given shortened URLs in each interval of a time series. There is a series for each shortened URL and a total series for all of them. Intervals without visits have a count of zero. Pruned visits only have daily counts, so they are counted at the start of their UTC day.",
+        "consumes": [
+          "application/json"
+        ],
+        "produces": [
+          "application/json"
+        ],
+        "tags": [
+          "api"
+        ],
+        "summary": "Count the visits to the given shortened URLs over time.",
+        "operationId": "analytics",
+        "parameters": [
+          {
+            "type": "string",
+            "format": "date-time",
+            "description": "Only count visits at or after this time.",
+            "name": "from",
+            "in": "query"
+          },
+          {
+            "enum": [
+              "hour",
+              "day",
+              "week"
+            ],
+            "type": "string",
+            "description": "The length of each interval in the time series. Weeks start on Monday. If empty, day is used.",
+            "name": "interval",
+            "in": "query"
+          },
+          {
+            "description": "The shortened URLs to count the visits of. If empty, all shortened URLs are counted.",
+            "name": "shortenedURLs",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "type": "array",
+              "items": {
+                "type": "string"
+              }
+            }
+          },
+          {
+            "type": "string",
+            "description": "The IANA time zone the intervals start in, such as America/New_York. If empty, UTC is used.",
+            "name": "timezone",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "format": "date-time",
+            "description": "Only count visits before this time.",
+            "name": "to",
+            "in": "query"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "The time series were successfully created.",
+            "schema": {
+              "$ref": "#/definitions/Analytics"
+            }
+          },
+          "default": {
+            "description": "Unexpected error.",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            }
+          }
+        }
+      }
+    },
     "/api/export": {
       "post": {
         "security": [
@@ -224,6 +304,52 @@ func init() {
         }
       }
     },
+    "/api/search": {
+      "post": {
+        "security": [
+          {
+            "JWT": []
+          }
+        ],
+        "description": "Find the Terse data whose original URL matches the query. The match can be by exact original URL, host, path prefix, or substring. Results are sorted by shortened URL and paginated.",
+        "consumes": [
+          "application/json"
+        ],
+        "produces": [
+          "application/json"
+        ],
+        "tags": [
+          "api"
+        ],
+        "summary": "Find Terse data by original URL.",
+        "operationId": "search",
+        "parameters": [
+          {
+            "description": "The query to match original URLs against and the page of results to return.",
+            "name": "query",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/SearchQuery"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "The page of Terse data that matched the query.",
+            "schema": {
+              "$ref": "#/definitions/SearchResults"
+            }
+          },
+          "default": {
+            "description": "Unexpected error.",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            }
+          }
+        }
+      }
+    },
     "/api/shortened": {
       "delete": {
         "security": [
@@ -277,7 +403,7 @@ func init() {
             "JWT": []
           }
         ],
-        "description": "Summary data includes the shortened URL, the original URL, the type of redirect, and the number of visits.",
+        "description": "Summary data includes the shortened URL, the original URL, the type of redirect, and the number of visits. Results are sorted and can be paginated.",
         "consumes": [
           "application/json"
         ],
@@ -290,6 +416,26 @@ func init() {
         "summary": "Provide Summary data for the requested shortened URLs.",
         "operationId": "shortenedSummary",
         "parameters": [
+          {
+            "type": "string",
+            "description": "The cursor from the previous page of results. If empty, the first page is returned.",
+            "name": "cursor",
+            "in": "query"
+          },
+          {
+            "type": "boolean",
+            "description": "Sort in descending order instead of ascending order.",
+            "name": "descending",
+            "in": "query"
+          },
+          {
+            "maximum": 1000,
+            "type": "integer",
+            "format": "uint64",
+            "description": "The maximum number of results to return. If empty, all results are returned.",
+            "name": "limit",
+            "in": "query"
+          },
           {
             "description": "The array of shortened URLs to get Summary data for. If none is provided, all will summaries will be returned.",
             "name": "shortenedURLs",
@@ -300,16 +446,24 @@ func init() {
                 "type": "string"
               }
             }
+          },
+          {
+            "enum": [
+              "createdAt",
+              "shortenedURL",
+              "visitCount"
+            ],
+            "type": "string",
+            "description": "The field to sort results by. If empty, results are sorted by shortened URL.",
+            "name": "sort",
+            "in": "query"
           }
         ],
         "responses": {
           "200": {
-            "description": "The map of shortened URLs to Summary data.",
+            "description": "The page of Summary data.",
             "schema": {
-              "additionalProperties": {
-                "x-nullable": true,
-                "$ref": "#/definitions/Summary"
-              }
+              "$ref": "#/definitions/SummaryPage"
             }
           },
           "default": {
@@ -328,7 +482,7 @@ func init() {
             "JWT": []
           }
         ],
-        "description": "Read the Terse data for the given shortened URL.",
+        "description": "Read the Terse data for the given shortened URL. Results are sorted and can be paginated.",
         "consumes": [
           "application/json"
         ],
@@ -341,6 +495,26 @@ func init() {
         "summary": "Read the Terse data for the given shortened URL.",
         "operationId": "terseRead",
         "parameters": [
+          {
+            "type": "string",
+            "description": "The cursor from the previous page of results. If empty, the first page is returned.",
+            "name": "cursor",
+            "in": "query"
+          },
+          {
+            "type": "boolean",
+            "description": "Sort in descending order instead of ascending order.",
+            "name": "descending",
+            "in": "query"
+          },
+          {
+            "maximum": 1000,
+            "type": "integer",
+            "format": "uint64",
+            "description": "The maximum number of results to return. If empty, all results are returned.",
+            "name": "limit",
+            "in": "query"
+          },
           {
             "description": "The shortened URLs to read the Terse data for.",
             "name": "shortenedURLs",
@@ -352,16 +526,24 @@ func init() {
                 "type": "string"
               }
             }
+          },
+          {
+            "enum": [
+              "createdAt",
+              "shortenedURL",
+              "visitCount"
+            ],
+            "type": "string",
+            "description": "The field to sort results by. If empty, results are sorted by shortened URL.",
+            "name": "sort",
+            "in": "query"
           }
         ],
         "responses": {
           "200": {
-            "description": "The Terse data was successfully retrieved.",
+            "description": "The page of Terse data.",
             "schema": {
-              "additionalProperties": {
-                "x-nullable": true,
-                "$ref": "#/definitions/Terse"
-              }
+              "$ref": "#/definitions/TersePage"
             }
           },
           "default": {
@@ -380,7 +562,7 @@ func init() {
             "JWT": []
           }
         ],
-        "description": "Read the Visits data for the given shortened URLs.",
+        "description": "Read the Visits data for the given shortened URLs. Visits are sorted by time and can be filtered by time, IP address, and header.",
         "consumes": [
           "application/json"
         ],
@@ -393,6 +575,42 @@ func init() {
         "summary": "Read the Visits data for the given shortened URLs.",
         "operationId": "visitsRead",
         "parameters": [
+          {
+            "type": "string",
+            "format": "date-time",
+            "description": "Only include visits at or after this time.",
+            "name": "from",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "description": "Only include visits with a value for the headerName header that contains this case insensitive substring.",
+            "name": "headerContains",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "description": "The header to match headerContains against. If headerContains is empty, only include visits that have this header.",
+            "name": "headerName",
+            "in": "query"
+          },
+          {
+            "type": "array",
+            "items": {
+              "type": "string"
+            },
+            "collectionFormat": "csv",
+            "description": "Only include visits from these comma separated IP addresses or CIDR blocks.",
+            "name": "ip",
+            "in": "query"
+          },
+          {
+            "type": "integer",
+            "format": "uint64",
+            "description": "The maximum number of visits to include for each shortened URL. If empty, there is no limit.",
+            "name": "limit",
+            "in": "query"
+          },
           {
             "description": "The shortened URLs to read the Visits data for.",
             "name": "shortenedURLs",
@@ -404,6 +622,13 @@ func init() {
                 "type": "string"
               }
             }
+          },
+          {
+            "type": "string",
+            "format": "date-time",
+            "description": "Only include visits before this time.",
+            "name": "to",
+            "in": "query"
           }
         ],
         "responses": {
@@ -432,7 +657,7 @@ func init() {
             "JWT": []
           }
         ],
-        "description": "Delete all Visits data for the given shortened URLs. This will affect Summary data.",
+        "description": "Delete Visits data for the given shortened URLs. If no query parameters are given, all Visits data for the shortened URLs are deleted. Otherwise, only the visits that match all the query parameters are deleted. This will affect Summary data.",
         "consumes": [
           "application/json"
         ],
@@ -442,9 +667,26 @@ func init() {
         "tags": [
           "api"
         ],
-        "summary": "Delete all Visits data for the given shortened URLs.",
+        "summary": "Delete Visits data for the given shortened URLs.",
         "operationId": "visitsDelete",
         "parameters": [
+          {
+            "type": "string",
+            "format": "date-time",
+            "description": "Only delete visits at or after this time.",
+            "name": "from",
+            "in": "query"
+          },
+          {
+            "type": "array",
+            "items": {
+              "type": "string"
+            },
+            "collectionFormat": "csv",
+            "description": "Only delete visits from these comma separated IP addresses or CIDR blocks.",
+            "name": "ip",
+            "in": "query"
+          },
           {
             "description": "The shortened URLs whose Visits data should be deleted.",
             "name": "shortenedURLs",
@@ -456,6 +698,13 @@ func init() {
                 "type": "string"
               }
             }
+          },
+          {
+            "type": "string",
+            "format": "date-time",
+            "description": "Only delete visits before this time. Use this to delete visits older than a cutoff.",
+            "name": "to",
+            "in": "query"
           }
         ],
         "responses": {
@@ -582,12 +831,133 @@ func init() {
           },
           "404": {
             "description": "The shortened URL expired or never existed."
+          },
+          "410": {
+            "description": "The shortened URL expired. The HTML document is the configured gone page.",
+            "schema": {
+              "type": "file"
+            }
+          }
+        }
+      }
+    },
+    "/{shortenedURL}/{suffix}": {
+      "get": {
+        "description": "Use the shortened URL with the rest of a path. It will redirect to the full URL with the rest of the path appended if it has not expired and forwards paths.",
+        "produces": [
+          "text/html"
+        ],
+        "tags": [
+          "public"
+        ],
+        "summary": "Typically a web browser would visit this endpoint, then get redirected to a path under the full URL.",
+        "operationId": "publicRedirectSuffix",
+        "parameters": [
+          {
+            "type": "string",
+            "name": "shortenedURL",
+            "in": "path",
+            "required": true
+          },
+          {
+            "type": "string",
+            "description": "The rest of the path after the shortened URL. It may contain slashes.",
+            "name": "suffix",
+            "in": "path",
+            "required": true
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "The HTML document containing a social media link preview and or JavaScript fingerprinting. Any visitor will be automatically redirected to the original link with JavaScript.",
+            "schema": {
+              "type": "file"
+            }
+          },
+          "301": {
+            "description": "An HTTP response that will server as a permanent redirect to the shortened URL's full URL.",
+            "headers": {
+              "Location": {
+                "type": "string",
+                "description": "The full URL that the redirect leads to."
+              }
+            }
+          },
+          "302": {
+            "description": "An HTTP response that will serve as a temporary redirect to the shortened URL's full URL.",
+            "headers": {
+              "Location": {
+                "type": "string",
+                "description": "The full URL that the redirect leads to."
+              }
+            }
+          },
+          "404": {
+            "description": "The shortened URL expired, never existed, or does not forward paths."
+          },
+          "410": {
+            "description": "The shortened URL expired. The HTML document is the configured gone page.",
+            "schema": {
+              "type": "file"
+            }
           }
         }
       }
     }
   },
   "definitions": {
+    "Analytics": {
+      "properties": {
+        "interval": {
+          "$ref": "#/definitions/AnalyticsInterval"
+        },
+        "shortenedURLs": {
+          "description": "The time series of each shortened URL.",
+          "type": "object",
+          "additionalProperties": {
+            "type": "array",
+            "items": {
+              "$ref": "#/definitions/AnalyticsPoint"
+            }
+          }
+        },
+        "timezone": {
+          "description": "The IANA time zone the intervals start in.",
+          "type": "string"
+        },
+        "total": {
+          "description": "The time series of all the shortened URLs combined.",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/AnalyticsPoint"
+          }
+        }
+      }
+    },
+    "AnalyticsInterval": {
+      "type": "string",
+      "enum": [
+        "hour",
+        "day",
+        "week"
+      ]
+    },
+    "AnalyticsPoint": {
+      "properties": {
+        "count": {
+          "description": "The number of visits in the interval.",
+          "type": "integer",
+          "format": "uint64",
+          "x-omitempty": false
+        },
+        "start": {
+          "description": "The start of the interval.",
+          "type": "string",
+          "format": "date-time"
+        }
+      },
+      "x-nullable": false
+    },
     "Error": {
       "type": "object",
       "required": [
@@ -637,22 +1007,98 @@ func init() {
         "type": "string"
       }
     },
+    "Platform": {
+      "type": "string",
+      "enum": [
+        "android",
+        "desktop",
+        "ios"
+      ]
+    },
     "Principal": {
       "properties": {
+        "groups": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "roles": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
         "sub": {
           "type": "string"
         }
       }
     },
-    "RedirectType": {
+    "QueryConflict": {
+      "description": "How forwarded query parameters with the same name as a query parameter already in the URL or in its defaults are handled. With incoming, the forwarded value replaces it. With original, the forwarded value is dropped. With append, both are kept. If empty, incoming is used.",
       "type": "string",
       "enum": [
-        "301",
+        "append",
+        "incoming",
+        "original"
+      ]
+    },
+    "RedirectType": {
+      "type": "string",
+      "enum": [
+        "301",
         "302",
         "meta",
         "js"
       ]
     },
+    "SearchMatch": {
+      "type": "string",
+      "enum": [
+        "exact",
+        "host",
+        "pathPrefix",
+        "substring"
+      ]
+    },
+    "SearchQuery": {
+      "required": [
+        "value"
+      ],
+      "properties": {
+        "after": {
+          "description": "Only return Terse data with a shortened URL after this one. Use the nextAfter value from the previous page of results.",
+          "type": "string"
+        },
+        "limit": {
+          "description": "The maximum number of Terse data to return. If empty, 100 is used. The maximum is 1000.",
+          "type": "integer",
+          "format": "uint64"
+        },
+        "match": {
+          "$ref": "#/definitions/SearchMatch"
+        },
+        "value": {
+          "description": "The value to match original URLs against. Matching by exact original URL compares normalized URLs. Matching by host includes subdomains. Matching by path prefix compares the start of the URL path. Matching by substring is case insensitive. If match is empty, substring is used.",
+          "type": "string",
+          "x-nullable": false
+        }
+      }
+    },
+    "SearchResults": {
+      "properties": {
+        "nextAfter": {
+          "description": "The value of after to use to get the next page of results. If empty, there are no more results.",
+          "type": "string"
+        },
+        "terse": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/Terse"
+          }
+        }
+      }
+    },
     "Summary": {
       "properties": {
         "terse": {
@@ -663,15 +1109,92 @@ func init() {
         }
       }
     },
+    "SummaryPage": {
+      "properties": {
+        "nextCursor": {
+          "description": "The cursor to use to get the next page of results. If empty, there are no more results.",
+          "type": "string"
+        },
+        "summaries": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/Summary"
+          }
+        }
+      }
+    },
+    "TargetRule": {
+      "required": [
+        "name",
+        "originalURL"
+      ],
+      "properties": {
+        "countries": {
+          "description": "The ISO 3166-1 alpha-2 codes of the countries the client must be in. A GeoIP database must be configured. If empty, the client can be in any country.",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "languages": {
+          "description": "The language tags the client's most preferred Accept-Language must match. A tag without a region, like de, also matches tags with a region, like de-AT. If empty, the client can prefer any language.",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "name": {
+          "description": "The name of the rule. It is recorded on the visits the rule redirects.",
+          "type": "string"
+        },
+        "originalURL": {
+          "description": "The URL to redirect clients that match the rule to.",
+          "type": "string"
+        },
+        "platforms": {
+          "description": "The platforms the client must be on, based on its User-Agent. If empty, the client can be on any platform.",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/Platform"
+          }
+        }
+      },
+      "x-nullable": false
+    },
     "Terse": {
       "required": [
         "originalURL",
         "shortenedURL"
       ],
       "properties": {
+        "createdAt": {
+          "description": "The time the shortened URL was created. It is set by the server.",
+          "type": "string",
+          "format": "date-time",
+          "x-nullable": true
+        },
+        "expiresAt": {
+          "description": "The time after which the shortened URL no longer redirects. If empty, it never expires by time.",
+          "type": "string",
+          "format": "date-time",
+          "x-nullable": true
+        },
+        "forwardPath": {
+          "description": "Append the rest of the path after the shortened URL to the URL the client is redirected to, like /docs-xyz/guide/install.",
+          "type": "boolean"
+        },
+        "forwardQuery": {
+          "description": "Forward the query parameters of the visit to the URL the client is redirected to.",
+          "type": "boolean"
+        },
         "javascriptTracking": {
           "type": "boolean"
         },
+        "maxVisits": {
+          "description": "The number of visits after which the shortened URL no longer redirects. If empty, it never expires by visits.",
+          "type": "integer",
+          "format": "uint64"
+        },
         "mediaPreview": {
           "$ref": "#/definitions/MediaPreview"
         },
@@ -679,12 +1202,40 @@ func init() {
           "type": "string",
           "x-nullable": false
         },
+        "queryConflict": {
+          "$ref": "#/definitions/QueryConflict"
+        },
+        "queryDefaults": {
+          "description": "Query parameters added to the URL the client is redirected to, like UTM parameters. They do not replace query parameters already in the URL.",
+          "type": "object",
+          "additionalProperties": {
+            "type": "string"
+          }
+        },
         "redirectType": {
           "$ref": "#/definitions/RedirectType"
         },
         "shortenedURL": {
           "type": "string",
           "x-nullable": false
+        },
+        "stickyVariants": {
+          "description": "Keep each visitor on the variant they were first redirected to with a cookie.",
+          "type": "boolean"
+        },
+        "targets": {
+          "description": "Rules that redirect some clients to a different URL than the original URL. The first rule that matches the client is used. If no rule matches, the original URL is used.",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/TargetRule"
+          }
+        },
+        "variants": {
+          "description": "Destinations that visits are split between by weight, like for A/B tests. If empty, the original URL is used.",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/Variant"
+          }
         }
       }
     },
@@ -693,9 +1244,28 @@ func init() {
         "originalURL"
       ],
       "properties": {
+        "expiresAt": {
+          "description": "The time after which the shortened URL no longer redirects. If empty, it never expires by time.",
+          "type": "string",
+          "format": "date-time",
+          "x-nullable": true
+        },
+        "forwardPath": {
+          "description": "Append the rest of the path after the shortened URL to the URL the client is redirected to, like /docs-xyz/guide/install.",
+          "type": "boolean"
+        },
+        "forwardQuery": {
+          "description": "Forward the query parameters of the visit to the URL the client is redirected to.",
+          "type": "boolean"
+        },
         "javascriptTracking": {
           "type": "boolean"
         },
+        "maxVisits": {
+          "description": "The number of visits after which the shortened URL no longer redirects. If empty, it never expires by visits.",
+          "type": "integer",
+          "format": "uint64"
+        },
         "mediaPreview": {
           "$ref": "#/definitions/MediaPreview"
         },
@@ -703,17 +1273,73 @@ func init() {
           "type": "string",
           "x-nullable": false
         },
+        "queryConflict": {
+          "$ref": "#/definitions/QueryConflict"
+        },
+        "queryDefaults": {
+          "description": "Query parameters added to the URL the client is redirected to, like UTM parameters. They do not replace query parameters already in the URL.",
+          "type": "object",
+          "additionalProperties": {
+            "type": "string"
+          }
+        },
         "redirectType": {
           "$ref": "#/definitions/RedirectType"
         },
         "shortenedURL": {
           "type": "string"
+        },
+        "stickyVariants": {
+          "description": "Keep each visitor on the variant they were first redirected to with a cookie.",
+          "type": "boolean"
+        },
+        "targets": {
+          "description": "Rules that redirect some clients to a different URL than the original URL. The first rule that matches the client is used. If no rule matches, the original URL is used.",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/TargetRule"
+          }
+        },
+        "variants": {
+          "description": "Destinations that visits are split between by weight, like for A/B tests. If empty, the original URL is used.",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/Variant"
+          }
         }
       },
       "x-nullable": false
     },
+    "TersePage": {
+      "properties": {
+        "nextCursor": {
+          "description": "The cursor to use to get the next page of results. If empty, there are no more results.",
+          "type": "string"
+        },
+        "terse": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/Terse"
+          }
+        }
+      }
+    },
     "TerseSummary": {
       "properties": {
+        "createdAt": {
+          "type": "string",
+          "format": "date-time",
+          "x-nullable": true
+        },
+        "expiresAt": {
+          "type": "string",
+          "format": "date-time",
+          "x-nullable": true
+        },
+        "maxVisits": {
+          "type": "integer",
+          "format": "uint64"
+        },
         "originalURL": {
           "type": "string"
         },
@@ -730,6 +1356,28 @@ func init() {
         "type": "string"
       }
     },
+    "Variant": {
+      "required": [
+        "name",
+        "originalURL"
+      ],
+      "properties": {
+        "name": {
+          "description": "The name of the variant. It is recorded on the visits redirected to the variant.",
+          "type": "string"
+        },
+        "originalURL": {
+          "description": "The URL to redirect clients assigned to the variant to.",
+          "type": "string"
+        },
+        "weight": {
+          "description": "The share of visits the variant gets, relative to the weights of the other variants. If empty, 1 is used.",
+          "type": "integer",
+          "format": "uint64"
+        }
+      },
+      "x-nullable": false
+    },
     "Visit": {
       "required": [
         "accessed",
@@ -740,6 +1388,19 @@ func init() {
           "type": "string",
           "format": "date-time"
         },
+        "asn": {
+          "description": "The autonomous system number of the IP address, if a GeoIP database is configured.",
+          "type": "integer",
+          "format": "uint64"
+        },
+        "bot": {
+          "description": "The visit was from an automated client, like a crawler or link preview unfurler, based on its User-Agent. Bot visits are not in the visit count unless configured.",
+          "type": "boolean"
+        },
+        "country": {
+          "description": "The ISO 3166-1 alpha-2 code of the country of the IP address, if a GeoIP database is configured.",
+          "type": "string"
+        },
         "headers": {
           "type": "object",
           "additionalProperties": {
@@ -751,13 +1412,93 @@ func init() {
         },
         "ip": {
           "type": "string"
+        },
+        "targetRule": {
+          "description": "The name of the targeting rule that picked the URL the client was redirected to. If empty, the original URL was used.",
+          "type": "string"
+        },
+        "variant": {
+          "description": "The name of the variant the client was redirected to, if the shortened URL has variants and no targeting rule matched.",
+          "type": "string"
         }
       },
       "x-nullable": false
     },
     "VisitsSummary": {
       "properties": {
+        "botCount": {
+          "description": "The number of visits from automated clients, like crawlers and link preview unfurlers.",
+          "type": "integer",
+          "format": "uint64"
+        },
+        "browsers": {
+          "description": "The number of visits from each browser family, such as Chrome or Firefox.",
+          "type": "object",
+          "additionalProperties": {
+            "type": "integer",
+            "format": "uint64"
+          }
+        },
+        "countries": {
+          "description": "The number of visits from each country, keyed by ISO 3166-1 alpha-2 code. Only visits with a country are counted.",
+          "type": "object",
+          "additionalProperties": {
+            "type": "integer",
+            "format": "uint64"
+          }
+        },
+        "dailyCounts": {
+          "description": "The number of visits on each UTC day, keyed by the date in YYYY-MM-DD format. Daily counts are kept after the raw Visits data are pruned by the retention policy.",
+          "type": "object",
+          "additionalProperties": {
+            "type": "integer",
+            "format": "uint64"
+          }
+        },
+        "devices": {
+          "description": "The number of visits from each device type. The device types are bot, desktop, mobile, tablet, and other.",
+          "type": "object",
+          "additionalProperties": {
+            "type": "integer",
+            "format": "uint64"
+          }
+        },
+        "humanCount": {
+          "description": "The number of visits that are not from automated clients.",
+          "type": "integer",
+          "format": "uint64"
+        },
+        "operatingSystems": {
+          "description": "The number of visits from each OS family, such as Windows or iOS.",
+          "type": "object",
+          "additionalProperties": {
+            "type": "integer",
+            "format": "uint64"
+          }
+        },
+        "referrers": {
+          "description": "The number of visits referred by each host, from the Referer header. Only the top 100 hosts are kept. Visits from the rest are counted under other.",
+          "type": "object",
+          "additionalProperties": {
+            "type": "integer",
+            "format": "uint64"
+          }
+        },
+        "uniqueVisitors": {
+          "description": "The estimated number of unique visitors. A visitor is identified by a hash of their IP address and User-Agent, which is only kept in a HyperLogLog sketch. The estimate has a standard error of about 1.6%.",
+          "type": "integer",
+          "format": "uint64"
+        },
+        "variants": {
+          "description": "The number of visits redirected to each variant, keyed by the variant name. Visits redirected by a targeting rule are not counted.",
+          "type": "object",
+          "additionalProperties": {
+            "type": "integer",
+            "format": "uint64"
+          }
+        },
         "visitCount": {
+          "description": "The number of visits. Visits from automated clients are not counted unless configured.",
           "type": "integer",
           "format": "uint"
         }
@@ -823,6 +1564,86 @@ func init() {
         }
       }
     },
+    "/api/analytics": {
+      "post": {
+        "security": [
+          {
+            "JWT": []
+          }
+        ],
+        "description": "Count the visits to the given shortened URLs in each interval of a time series. There is a series for each shortened URL and a total series for all of them. Intervals without visits have a count of zero. Pruned visits only have daily counts, so they are counted at the start of their UTC day.",
+        "consumes": [
+          "application/json"
+        ],
+        "produces": [
+          "application/json"
+        ],
+        "tags": [
+          "api"
+        ],
+        "summary": "Count the visits to the given shortened URLs over time.",
+        "operationId": "analytics",
+        "parameters": [
+          {
+            "type": "string",
+            "format": "date-time",
+            "description": "Only count visits at or after this time.",
+            "name": "from",
+            "in": "query"
+          },
+          {
+            "enum": [
+              "hour",
+              "day",
+              "week"
+            ],
+            "type": "string",
+            "description": "The length of each interval in the time series. Weeks start on Monday. If empty, day is used.",
+            "name": "interval",
+            "in": "query"
+          },
+          {
+            "description": "The shortened URLs to count the visits of. If empty, all shortened URLs are counted.",
+            "name": "shortenedURLs",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "type": "array",
+              "items": {
+                "type": "string"
+              }
+            }
+          },
+          {
+            "type": "string",
+            "description": "The IANA time zone the intervals start in, such as America/New_York. If empty, UTC is used.",
+            "name": "timezone",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "format": "date-time",
+            "description": "Only count visits before this time.",
+            "name": "to",
+            "in": "query"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "The time series were successfully created.",
+            "schema": {
+              "$ref": "#/definitions/Analytics"
+            }
+          },
+          "default": {
+            "description": "Unexpected error.",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            }
+          }
+        }
+      }
+    },
     "/api/export": {
       "post": {
         "security": [
@@ -997,14 +1818,14 @@ func init() {
         }
       }
     },
-    "/api/shortened": {
-      "delete": {
+    "/api/search": {
+      "post": {
         "security": [
           {
             "JWT": []
           }
         ],
-        "description": "Delete all assets for the given shortened URLs. This includes all Terse data, Visits data, and Summary data.",
+        "description": "Find the Terse data whose original URL matches the query. The match can be by exact original URL, host, path prefix, or substring. Results are sorted by shortened URL and paginated.",
         "consumes": [
           "application/json"
         ],
@@ -1014,19 +1835,65 @@ func init() {
         "tags": [
           "api"
         ],
-        "summary": "Delete all assets for the given shortened URLs.",
-        "operationId": "shortenedDelete",
+        "summary": "Find Terse data by original URL.",
+        "operationId": "search",
         "parameters": [
           {
-            "description": "The shortened URLs whose data should be deleted.",
-            "name": "shortenedURLs",
+            "description": "The query to match original URLs against and the page of results to return.",
+            "name": "query",
             "in": "body",
             "required": true,
             "schema": {
-              "type": "array",
-              "items": {
-                "type": "string"
-              }
+              "$ref": "#/definitions/SearchQuery"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "The page of Terse data that matched the query.",
+            "schema": {
+              "$ref": "#/definitions/SearchResults"
+            }
+          },
+          "default": {
+            "description": "Unexpected error.",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            }
+          }
+        }
+      }
+    },
+    "/api/shortened": {
+      "delete": {
+        "security": [
+          {
+            "JWT": []
+          }
+        ],
+        "description": "Delete all assets for the given shortened URLs. This includes all Terse data, Visits data, and Summary data.",
+        "consumes": [
+          "application/json"
+        ],
+        "produces": [
+          "application/json"
+        ],
+        "tags": [
+          "api"
+        ],
+        "summary": "Delete all assets for the given shortened URLs.",
+        "operationId": "shortenedDelete",
+        "parameters": [
+          {
+            "description": "The shortened URLs whose data should be deleted.",
+            "name": "shortenedURLs",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "type": "array",
+              "items": {
+                "type": "string"
+              }
             }
           }
         ],
@@ -1050,7 +1917,7 @@ func init() {
             "JWT": []
           }
         ],
-        "description": "Summary data includes the shortened URL, the original URL, the type of redirect, and the number of visits.",
+        "description": "Summary data includes the shortened URL, the original URL, the type of redirect, and the number of visits. Results are sorted and can be paginated.",
         "consumes": [
           "application/json"
         ],
@@ -1063,6 +1930,26 @@ func init() {
         "summary": "Provide Summary data for the requested shortened URLs.",
         "operationId": "shortenedSummary",
         "parameters": [
+          {
+            "type": "string",
+            "description": "The cursor from the previous page of results. If empty, the first page is returned.",
+            "name": "cursor",
+            "in": "query"
+          },
+          {
+            "type": "boolean",
+            "description": "Sort in descending order instead of ascending order.",
+            "name": "descending",
+            "in": "query"
+          },
+          {
+            "maximum": 1000,
+            "type": "integer",
+            "format": "uint64",
+            "description": "The maximum number of results to return. If empty, all results are returned.",
+            "name": "limit",
+            "in": "query"
+          },
           {
             "description": "The array of shortened URLs to get Summary data for. If none is provided, all will summaries will be returned.",
             "name": "shortenedURLs",
@@ -1073,16 +1960,24 @@ func init() {
                 "type": "string"
               }
             }
+          },
+          {
+            "enum": [
+              "createdAt",
+              "shortenedURL",
+              "visitCount"
+            ],
+            "type": "string",
+            "description": "The field to sort results by. If empty, results are sorted by shortened URL.",
+            "name": "sort",
+            "in": "query"
           }
         ],
         "responses": {
           "200": {
-            "description": "The map of shortened URLs to Summary data.",
+            "description": "The page of Summary data.",
             "schema": {
-              "additionalProperties": {
-                "x-nullable": true,
-                "$ref": "#/definitions/Summary"
-              }
+              "$ref": "#/definitions/SummaryPage"
             }
           },
           "default": {
@@ -1101,7 +1996,7 @@ func init() {
             "JWT": []
           }
         ],
-        "description": "Read the Terse data for the given shortened URL.",
+        "description": "Read the Terse data for the given shortened URL. Results are sorted and can be paginated.",
         "consumes": [
           "application/json"
         ],
@@ -1114,6 +2009,26 @@ func init() {
         "summary": "Read the Terse data for the given shortened URL.",
         "operationId": "terseRead",
         "parameters": [
+          {
+            "type": "string",
+            "description": "The cursor from the previous page of results. If empty, the first page is returned.",
+            "name": "cursor",
+            "in": "query"
+          },
+          {
+            "type": "boolean",
+            "description": "Sort in descending order instead of ascending order.",
+            "name": "descending",
+            "in": "query"
+          },
+          {
+            "maximum": 1000,
+            "type": "integer",
+            "format": "uint64",
+            "description": "The maximum number of results to return. If empty, all results are returned.",
+            "name": "limit",
+            "in": "query"
+          },
           {
             "description": "The shortened URLs to read the Terse data for.",
             "name": "shortenedURLs",
@@ -1125,16 +2040,24 @@ func init() {
                 "type": "string"
               }
             }
+          },
+          {
+            "enum": [
+              "createdAt",
+              "shortenedURL",
+              "visitCount"
+            ],
+            "type": "string",
+            "description": "The field to sort results by. If empty, results are sorted by shortened URL.",
+            "name": "sort",
+            "in": "query"
           }
         ],
         "responses": {
           "200": {
-            "description": "The Terse data was successfully retrieved.",
+            "description": "The page of Terse data.",
             "schema": {
-              "additionalProperties": {
-                "x-nullable": true,
-                "$ref": "#/definitions/Terse"
-              }
+              "$ref": "#/definitions/TersePage"
             }
           },
           "default": {
@@ -1153,7 +2076,7 @@ func init() {
             "JWT": []
           }
         ],
-        "description": "Read the Visits data for the given shortened URLs.",
+        "description": "Read the Visits data for the given shortened URLs. Visits are sorted by time and can be filtered by time, IP address, and header.",
         "consumes": [
           "application/json"
         ],
@@ -1166,6 +2089,42 @@ func init() {
         "summary": "Read the Visits data for the given shortened URLs.",
         "operationId": "visitsRead",
         "parameters": [
+          {
+            "type": "string",
+            "format": "date-time",
+            "description": "Only include visits at or after this time.",
+            "name": "from",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "description": "Only include visits with a value for the headerName header that contains this case insensitive substring.",
+            "name": "headerContains",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "description": "The header to match headerContains against. If headerContains is empty, only include visits that have this header.",
+            "name": "headerName",
+            "in": "query"
+          },
+          {
+            "type": "array",
+            "items": {
+              "type": "string"
+            },
+            "collectionFormat": "csv",
+            "description": "Only include visits from these comma separated IP addresses or CIDR blocks.",
+            "name": "ip",
+            "in": "query"
+          },
+          {
+            "type": "integer",
+            "format": "uint64",
+            "description": "The maximum number of visits to include for each shortened URL. If empty, there is no limit.",
+            "name": "limit",
+            "in": "query"
+          },
           {
             "description": "The shortened URLs to read the Visits data for.",
             "name": "shortenedURLs",
@@ -1177,6 +2136,13 @@ func init() {
                 "type": "string"
               }
             }
+          },
+          {
+            "type": "string",
+            "format": "date-time",
+            "description": "Only include visits before this time.",
+            "name": "to",
+            "in": "query"
           }
         ],
         "responses": {
@@ -1205,7 +2171,7 @@ func init() {
             "JWT": []
           }
         ],
-        "description": "Delete all Visits data for the given shortened URLs. This will affect Summary data.",
+        "description": "Delete Visits data for the given shortened URLs. If no query parameters are given, all Visits data for the shortened URLs are deleted. Otherwise, only the visits that match all the query parameters are deleted. This will affect Summary data.",
         "consumes": [
           "application/json"
         ],
@@ -1215,9 +2181,26 @@ func init() {
         "tags": [
           "api"
         ],
-        "summary": "Delete all Visits data for the given shortened URLs.",
+        "summary": "Delete Visits data for the given shortened URLs.",
         "operationId": "visitsDelete",
         "parameters": [
+          {
+            "type": "string",
+            "format": "date-time",
+            "description": "Only delete visits at or after this time.",
+            "name": "from",
+            "in": "query"
+          },
+          {
+            "type": "array",
+            "items": {
+              "type": "string"
+            },
+            "collectionFormat": "csv",
+            "description": "Only delete visits from these comma separated IP addresses or CIDR blocks.",
+            "name": "ip",
+            "in": "query"
+          },
           {
             "description": "The shortened URLs whose Visits data should be deleted.",
             "name": "shortenedURLs",
@@ -1229,6 +2212,13 @@ func init() {
                 "type": "string"
               }
             }
+          },
+          {
+            "type": "string",
+            "format": "date-time",
+            "description": "Only delete visits before this time. Use this to delete visits older than a cutoff.",
+            "name": "to",
+            "in": "query"
           }
         ],
         "responses": {
@@ -1355,12 +2345,133 @@ func init() {
           },
           "404": {
             "description": "The shortened URL expired or never existed."
+          },
+          "410": {
+            "description": "The shortened URL expired. The HTML document is the configured gone page.",
+            "schema": {
+              "type": "file"
+            }
+          }
+        }
+      }
+    },
+    "/{shortenedURL}/{suffix}": {
+      "get": {
+        "description": "Use the shortened URL with the rest of a path. It will redirect to the full URL with the rest of the path appended if it has not expired and forwards paths.",
+        "produces": [
+          "text/html"
+        ],
+        "tags": [
+          "public"
+        ],
+        "summary": "Typically a web browser would visit this endpoint, then get redirected to a path under the full URL.",
+        "operationId": "publicRedirectSuffix",
+        "parameters": [
+          {
+            "type": "string",
+            "name": "shortenedURL",
+            "in": "path",
+            "required": true
+          },
+          {
+            "type": "string",
+            "description": "The rest of the path after the shortened URL. It may contain slashes.",
+            "name": "suffix",
+            "in": "path",
+            "required": true
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "The HTML document containing a social media link preview and or JavaScript fingerprinting. Any visitor will be automatically redirected to the original link with JavaScript.",
+            "schema": {
+              "type": "file"
+            }
+          },
+          "301": {
+            "description": "An HTTP response that will server as a permanent redirect to the shortened URL's full URL.",
+            "headers": {
+              "Location": {
+                "type": "string",
+                "description": "The full URL that the redirect leads to."
+              }
+            }
+          },
+          "302": {
+            "description": "An HTTP response that will serve as a temporary redirect to the shortened URL's full URL.",
+            "headers": {
+              "Location": {
+                "type": "string",
+                "description": "The full URL that the redirect leads to."
+              }
+            }
+          },
+          "404": {
+            "description": "The shortened URL expired, never existed, or does not forward paths."
+          },
+          "410": {
+            "description": "The shortened URL expired. The HTML document is the configured gone page.",
+            "schema": {
+              "type": "file"
+            }
           }
         }
       }
     }
   },
   "definitions": {
+    "Analytics": {
+      "properties": {
+        "interval": {
+          "$ref": "#/definitions/AnalyticsInterval"
+        },
+        "shortenedURLs": {
+          "description": "The time series of each shortened URL.",
+          "type": "object",
+          "additionalProperties": {
+            "type": "array",
+            "items": {
+              "$ref": "#/definitions/AnalyticsPoint"
+            }
+          }
+        },
+        "timezone": {
+          "description": "The IANA time zone the intervals start in.",
+          "type": "string"
+        },
+        "total": {
+          "description": "The time series of all the shortened URLs combined.",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/AnalyticsPoint"
+          }
+        }
+      }
+    },
+    "AnalyticsInterval": {
+      "type": "string",
+      "enum": [
+        "hour",
+        "day",
+        "week"
+      ]
+    },
+    "AnalyticsPoint": {
+      "properties": {
+        "count": {
+          "description": "The number of visits in the interval.",
+          "type": "integer",
+          "format": "uint64",
+          "x-omitempty": false
+        },
+        "start": {
+          "description": "The start of the interval.",
+          "type": "string",
+          "format": "date-time"
+        }
+      },
+      "x-nullable": false
+    },
     "Error": {
       "type": "object",
       "required": [
@@ -1410,13 +2521,42 @@ func init() {
         "type": "string"
       }
     },
+    "Platform": {
+      "type": "string",
+      "enum": [
+        "android",
+        "desktop",
+        "ios"
+      ]
+    },
     "Principal": {
       "properties": {
+        "groups": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "roles": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
         "sub": {
           "type": "string"
         }
       }
     },
+    "QueryConflict": {
+      "description": "How forwarded query parameters with the same name as a query parameter already in the URL or in its defaults are handled. With incoming, the forwarded value replaces it. With original, the forwarded value is dropped. With append, both are kept. If empty, incoming is used.",
+      "type": "string",
+      "enum": [
+        "append",
+        "incoming",
+        "original"
+      ]
+    },
     "RedirectType": {
       "type": "string",
       "enum": [
@@ -1426,6 +2566,53 @@ func init() {
         "js"
       ]
     },
+    "SearchMatch": {
+      "type": "string",
+      "enum": [
+        "exact",
+        "host",
+        "pathPrefix",
+        "substring"
+      ]
+    },
+    "SearchQuery": {
+      "required": [
+        "value"
+      ],
+      "properties": {
+        "after": {
+          "description": "Only return Terse data with a shortened URL after this one. Use the nextAfter value from the previous page of results.",
+          "type": "string"
+        },
+        "limit": {
+          "description": "The maximum number of Terse data to return. If empty, 100 is used. The maximum is 1000.",
+          "type": "integer",
+          "format": "uint64"
+        },
+        "match": {
+          "$ref": "#/definitions/SearchMatch"
+        },
+        "value": {
+          "description": "The value to match original URLs against. Matching by exact original URL compares normalized URLs. Matching by host includes subdomains. Matching by path prefix compares the start of the URL path. Matching by substring is case insensitive. If match is empty, substring is used.",
+          "type": "string",
+          "x-nullable": false
+        }
+      }
+    },
+    "SearchResults": {
+      "properties": {
+        "nextAfter": {
+          "description": "The value of after to use to get the next page of results. If empty, there are no more results.",
+          "type": "string"
+        },
+        "terse": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/Terse"
+          }
+        }
+      }
+    },
     "Summary": {
       "properties": {
         "terse": {
@@ -1436,15 +2623,92 @@ func init() {
         }
       }
     },
+    "SummaryPage": {
+      "properties": {
+        "nextCursor": {
+          "description": "The cursor to use to get the next page of results. If empty, there are no more results.",
+          "type": "string"
+        },
+        "summaries": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/Summary"
+          }
+        }
+      }
+    },
+    "TargetRule": {
+      "required": [
+        "name",
+        "originalURL"
+      ],
+      "properties": {
+        "countries": {
+          "description": "The ISO 3166-1 alpha-2 codes of the countries the client must be in. A GeoIP database must be configured. If empty, the client can be in any country.",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "languages": {
+          "description": "The language tags the client's most preferred Accept-Language must match. A tag without a region, like de, also matches tags with a region, like de-AT. If empty, the client can prefer any language.",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "name": {
+          "description": "The name of the rule. It is recorded on the visits the rule redirects.",
+          "type": "string"
+        },
+        "originalURL": {
+          "description": "The URL to redirect clients that match the rule to.",
+          "type": "string"
+        },
+        "platforms": {
+          "description": "The platforms the client must be on, based on its User-Agent. If empty, the client can be on any platform.",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/Platform"
+          }
+        }
+      },
+      "x-nullable": false
+    },
     "Terse": {
       "required": [
         "originalURL",
         "shortenedURL"
       ],
       "properties": {
+        "createdAt": {
+          "description": "The time the shortened URL was created. It is set by the server.",
+          "type": "string",
+          "format": "date-time",
+          "x-nullable": true
+        },
+        "expiresAt": {
+          "description": "The time after which the shortened URL no longer redirects. If empty, it never expires by time.",
+          "type": "string",
+          "format": "date-time",
+          "x-nullable": true
+        },
+        "forwardPath": {
+          "description": "Append the rest of the path after the shortened URL to the URL the client is redirected to, like /docs-xyz/guide/install.",
+          "type": "boolean"
+        },
+        "forwardQuery": {
+          "description": "Forward the query parameters of the visit to the URL the client is redirected to.",
+          "type": "boolean"
+        },
         "javascriptTracking": {
           "type": "boolean"
         },
+        "maxVisits": {
+          "description": "The number of visits after which the shortened URL no longer redirects. If empty, it never expires by visits.",
+          "type": "integer",
+          "format": "uint64"
+        },
         "mediaPreview": {
           "$ref": "#/definitions/MediaPreview"
         },
@@ -1452,12 +2716,40 @@ func init() {
           "type": "string",
           "x-nullable": false
         },
+        "queryConflict": {
+          "$ref": "#/definitions/QueryConflict"
+        },
+        "queryDefaults": {
+          "description": "Query parameters added to the URL the client is redirected to, like UTM parameters. They do not replace query parameters already in the URL.",
+          "type": "object",
+          "additionalProperties": {
+            "type": "string"
+          }
+        },
         "redirectType": {
           "$ref": "#/definitions/RedirectType"
         },
         "shortenedURL": {
           "type": "string",
           "x-nullable": false
+        },
+        "stickyVariants": {
+          "description": "Keep each visitor on the variant they were first redirected to with a cookie.",
+          "type": "boolean"
+        },
+        "targets": {
+          "description": "Rules that redirect some clients to a different URL than the original URL. The first rule that matches the client is used. If no rule matches, the original URL is used.",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/TargetRule"
+          }
+        },
+        "variants": {
+          "description": "Destinations that visits are split between by weight, like for A/B tests. If empty, the original URL is used.",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/Variant"
+          }
         }
       }
     },
@@ -1466,9 +2758,28 @@ func init() {
         "originalURL"
       ],
       "properties": {
+        "expiresAt": {
+          "description": "The time after which the shortened URL no longer redirects. If empty, it never expires by time.",
+          "type": "string",
+          "format": "date-time",
+          "x-nullable": true
+        },
+        "forwardPath": {
+          "description": "Append the rest of the path after the shortened URL to the URL the client is redirected to, like /docs-xyz/guide/install.",
+          "type": "boolean"
+        },
+        "forwardQuery": {
+          "description": "Forward the query parameters of the visit to the URL the client is redirected to.",
+          "type": "boolean"
+        },
         "javascriptTracking": {
           "type": "boolean"
         },
+        "maxVisits": {
+          "description": "The number of visits after which the shortened URL no longer redirects. If empty, it never expires by visits.",
+          "type": "integer",
+          "format": "uint64"
+        },
         "mediaPreview": {
           "$ref": "#/definitions/MediaPreview"
         },
@@ -1476,17 +2787,73 @@ func init() {
           "type": "string",
           "x-nullable": false
         },
+        "queryConflict": {
+          "$ref": "#/definitions/QueryConflict"
+        },
+        "queryDefaults": {
+          "description": "Query parameters added to the URL the client is redirected to, like UTM parameters. They do not replace query parameters already in the URL.",
+          "type": "object",
+          "additionalProperties": {
+            "type": "string"
+          }
+        },
         "redirectType": {
           "$ref": "#/definitions/RedirectType"
         },
         "shortenedURL": {
           "type": "string"
+        },
+        "stickyVariants": {
+          "description": "Keep each visitor on the variant they were first redirected to with a cookie.",
+          "type": "boolean"
+        },
+        "targets": {
+          "description": "Rules that redirect some clients to a different URL than the original URL. The first rule that matches the client is used. If no rule matches, the original URL is used.",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/TargetRule"
+          }
+        },
+        "variants": {
+          "description": "Destinations that visits are split between by weight, like for A/B tests. If empty, the original URL is used.",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/Variant"
+          }
         }
       },
       "x-nullable": false
     },
+    "TersePage": {
+      "properties": {
+        "nextCursor": {
+          "description": "The cursor to use to get the next page of results. If empty, there are no more results.",
+          "type": "string"
+        },
+        "terse": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/Terse"
+          }
+        }
+      }
+    },
     "TerseSummary": {
       "properties": {
+        "createdAt": {
+          "type": "string",
+          "format": "date-time",
+          "x-nullable": true
+        },
+        "expiresAt": {
+          "type": "string",
+          "format": "date-time",
+          "x-nullable": true
+        },
+        "maxVisits": {
+          "type": "integer",
+          "format": "uint64"
+        },
         "originalURL": {
           "type": "string"
         },
@@ -1503,6 +2870,28 @@ func init() {
         "type": "string"
       }
     },
+    "Variant": {
+      "required": [
+        "name",
+        "originalURL"
+      ],
+      "properties": {
+        "name": {
+          "description": "The name of the variant. It is recorded on the visits redirected to the variant.",
+          "type": "string"
+        },
+        "originalURL": {
+          "description": "The URL to redirect clients assigned to the variant to.",
+          "type": "string"
+        },
+        "weight": {
+          "description": "The share of visits the variant gets, relative to the weights of the other variants. If empty, 1 is used.",
+          "type": "integer",
+          "format": "uint64"
+        }
+      },
+      "x-nullable": false
+    },
     "Visit": {
       "required": [
         "accessed",
@@ -1513,6 +2902,19 @@ func init() {
           "type": "string",
           "format": "date-time"
         },
+        "asn": {
+          "description": "The autonomous system number of the IP address, if a GeoIP database is configured.",
+          "type": "integer",
+          "format": "uint64"
+        },
+        "bot": {
+          "description": "The visit was from an automated client, like a crawler or link preview unfurler, based on its User-Agent. Bot visits are not in the visit count unless configured.",
+          "type": "boolean"
+        },
+        "country": {
+          "description": "The ISO 3166-1 alpha-2 code of the country of the IP address, if a GeoIP database is configured.",
+          "type": "string"
+        },
         "headers": {
           "type": "object",
           "additionalProperties": {
@@ -1524,13 +2926,93 @@ func init() {
         },
         "ip": {
           "type": "string"
+        },
+        "targetRule": {
+          "description": "The name of the targeting rule that picked the URL the client was redirected to. If empty, the original URL was used.",
+          "type": "string"
+        },
+        "variant": {
+          "description": "The name of the variant the client was redirected to, if the shortened URL has variants and no targeting rule matched.",
+          "type": "string"
         }
       },
       "x-nullable": false
     },
     "VisitsSummary": {
       "properties": {
+        "botCount": {
+          "description": "The number of visits from automated clients, like crawlers and link preview unfurlers.",
+          "type": "integer",
+          "format": "uint64"
+        },
+        "browsers": {
+          "description": "The number of visits from each browser family, such as Chrome or Firefox.",
+          "type": "object",
+          "additionalProperties": {
+            "type": "integer",
+            "format": "uint64"
+          }
+        },
+        "countries": {
+          "description": "The number of visits from each country, keyed by ISO 3166-1 alpha-2 code. Only visits with a country are counted.",
+          "type": "object",
+          "additionalProperties": {
+            "type": "integer",
+            "format": "uint64"
+          }
+        },
+        "dailyCounts": {
+          "description": "The number of visits on each UTC day, keyed by the date in YYYY-MM-DD format. Daily counts are kept after the raw Visits data are pruned by the retention policy.",
+          "type": "object",
+          "additionalProperties": {
+            "type": "integer",
+            "format": "uint64"
+          }
+        },
+        "devices": {
+          "description": "The number of visits from each device type. The device types are bot, desktop, mobile, tablet, and other.",
+          "type": "object",
+          "additionalProperties": {
+            "type": "integer",
+            "format": "uint64"
+          }
+        },
+        "humanCount": {
+          "description": "The number of visits that are not from automated clients.",
+          "type": "integer",
+          "format": "uint64"
+        },
+        "operatingSystems": {
+          "description": "The number of visits from each OS family, such as Windows or iOS.",
+          "type": "object",
+          "additionalProperties": {
+            "type": "integer",
+            "format": "uint64"
+          }
+        },
+        "referrers": {
+          "description": "The number of visits referred by each host, from the Referer header. Only the top 100 hosts are kept. Visits from the rest are counted under other.",
+          "type": "object",
+          "additionalProperties": {
+            "type": "integer",
+            "format": "uint64"
+          }
+        },
+        "uniqueVisitors": {
+          "description": "The estimated number of unique visitors. A visitor is identified by a hash of their IP address and User-Agent, which is only kept in a HyperLogLog sketch. The estimate has a standard error of about 1.6%.",
+          "type": "integer",
+          "format": "uint64"
+        },
+        "variants": {
+          "description": "The number of visits redirected to each variant, keyed by the variant name. Visits redirected by a targeting rule are not counted.",
+          "type": "object",
+          "additionalProperties": {
+            "type": "integer",
+            "format": "uint64"
+          }
+        },
         "visitCount": {
+          "description": "The number of visits. Visits from automated clients are not counted unless configured.",
           "type": "integer",
           "format": "uint"
         }
@@ -11,6 +11,9 @@ import (
 	"github.com/go-openapi/errors"
 	"github.com/go-openapi/runtime"
 	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	"github.com/go-openapi/validate"
 )
 
 // NewShortenedSummaryParams creates a new ShortenedSummaryParams object
@@ -30,10 +33,27 @@ type ShortenedSummaryParams struct {
 	// HTTP Request Object
 	HTTPRequest *http.Request `json:"-"`
 
+	/*The cursor from the previous page of results. If empty, the first page is returned.
+	  In: query
+	*/
+	Cursor *string
+	/*Sort in descending order instead of ascending order.
+	  In: query
+	*/
+	Descending *bool
+	/*The maximum number of results to return. If empty, all results are returned.
+	  Maximum: 1000
+	  In: query
+	*/
+	Limit *uint64
 	/*The array of shortened URLs to get Summary data for. If none is provided, all will summaries will be returned.
 	  In: body
 	*/
 	ShortenedURLs []string
+	/*The field to sort results by. If empty, results are sorted by shortened URL.
+	  In: query
+	*/
+	Sort *string
 }
 
 // BindRequest both binds and validates a request, it assumes that complex things implement a Validatable(strfmt.Registry) error interface
@@ -45,6 +65,23 @@ func (o *ShortenedSummaryParams) BindRequest(r *http.Request, route *middleware.
 
 	o.HTTPRequest = r
 
+	qs := runtime.Values(r.URL.Query())
+
+	qCursor, qhkCursor, _ := qs.GetOK("cursor")
+	if err := o.bindCursor(qCursor, qhkCursor, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qDescending, qhkDescending, _ := qs.GetOK("descending")
+	if err := o.bindDescending(qDescending, qhkDescending, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qLimit, qhkLimit, _ := qs.GetOK("limit")
+	if err := o.bindLimit(qLimit, qhkLimit, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
 	if runtime.HasBody(r) {
 		defer r.Body.Close()
 		var body []string
@@ -55,8 +92,123 @@ func (o *ShortenedSummaryParams) BindRequest(r *http.Request, route *middleware.
 			o.ShortenedURLs = body
 		}
 	}
+
+	qSort, qhkSort, _ := qs.GetOK("sort")
+	if err := o.bindSort(qSort, qhkSort, route.Formats); err != nil {
+		res = append(res, err)
+	}
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
 	return nil
 }
+
+// bindCursor binds and validates parameter Cursor from query.
+func (o *ShortenedSummaryParams) bindCursor(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: false
+	// AllowEmptyValue: false
+
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+	o.Cursor = &raw
+
+	return nil
+}
+
+// bindDescending binds and validates parameter Descending from query.
+func (o *ShortenedSummaryParams) bindDescending(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: false
+	// AllowEmptyValue: false
+
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	value, err := swag.ConvertBool(raw)
+	if err != nil {
+		return errors.InvalidType("descending", "query", "bool", raw)
+	}
+	o.Descending = &value
+
+	return nil
+}
+
+// bindLimit binds and validates parameter Limit from query.
+func (o *ShortenedSummaryParams) bindLimit(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: false
+	// AllowEmptyValue: false
+
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	value, err := swag.ConvertUint64(raw)
+	if err != nil {
+		return errors.InvalidType("limit", "query", "uint64", raw)
+	}
+	o.Limit = &value
+
+	if err := o.validateLimit(formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateLimit carries on validations for parameter Limit
+func (o *ShortenedSummaryParams) validateLimit(formats strfmt.Registry) error {
+
+	if err := validate.MaximumUint("limit", "query", *o.Limit, 1000, false); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// bindSort binds and validates parameter Sort from query.
+func (o *ShortenedSummaryParams) bindSort(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: false
+	// AllowEmptyValue: false
+
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+	o.Sort = &raw
+
+	if err := o.validateSort(formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateSort carries on validations for parameter Sort
+func (o *ShortenedSummaryParams) validateSort(formats strfmt.Registry) error {
+
+	if err := validate.EnumCase("sort", "query", *o.Sort, []interface{}{"createdAt", "shortenedURL", "visitCount"}, true); err != nil {
+		return err
+	}
+
+	return nil
+}
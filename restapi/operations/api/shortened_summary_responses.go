@@ -16,7 +16,7 @@ import (
 // ShortenedSummaryOKCode is the HTTP code returned for type ShortenedSummaryOK
 const ShortenedSummaryOKCode int = 200
 
-/*ShortenedSummaryOK The map of shortened URLs to Summary data.
+/*ShortenedSummaryOK The page of Summary data.
 
 swagger:response shortenedSummaryOK
 */
@@ -25,7 +25,7 @@ type ShortenedSummaryOK struct {
 	/*
 	  In: Body
 	*/
-	Payload map[string]*models.Summary `json:"body,omitempty"`
+	Payload *models.SummaryPage `json:"body,omitempty"`
 }
 
 // NewShortenedSummaryOK creates ShortenedSummaryOK with default headers values
@@ -35,13 +35,13 @@ func NewShortenedSummaryOK() *ShortenedSummaryOK {
 }
 
 // WithPayload adds the payload to the shortened summary o k response
-func (o *ShortenedSummaryOK) WithPayload(payload map[string]*models.Summary) *ShortenedSummaryOK {
+func (o *ShortenedSummaryOK) WithPayload(payload *models.SummaryPage) *ShortenedSummaryOK {
 	o.Payload = payload
 	return o
 }
 
 // SetPayload sets the payload to the shortened summary o k response
-func (o *ShortenedSummaryOK) SetPayload(payload map[string]*models.Summary) {
+func (o *ShortenedSummaryOK) SetPayload(payload *models.SummaryPage) {
 	o.Payload = payload
 }
 
@@ -49,14 +49,11 @@ func (o *ShortenedSummaryOK) SetPayload(payload map[string]*models.Summary) {
 func (o *ShortenedSummaryOK) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
 
 	rw.WriteHeader(200)
-	payload := o.Payload
-	if payload == nil {
-		// return empty map
-		payload = make(map[string]*models.Summary, 50)
-	}
-
-	if err := producer.Produce(rw, payload); err != nil {
-		panic(err) // let the recovery middleware deal with this
+	if o.Payload != nil {
+		payload := o.Payload
+		if err := producer.Produce(rw, payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
 	}
 }
 
@@ -12,6 +12,9 @@ import (
 	"github.com/go-openapi/errors"
 	"github.com/go-openapi/runtime"
 	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	"github.com/go-openapi/validate"
 )
 
 // NewVisitsDeleteParams creates a new VisitsDeleteParams object
@@ -31,11 +34,24 @@ type VisitsDeleteParams struct {
 	// HTTP Request Object
 	HTTPRequest *http.Request `json:"-"`
 
+	/*Only delete visits at or after this time.
+	  In: query
+	*/
+	From *strfmt.DateTime
+	/*Only delete visits from these comma separated IP addresses or CIDR blocks.
+	  In: query
+	  Collection Format: csv
+	*/
+	IP []string
 	/*The shortened URLs whose Visits data should be deleted.
 	  Required: true
 	  In: body
 	*/
 	ShortenedURLs []string
+	/*Only delete visits before this time. Use this to delete visits older than a cutoff.
+	  In: query
+	*/
+	To *strfmt.DateTime
 }
 
 // BindRequest both binds and validates a request, it assumes that complex things implement a Validatable(strfmt.Registry) error interface
@@ -47,6 +63,18 @@ func (o *VisitsDeleteParams) BindRequest(r *http.Request, route *middleware.Matc
 
 	o.HTTPRequest = r
 
+	qs := runtime.Values(r.URL.Query())
+
+	qFrom, qhkFrom, _ := qs.GetOK("from")
+	if err := o.bindFrom(qFrom, qhkFrom, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qIP, qhkIP, _ := qs.GetOK("ip")
+	if err := o.bindIP(qIP, qhkIP, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
 	if runtime.HasBody(r) {
 		defer r.Body.Close()
 		var body []string
@@ -63,8 +91,114 @@ func (o *VisitsDeleteParams) BindRequest(r *http.Request, route *middleware.Matc
 	} else {
 		res = append(res, errors.Required("shortenedURLs", "body", ""))
 	}
+
+	qTo, qhkTo, _ := qs.GetOK("to")
+	if err := o.bindTo(qTo, qhkTo, route.Formats); err != nil {
+		res = append(res, err)
+	}
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
 	return nil
 }
+
+// bindFrom binds and validates parameter From from query.
+func (o *VisitsDeleteParams) bindFrom(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: false
+	// AllowEmptyValue: false
+
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	// Format: date-time
+	value, err := formats.Parse("date-time", raw)
+	if err != nil {
+		return errors.InvalidType("from", "query", "strfmt.DateTime", raw)
+	}
+	o.From = (value.(*strfmt.DateTime))
+
+	if err := o.validateFrom(formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateFrom carries on validations for parameter From
+func (o *VisitsDeleteParams) validateFrom(formats strfmt.Registry) error {
+
+	if err := validate.FormatOf("from", "query", "date-time", o.From.String(), formats); err != nil {
+		return err
+	}
+	return nil
+}
+
+// bindIP binds and validates array parameter IP from query.
+//
+// Arrays are parsed according to CollectionFormat: "csv" (defaults to "csv" when empty).
+func (o *VisitsDeleteParams) bindIP(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var qvIP string
+	if len(rawData) > 0 {
+		qvIP = rawData[len(rawData)-1]
+	}
+
+	// CollectionFormat: csv
+	iPIC := swag.SplitByFormat(qvIP, "csv")
+	if len(iPIC) == 0 {
+		return nil
+	}
+
+	var iPIR []string
+	for _, iPIV := range iPIC {
+		iPI := iPIV
+
+		iPIR = append(iPIR, iPI)
+	}
+
+	o.IP = iPIR
+
+	return nil
+}
+
+// bindTo binds and validates parameter To from query.
+func (o *VisitsDeleteParams) bindTo(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: false
+	// AllowEmptyValue: false
+
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	// Format: date-time
+	value, err := formats.Parse("date-time", raw)
+	if err != nil {
+		return errors.InvalidType("to", "query", "strfmt.DateTime", raw)
+	}
+	o.To = (value.(*strfmt.DateTime))
+
+	if err := o.validateTo(formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateTo carries on validations for parameter To
+func (o *VisitsDeleteParams) validateTo(formats strfmt.Registry) error {
+
+	if err := validate.FormatOf("to", "query", "date-time", o.To.String(), formats); err != nil {
+		return err
+	}
+	return nil
+}
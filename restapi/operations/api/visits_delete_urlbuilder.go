@@ -9,11 +9,20 @@ import (
 	"errors"
 	"net/url"
 	golangswaggerpaths "path"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
 )
 
 // VisitsDeleteURL generates an URL for the visits delete operation
 type VisitsDeleteURL struct {
+	From *strfmt.DateTime
+	IP   []string
+	To   *strfmt.DateTime
+
 	_basePath string
+	// avoid unkeyed usage
+	_ struct{}
 }
 
 // WithBasePath sets the base path for this url builder, only required when it's different from the
@@ -43,6 +52,43 @@ func (o *VisitsDeleteURL) Build() (*url.URL, error) {
 	}
 	_result.Path = golangswaggerpaths.Join(_basePath, _path)
 
+	qs := make(url.Values)
+
+	var fromQ string
+	if o.From != nil {
+		fromQ = o.From.String()
+	}
+	if fromQ != "" {
+		qs.Set("from", fromQ)
+	}
+
+	var iPIR []string
+	for _, iPI := range o.IP {
+		iPIS := iPI
+		if iPIS != "" {
+			iPIR = append(iPIR, iPIS)
+		}
+	}
+
+	ip := swag.JoinByFormat(iPIR, "csv")
+
+	if len(ip) > 0 {
+		qsv := ip[0]
+		if qsv != "" {
+			qs.Set("ip", qsv)
+		}
+	}
+
+	var toQ string
+	if o.To != nil {
+		toQ = o.To.String()
+	}
+	if toQ != "" {
+		qs.Set("to", toQ)
+	}
+
+	_result.RawQuery = qs.Encode()
+
 	return &_result, nil
 }
 
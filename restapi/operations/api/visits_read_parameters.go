@@ -12,6 +12,9 @@ import (
 	"github.com/go-openapi/errors"
 	"github.com/go-openapi/runtime"
 	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	"github.com/go-openapi/validate"
 )
 
 // NewVisitsReadParams creates a new VisitsReadParams object
@@ -31,11 +34,36 @@ type VisitsReadParams struct {
 	// HTTP Request Object
 	HTTPRequest *http.Request `json:"-"`
 
+	/*Only include visits at or after this time.
+	  In: query
+	*/
+	From *strfmt.DateTime
+	/*Only include visits with a value for the headerName header that contains this case insensitive substring.
+	  In: query
+	*/
+	HeaderContains *string
+	/*The header to match headerContains against. If headerContains is empty, only include visits that have this header.
+	  In: query
+	*/
+	HeaderName *string
+	/*Only include visits from these comma separated IP addresses or CIDR blocks.
+	  In: query
+	  Collection Format: csv
+	*/
+	IP []string
+	/*The maximum number of visits to include for each shortened URL. If empty, there is no limit.
+	  In: query
+	*/
+	Limit *uint64
 	/*The shortened URLs to read the Visits data for.
 	  Required: true
 	  In: body
 	*/
 	ShortenedURLs []string
+	/*Only include visits before this time.
+	  In: query
+	*/
+	To *strfmt.DateTime
 }
 
 // BindRequest both binds and validates a request, it assumes that complex things implement a Validatable(strfmt.Registry) error interface
@@ -47,6 +75,33 @@ func (o *VisitsReadParams) BindRequest(r *http.Request, route *middleware.Matche
 
 	o.HTTPRequest = r
 
+	qs := runtime.Values(r.URL.Query())
+
+	qFrom, qhkFrom, _ := qs.GetOK("from")
+	if err := o.bindFrom(qFrom, qhkFrom, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qHeaderContains, qhkHeaderContains, _ := qs.GetOK("headerContains")
+	if err := o.bindHeaderContains(qHeaderContains, qhkHeaderContains, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qHeaderName, qhkHeaderName, _ := qs.GetOK("headerName")
+	if err := o.bindHeaderName(qHeaderName, qhkHeaderName, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qIP, qhkIP, _ := qs.GetOK("ip")
+	if err := o.bindIP(qIP, qhkIP, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qLimit, qhkLimit, _ := qs.GetOK("limit")
+	if err := o.bindLimit(qLimit, qhkLimit, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
 	if runtime.HasBody(r) {
 		defer r.Body.Close()
 		var body []string
@@ -63,8 +118,173 @@ func (o *VisitsReadParams) BindRequest(r *http.Request, route *middleware.Matche
 	} else {
 		res = append(res, errors.Required("shortenedURLs", "body", ""))
 	}
+
+	qTo, qhkTo, _ := qs.GetOK("to")
+	if err := o.bindTo(qTo, qhkTo, route.Formats); err != nil {
+		res = append(res, err)
+	}
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
 	return nil
 }
+
+// bindFrom binds and validates parameter From from query.
+func (o *VisitsReadParams) bindFrom(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: false
+	// AllowEmptyValue: false
+
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	// Format: date-time
+	value, err := formats.Parse("date-time", raw)
+	if err != nil {
+		return errors.InvalidType("from", "query", "strfmt.DateTime", raw)
+	}
+	o.From = (value.(*strfmt.DateTime))
+
+	if err := o.validateFrom(formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateFrom carries on validations for parameter From
+func (o *VisitsReadParams) validateFrom(formats strfmt.Registry) error {
+
+	if err := validate.FormatOf("from", "query", "date-time", o.From.String(), formats); err != nil {
+		return err
+	}
+	return nil
+}
+
+// bindHeaderContains binds and validates parameter HeaderContains from query.
+func (o *VisitsReadParams) bindHeaderContains(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: false
+	// AllowEmptyValue: false
+
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+	o.HeaderContains = &raw
+
+	return nil
+}
+
+// bindHeaderName binds and validates parameter HeaderName from query.
+func (o *VisitsReadParams) bindHeaderName(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: false
+	// AllowEmptyValue: false
+
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+	o.HeaderName = &raw
+
+	return nil
+}
+
+// bindIP binds and validates array parameter IP from query.
+//
+// Arrays are parsed according to CollectionFormat: "csv" (defaults to "csv" when empty).
+func (o *VisitsReadParams) bindIP(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var qvIP string
+	if len(rawData) > 0 {
+		qvIP = rawData[len(rawData)-1]
+	}
+
+	// CollectionFormat: csv
+	iPIC := swag.SplitByFormat(qvIP, "csv")
+	if len(iPIC) == 0 {
+		return nil
+	}
+
+	var iPIR []string
+	for _, iPIV := range iPIC {
+		iPI := iPIV
+
+		iPIR = append(iPIR, iPI)
+	}
+
+	o.IP = iPIR
+
+	return nil
+}
+
+// bindLimit binds and validates parameter Limit from query.
+func (o *VisitsReadParams) bindLimit(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: false
+	// AllowEmptyValue: false
+
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	value, err := swag.ConvertUint64(raw)
+	if err != nil {
+		return errors.InvalidType("limit", "query", "uint64", raw)
+	}
+	o.Limit = &value
+
+	return nil
+}
+
+// bindTo binds and validates parameter To from query.
+func (o *VisitsReadParams) bindTo(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: false
+	// AllowEmptyValue: false
+
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	// Format: date-time
+	value, err := formats.Parse("date-time", raw)
+	if err != nil {
+		return errors.InvalidType("to", "query", "strfmt.DateTime", raw)
+	}
+	o.To = (value.(*strfmt.DateTime))
+
+	if err := o.validateTo(formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateTo carries on validations for parameter To
+func (o *VisitsReadParams) validateTo(formats strfmt.Registry) error {
+
+	if err := validate.FormatOf("to", "query", "date-time", o.To.String(), formats); err != nil {
+		return err
+	}
+	return nil
+}
@@ -9,11 +9,23 @@ import (
 	"errors"
 	"net/url"
 	golangswaggerpaths "path"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
 )
 
 // VisitsReadURL generates an URL for the visits read operation
 type VisitsReadURL struct {
+	From           *strfmt.DateTime
+	HeaderContains *string
+	HeaderName     *string
+	IP             []string
+	Limit          *uint64
+	To             *strfmt.DateTime
+
 	_basePath string
+	// avoid unkeyed usage
+	_ struct{}
 }
 
 // WithBasePath sets the base path for this url builder, only required when it's different from the
@@ -43,6 +55,67 @@ func (o *VisitsReadURL) Build() (*url.URL, error) {
 	}
 	_result.Path = golangswaggerpaths.Join(_basePath, _path)
 
+	qs := make(url.Values)
+
+	var fromQ string
+	if o.From != nil {
+		fromQ = o.From.String()
+	}
+	if fromQ != "" {
+		qs.Set("from", fromQ)
+	}
+
+	var headerContainsQ string
+	if o.HeaderContains != nil {
+		headerContainsQ = *o.HeaderContains
+	}
+	if headerContainsQ != "" {
+		qs.Set("headerContains", headerContainsQ)
+	}
+
+	var headerNameQ string
+	if o.HeaderName != nil {
+		headerNameQ = *o.HeaderName
+	}
+	if headerNameQ != "" {
+		qs.Set("headerName", headerNameQ)
+	}
+
+	var iPIR []string
+	for _, iPI := range o.IP {
+		iPIS := iPI
+		if iPIS != "" {
+			iPIR = append(iPIR, iPIS)
+		}
+	}
+
+	ip := swag.JoinByFormat(iPIR, "csv")
+
+	if len(ip) > 0 {
+		qsv := ip[0]
+		if qsv != "" {
+			qs.Set("ip", qsv)
+		}
+	}
+
+	var limitQ string
+	if o.Limit != nil {
+		limitQ = swag.FormatUint64(*o.Limit)
+	}
+	if limitQ != "" {
+		qs.Set("limit", limitQ)
+	}
+
+	var toQ string
+	if o.To != nil {
+		toQ = o.To.String()
+	}
+	if toQ != "" {
+		qs.Set("to", toQ)
+	}
+
+	_result.RawQuery = qs.Encode()
+
 	return &_result, nil
 }
 
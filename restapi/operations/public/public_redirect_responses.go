@@ -169,3 +169,45 @@ func (o *PublicRedirectNotFound) WriteResponse(rw http.ResponseWriter, producer
 
 	rw.WriteHeader(404)
 }
+
+// PublicRedirectGoneCode is the HTTP code returned for type PublicRedirectGone
+const PublicRedirectGoneCode int = 410
+
+/*PublicRedirectGone The shortened URL expired. The HTML document is the configured gone page.
+
+swagger:response publicRedirectGone
+*/
+type PublicRedirectGone struct {
+
+	/*
+	  In: Body
+	*/
+	Payload io.ReadCloser `json:"body,omitempty"`
+}
+
+// NewPublicRedirectGone creates PublicRedirectGone with default headers values
+func NewPublicRedirectGone() *PublicRedirectGone {
+
+	return &PublicRedirectGone{}
+}
+
+// WithPayload adds the payload to the public redirect gone response
+func (o *PublicRedirectGone) WithPayload(payload io.ReadCloser) *PublicRedirectGone {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the public redirect gone response
+func (o *PublicRedirectGone) SetPayload(payload io.ReadCloser) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *PublicRedirectGone) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(410)
+	payload := o.Payload
+	if err := producer.Produce(rw, payload); err != nil {
+		panic(err) // let the recovery middleware deal with this
+	}
+}
@@ -51,6 +51,9 @@ func NewTerseurlAPI(spec *loads.Document) *TerseurlAPI {
 		}),
 		JSONProducer: runtime.JSONProducer(),
 
+		APIAnalyticsHandler: apiops.AnalyticsHandlerFunc(func(params apiops.AnalyticsParams, principal *models.Principal) middleware.Responder {
+			return middleware.NotImplemented("operation api.Analytics has not yet been implemented")
+		}),
 		APIExportHandler: apiops.ExportHandlerFunc(func(params apiops.ExportParams, principal *models.Principal) middleware.Responder {
 			return middleware.NotImplemented("operation api.Export has not yet been implemented")
 		}),
@@ -63,6 +66,12 @@ func NewTerseurlAPI(spec *loads.Document) *TerseurlAPI {
 		PublicPublicRedirectHandler: public.PublicRedirectHandlerFunc(func(params public.PublicRedirectParams) middleware.Responder {
 			return middleware.NotImplemented("operation public.PublicRedirect has not yet been implemented")
 		}),
+		PublicPublicRedirectSuffixHandler: public.PublicRedirectSuffixHandlerFunc(func(params public.PublicRedirectSuffixParams) middleware.Responder {
+			return middleware.NotImplemented("operation public.PublicRedirectSuffix has not yet been implemented")
+		}),
+		APISearchHandler: apiops.SearchHandlerFunc(func(params apiops.SearchParams, principal *models.Principal) middleware.Responder {
+			return middleware.NotImplemented("operation api.Search has not yet been implemented")
+		}),
 		APIShortenedDeleteHandler: apiops.ShortenedDeleteHandlerFunc(func(params apiops.ShortenedDeleteParams, principal *models.Principal) middleware.Responder {
 			return middleware.NotImplemented("operation api.ShortenedDelete has not yet been implemented")
 		}),
@@ -140,6 +149,8 @@ type TerseurlAPI struct {
 	// APIAuthorizer provides access control (ACL/RBAC/ABAC) by providing access to the request and authenticated principal
 	APIAuthorizer runtime.Authorizer
 
+	// APIAnalyticsHandler sets the operation handler for the analytics operation
+	APIAnalyticsHandler apiops.AnalyticsHandler
 	// APIExportHandler sets the operation handler for the export operation
 	APIExportHandler apiops.ExportHandler
 	// APIFrontendMetaHandler sets the operation handler for the frontend meta operation
@@ -148,6 +159,10 @@ type TerseurlAPI struct {
 	APIImportHandler apiops.ImportHandler
 	// PublicPublicRedirectHandler sets the operation handler for the public redirect operation
 	PublicPublicRedirectHandler public.PublicRedirectHandler
+	// PublicPublicRedirectSuffixHandler sets the operation handler for the public redirect suffix operation
+	PublicPublicRedirectSuffixHandler public.PublicRedirectSuffixHandler
+	// APISearchHandler sets the operation handler for the search operation
+	APISearchHandler apiops.SearchHandler
 	// APIShortenedDeleteHandler sets the operation handler for the shortened delete operation
 	APIShortenedDeleteHandler apiops.ShortenedDeleteHandler
 	// APIShortenedPrefixHandler sets the operation handler for the shortened prefix operation
@@ -248,6 +263,9 @@ func (o *TerseurlAPI) Validate() error {
 		unregistered = append(unregistered, "AuthorizationAuth")
 	}
 
+	if o.APIAnalyticsHandler == nil {
+		unregistered = append(unregistered, "api.AnalyticsHandler")
+	}
 	if o.APIExportHandler == nil {
 		unregistered = append(unregistered, "api.ExportHandler")
 	}
@@ -260,6 +278,12 @@ func (o *TerseurlAPI) Validate() error {
 	if o.PublicPublicRedirectHandler == nil {
 		unregistered = append(unregistered, "public.PublicRedirectHandler")
 	}
+	if o.PublicPublicRedirectSuffixHandler == nil {
+		unregistered = append(unregistered, "public.PublicRedirectSuffixHandler")
+	}
+	if o.APISearchHandler == nil {
+		unregistered = append(unregistered, "api.SearchHandler")
+	}
 	if o.APIShortenedDeleteHandler == nil {
 		unregistered = append(unregistered, "api.ShortenedDeleteHandler")
 	}
@@ -385,6 +409,10 @@ func (o *TerseurlAPI) initHandlerCache() {
 		o.handlers = make(map[string]map[string]http.Handler)
 	}
 
+	if o.handlers["POST"] == nil {
+		o.handlers["POST"] = make(map[string]http.Handler)
+	}
+	o.handlers["POST"]["/api/analytics"] = apiops.NewAnalytics(o.context, o.APIAnalyticsHandler)
 	if o.handlers["POST"] == nil {
 		o.handlers["POST"] = make(map[string]http.Handler)
 	}
@@ -401,6 +429,14 @@ func (o *TerseurlAPI) initHandlerCache() {
 		o.handlers["GET"] = make(map[string]http.Handler)
 	}
 	o.handlers["GET"]["/{shortenedURL}"] = public.NewPublicRedirect(o.context, o.PublicPublicRedirectHandler)
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/{shortenedURL}/{suffix}"] = public.NewPublicRedirectSuffix(o.context, o.PublicPublicRedirectSuffixHandler)
+	if o.handlers["POST"] == nil {
+		o.handlers["POST"] = make(map[string]http.Handler)
+	}
+	o.handlers["POST"]["/api/search"] = apiops.NewSearch(o.context, o.APISearchHandler)
 	if o.handlers["DELETE"] == nil {
 		o.handlers["DELETE"] = make(map[string]http.Handler)
 	}
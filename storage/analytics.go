@@ -0,0 +1,185 @@
+package storage
+
+import (
+	"errors"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+
+	"github.com/MicahParks/terseurl/models"
+)
+
+const (
+
+	// IntervalDay counts visits by day.
+	IntervalDay Interval = "day"
+
+	// IntervalHour counts visits by hour.
+	IntervalHour Interval = "hour"
+
+	// IntervalWeek counts visits by week. Weeks start on Monday.
+	IntervalWeek Interval = "week"
+
+	// maxSeriesPoints is the maximum number of intervals in a time series.
+	maxSeriesPoints = 10000
+)
+
+// ErrTooManyPoints indicates the time series would have more intervals than allowed.
+var ErrTooManyPoints = errors.New("the time series has too many intervals")
+
+// Interval is the length of each interval in a time series.
+type Interval string
+
+// Series describes a time series of visit counts.
+type Series struct {
+
+	// From only counts visits at or after this time. If zero, the series starts at the first visit.
+	From time.Time
+
+	// Interval is the length of each interval. If empty, IntervalDay is used.
+	Interval Interval
+
+	// Location is the time zone the intervals start in. If nil, UTC is used.
+	Location *time.Location
+
+	// To only counts visits before this time. If zero, the series ends at the last visit.
+	To time.Time
+}
+
+// analytics creates the time series for the given visit counts of each shortened URL and the total time series for all
+// of them. All the time series have the same intervals. The error will be storage.ErrTooManyPoints if there are too
+// many intervals.
+func (s Series) analytics(histograms map[string]map[int64]uint64) (analytics *models.Analytics, err error) {
+
+	// Add the visit counts of each shortened URL to the total.
+	total := make(map[int64]uint64)
+	for _, counts := range histograms {
+		for start, count := range counts {
+			total[start] += count
+		}
+	}
+
+	// Find the first and last intervals.
+	first, last, ok := s.bounds(total)
+
+	// Create the time series.
+	analytics = &models.Analytics{
+		Interval:      models.AnalyticsInterval(s.interval()),
+		ShortenedURLs: make(map[string][]models.AnalyticsPoint, len(histograms)),
+		Timezone:      s.location().String(),
+	}
+	if analytics.Total, err = s.points(total, first, last, ok); err != nil {
+		return nil, err
+	}
+	for shortened, counts := range histograms {
+		if analytics.ShortenedURLs[shortened], err = s.points(counts, first, last, ok); err != nil {
+			return nil, err
+		}
+	}
+
+	return analytics, nil
+}
+
+// bounds finds the start of the first and last intervals of the time series. The time window is used if given,
+// otherwise the first and last visits are used. If ok is false, the time series has no intervals.
+func (s Series) bounds(counts map[int64]uint64) (first, last time.Time, ok bool) {
+
+	// Find the first and last intervals with visits.
+	for start := range counts {
+		t := time.Unix(start, 0).In(s.location())
+		if !ok || t.Before(first) {
+			first = t
+		}
+		if !ok || t.After(last) {
+			last = t
+		}
+		ok = true
+	}
+
+	// Use the time window instead, if given.
+	if !s.From.IsZero() {
+		first = s.start(s.From)
+	}
+	if !s.To.IsZero() {
+		last = s.start(s.To.Add(-time.Nanosecond))
+	}
+
+	return first, last, ok || !s.From.IsZero() && !s.To.IsZero()
+}
+
+// count adds the given number of visits at the given time to the visit counts, which are keyed by the Unix time of the
+// start of each interval. Visits outside the time window are not counted.
+func (s Series) count(counts map[int64]uint64, t time.Time, visits uint64) {
+	if (VisitsFilter{From: s.From, To: s.To}).InWindow(t) {
+		counts[s.start(t).Unix()] += visits
+	}
+}
+
+// interval returns the length of each interval.
+func (s Series) interval() (interval Interval) {
+	if s.Interval == "" {
+		return IntervalDay
+	}
+	return s.Interval
+}
+
+// location returns the time zone the intervals start in.
+func (s Series) location() (location *time.Location) {
+	if s.Location == nil {
+		return time.UTC
+	}
+	return s.Location
+}
+
+// next returns the start of the interval after the interval with the given start.
+func (s Series) next(start time.Time) (next time.Time) {
+	switch s.interval() {
+	case IntervalHour:
+		return start.Add(time.Hour)
+	case IntervalWeek:
+		return time.Date(start.Year(), start.Month(), start.Day()+7, 0, 0, 0, 0, s.location())
+	default:
+		return time.Date(start.Year(), start.Month(), start.Day()+1, 0, 0, 0, 0, s.location())
+	}
+}
+
+// points creates the points of the time series from the first to the last interval. Intervals without visits have a
+// count of zero. The error will be storage.ErrTooManyPoints if there are too many intervals.
+func (s Series) points(counts map[int64]uint64, first, last time.Time, ok bool) (points []models.AnalyticsPoint, err error) {
+	points = make([]models.AnalyticsPoint, 0)
+
+	// Check for the empty case.
+	if !ok {
+		return points, nil
+	}
+
+	// Create a point for every interval.
+	for start := first; !start.After(last); start = s.next(start) {
+		if len(points) == maxSeriesPoints {
+			return nil, ErrTooManyPoints
+		}
+		dateTime := strfmt.DateTime(start)
+		points = append(points, models.AnalyticsPoint{
+			Count: counts[start.Unix()],
+			Start: &dateTime,
+		})
+	}
+
+	return points, nil
+}
+
+// start returns the start of the interval the given time is in.
+func (s Series) start(t time.Time) (start time.Time) {
+	t = t.In(s.location())
+	switch s.interval() {
+	case IntervalHour:
+
+		// Subtract instead of using time.Date, so the repeated hour at the end of daylight saving time is not merged.
+		return t.Add(-time.Duration(t.Minute())*time.Minute - time.Duration(t.Second())*time.Second - time.Duration(t.Nanosecond()))
+	case IntervalWeek:
+		sinceMonday := (int(t.Weekday()) + 6) % 7
+		return time.Date(t.Year(), t.Month(), t.Day()-sinceMonday, 0, 0, 0, 0, s.location())
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, s.location())
+	}
+}
@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"context"
+
+	"go.etcd.io/bbolt"
+
+	"github.com/MicahParks/terseurl/models"
+)
+
+// BboltAuthorization is an AuthorizationStore implementation that relies on a bbolt file for the backend storage. The
+// bucket maps shortened URLs to the subject of their owner.
+type BboltAuthorization struct {
+	db                  *bbolt.DB
+	authorizationBucket []byte
+}
+
+// NewBboltAuthorization creates a new BboltAuthorization given the required assets.
+func NewBboltAuthorization(db *bbolt.DB, authorizationBucket []byte) (authStore AuthorizationStore) {
+	return BboltAuthorization{
+		db:                  db,
+		authorizationBucket: authorizationBucket,
+	}
+}
+
+// AuthorizedShortened creates a map of users to the shortened URLs they are authorized for.
+func (b BboltAuthorization) AuthorizedShortened(_ context.Context, users []*models.Principal) (authorized map[*models.Principal][]string, err error) {
+
+	// Create the return map.
+	authorized = make(map[*models.Principal][]string, len(users))
+
+	// Make a map of subjects to users for quick lookup.
+	subjects := make(map[string][]*models.Principal, len(users))
+	for _, user := range users {
+		subjects[user.Sub] = append(subjects[user.Sub], user)
+		authorized[user] = make([]string, 0)
+	}
+
+	// Create the forEachFunc.
+	var forEach forEachFunc = func(shortened, data []byte) (err error) {
+
+		// Add the shortened URL to every user with the owning subject.
+		for _, user := range subjects[string(data)] {
+			authorized[user] = append(authorized[user], string(shortened))
+		}
+
+		return nil
+	}
+
+	// Read the Authorization data into the return map.
+	if err = bboltRead(b, forEach, nil); err != nil {
+		return nil, err
+	}
+
+	return authorized, nil
+}
+
+// BucketName returns the name of the bbolt bucket.
+func (b BboltAuthorization) BucketName() (bucketName []byte) {
+	return b.authorizationBucket
+}
+
+// Close closes the connection to the underlying storage.
+func (b BboltAuthorization) Close(_ context.Context) (err error) {
+
+	// Close the bbolt database file.
+	return b.db.Close()
+}
+
+// DB returns the bbolt database.
+func (b BboltAuthorization) DB() (db *bbolt.DB) {
+	return b.db
+}
+
+// Delete deletes the authorization information for the given shortened URLs. If shortenedURLs is nil or empty, all
+// Authorization data are deleted. No error should be returned if a shortened URL is not found.
+func (b BboltAuthorization) Delete(_ context.Context, shortenedURLs []string) (err error) {
+	return bboltDelete(b, shortenedURLs)
+}
+
+// Upsert upserts the owner of the given shortened URLs. The owner is identified by the principal's subject.
+func (b BboltAuthorization) Upsert(_ context.Context, owners map[string]*models.Principal) (err error) {
+
+	// Open the bbolt database for writing, batch if possible.
+	if err = b.db.Batch(func(tx *bbolt.Tx) error {
+
+		// Iterate through the given shortened URLs.
+		for shortened, owner := range owners {
+
+			// Write the owner's subject to the bucket.
+			if err = tx.Bucket(b.authorizationBucket).Put([]byte(shortened), []byte(owner.Sub)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -1,24 +1,33 @@
 package storage
 
 import (
+	"bytes"
 	"context"
+	"time"
 
+	"github.com/go-openapi/strfmt"
 	"go.etcd.io/bbolt"
 
 	"github.com/MicahParks/terseurl/models"
 )
 
-// BboltTerse is a TerseStore implementation that relies on a bbolt file for the backend storage.
+// BboltTerse is a TerseStore implementation that relies on a bbolt file for the backend storage. The created bucket is
+// an index whose keys are page keys sorted by creation time. The dedupe bucket is an index whose keys are a dedupe key
+// and a shortened URL separated by a null byte.
 type BboltTerse struct {
-	db          *bbolt.DB
-	terseBucket []byte
+	db            *bbolt.DB
+	createdBucket []byte
+	dedupeBucket  []byte
+	terseBucket   []byte
 }
 
 // NewBboltTerse creates a new BboltTerse given the required assets.
-func NewBboltTerse(db *bbolt.DB, terseBucket []byte) (terseStore TerseStore) {
+func NewBboltTerse(db *bbolt.DB, createdBucket, dedupeBucket, terseBucket []byte) (terseStore TerseStore) {
 	return BboltTerse{
-		db:          db,
-		terseBucket: terseBucket,
+		db:            db,
+		createdBucket: createdBucket,
+		dedupeBucket:  dedupeBucket,
+		terseBucket:   terseBucket,
 	}
 }
 
@@ -42,7 +51,120 @@ func (b BboltTerse) DB() (db *bbolt.DB) {
 // Delete deletes the Terse data for the given shortened URLs. If shortenedURLs is nil or empty, all shortened URL
 // Terse data are deleted. There should be no error if a shortened URL is not found.
 func (b BboltTerse) Delete(_ context.Context, shortenedURLs []string) (err error) {
-	return bboltDelete(b, shortenedURLs)
+
+	// Check for the empty case.
+	if len(shortenedURLs) == 0 {
+
+		// Open the bbolt database for exclusive writing.
+		return b.db.Update(func(tx *bbolt.Tx) error {
+
+			// Delete and recreate the Terse and index buckets.
+			for _, bucket := range [][]byte{b.createdBucket, b.dedupeBucket, b.terseBucket} {
+				if err = tx.DeleteBucket(bucket); err != nil {
+					return err
+				}
+				if _, err = tx.CreateBucket(bucket); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	}
+
+	// Open the bbolt database for writing, batch if possible.
+	return b.db.Batch(func(tx *bbolt.Tx) error {
+
+		// Iterate through the given shortened URLs.
+		for _, shortened := range shortenedURLs {
+
+			// Remove the shortened URL from the indexes.
+			if err = b.indexRemove(tx, shortened); err != nil {
+				return err
+			}
+
+			// Delete the shortened URL's Terse data from the bucket.
+			if err = tx.Bucket(b.terseBucket).Delete([]byte(shortened)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// DeleteExpired deletes the Terse data for the given shortened URLs that have expired at the given time with the given
+// visit counts. Expiration is checked when the Terse data are deleted, so Terse data updated after they were found to
+// be expired are kept. The shortened URLs whose Terse data were deleted are returned. There should be no error if a
+// shortened URL is not found.
+func (b BboltTerse) DeleteExpired(_ context.Context, visitCounts map[string]uint64, now time.Time) (deleted []string, err error) {
+
+	// Open the bbolt database for exclusive writing.
+	if err = b.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(b.terseBucket)
+
+		// Iterate through the given shortened URLs.
+		deleted = make([]string, 0, len(visitCounts))
+		for shortened, visitCount := range visitCounts {
+
+			// Only delete the Terse data if it has expired.
+			data := bucket.Get([]byte(shortened))
+			if data == nil {
+				continue
+			}
+			terse, err := bytesToTerse(data)
+			if err != nil {
+				return err
+			}
+			if !terseExpired(terse, visitCount, now) {
+				continue
+			}
+
+			// Remove the shortened URL from the indexes, then delete its Terse data.
+			if err = b.indexRemove(tx, shortened); err != nil {
+				return err
+			}
+			if err = bucket.Delete([]byte(shortened)); err != nil {
+				return err
+			}
+			deleted = append(deleted, shortened)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return deleted, nil
+}
+
+// Deduplicate returns a map of the given dedupe keys to the shortened URLs whose Terse data have that dedupe key.
+// Dedupe keys without any shortened URLs are not in the map. See storage.DedupeKey.
+func (b BboltTerse) Deduplicate(_ context.Context, keys []string) (shortenedURLs map[string][]string, err error) {
+
+	// Create the return map.
+	shortenedURLs = make(map[string][]string, len(keys))
+
+	// Open the bbolt database for reading.
+	if err = b.db.View(func(tx *bbolt.Tx) error {
+		cursor := tx.Bucket(b.dedupeBucket).Cursor()
+
+		// Iterate through the given dedupe keys.
+		for _, key := range keys {
+
+			// Add every shortened URL with the dedupe key prefix to the return map.
+			prefix := dedupeIndexKey(key, "")
+			for k, _ := cursor.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = cursor.Next() {
+				shortenedURLs[key] = append(shortenedURLs[key], string(k[len(prefix):]))
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return shortenedURLs, nil
 }
 
 // Read returns a map of shortened URLs to Terse data. If shortenedURLs is nil or empty, all shortened URL Terse
@@ -75,6 +197,139 @@ func (b BboltTerse) Read(_ context.Context, shortenedURLs []string) (terseData m
 	return terseData, nil
 }
 
+// ReadPage returns the Terse data for the given shortened URLs as a sorted page. If shortenedURLs is nil or empty, all
+// shortened URL Terse data are paginated. The next return value is the cursor for the following page. It is empty if
+// there are no more results. The error must be storage.ErrShortenedNotFound if a shortened URL is not found. The error
+// must be storage.ErrInvalidCursor if the page's cursor cannot be used. The error must be storage.ErrUnsupportedSort
+// if the page is sorted by visit count.
+func (b BboltTerse) ReadPage(_ context.Context, page Page, shortenedURLs []string) (terseData []*models.Terse, next string, err error) {
+
+	// The visit count is not part of the Terse data.
+	if page.Sort == SortVisitCount {
+		return nil, "", ErrUnsupportedSort
+	}
+
+	// Open the bbolt database for reading.
+	if err = b.db.View(func(tx *bbolt.Tx) error {
+		terseBucket := tx.Bucket(b.terseBucket)
+
+		// Get the page keys in the requested page.
+		var keys []string
+		if len(shortenedURLs) == 0 {
+
+			// Use a cursor on the bucket whose keys are sorted in the requested order.
+			bucket := terseBucket
+			if page.Sort == SortCreatedAt {
+				bucket = tx.Bucket(b.createdBucket)
+			}
+			if keys, next, err = bboltPageKeys(bucket, page); err != nil {
+				return err
+			}
+		} else {
+
+			// Create the page keys for the given shortened URLs.
+			keys = make([]string, 0, len(shortenedURLs))
+			for _, shortened := range shortenedURLs {
+				data := terseBucket.Get([]byte(shortened))
+				if data == nil {
+					return ErrShortenedNotFound
+				}
+				terse, err := bytesToTerse(data)
+				if err != nil {
+					return err
+				}
+				keys = append(keys, pageKey(page.Sort, shortened, terse.CreatedAt, 0))
+			}
+
+			// Only use the page keys in the requested page.
+			if keys, next, err = page.paginate(keys); err != nil {
+				return err
+			}
+		}
+
+		// Add the Terse data to the return slice in order.
+		terseData = make([]*models.Terse, len(keys))
+		for i, key := range keys {
+			terse, err := bytesToTerse(terseBucket.Get([]byte(pageKeyShortened(key))))
+			if err != nil {
+				return err
+			}
+			terseData[i] = &terse
+		}
+
+		return nil
+	}); err != nil {
+		return nil, "", err
+	}
+
+	return terseData, next, nil
+}
+
+// Search returns the Terse data whose original URL matches the query, sorted by shortened URL. Only shortened URLs
+// after query.After are searched and at most query.Limit Terse data are returned. If shortenedURLs is nil or empty,
+// all shortened URLs are searched. The next return value is the last returned shortened URL if there are more
+// matches, otherwise it is empty.
+func (b BboltTerse) Search(_ context.Context, query models.SearchQuery, shortenedURLs []string) (terseData []*models.Terse, next string, err error) {
+
+	// Create the return slice.
+	terseData = make([]*models.Terse, 0)
+
+	// Make a set of the shortened URLs to search, if given.
+	var allowed map[string]struct{}
+	if len(shortenedURLs) != 0 {
+		allowed = make(map[string]struct{}, len(shortenedURLs))
+		for _, shortened := range shortenedURLs {
+			allowed[shortened] = struct{}{}
+		}
+	}
+
+	// Open the bbolt database for reading.
+	if err = b.db.View(func(tx *bbolt.Tx) error {
+		cursor := tx.Bucket(b.terseBucket).Cursor()
+
+		// Iterate through the shortened URLs after the given one. The keys are sorted.
+		k, data := cursor.Seek([]byte(query.After))
+		if k != nil && string(k) == query.After {
+			k, data = cursor.Next()
+		}
+		for ; k != nil; k, data = cursor.Next() {
+
+			// Only search the allowed shortened URLs.
+			if allowed != nil {
+				if _, ok := allowed[string(k)]; !ok {
+					continue
+				}
+			}
+
+			// Turn the raw data into Terse data.
+			terse, err := bytesToTerse(data)
+			if err != nil {
+				return err
+			}
+
+			// Only use the Terse data that matches.
+			if !terseMatches(terse, query.Match, query.Value) {
+				continue
+			}
+
+			// Stop if there are more matches than the limit.
+			if uint64(len(terseData)) == query.Limit {
+				next = terseData[len(terseData)-1].ShortenedURL
+				break
+			}
+
+			// Add the Terse data to the return slice.
+			terseData = append(terseData, &terse)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, "", err
+	}
+
+	return terseData, next, nil
+}
+
 // Summary summarizes the Terse data for the given shortened URLs. If shortenedURLs is nil or empty, then all
 // shortened URL Summary data are expected.
 func (b BboltTerse) Summary(_ context.Context, shortenedURLs []string) (summaries map[string]*models.TerseSummary, err error) {
@@ -107,7 +362,8 @@ func (b BboltTerse) Summary(_ context.Context, shortenedURLs []string) (summarie
 
 // Write writes the given Terse data according to the given operation. The error must be storage.ErrShortenedExists
 // if an Insert operation cannot be performed due to the Terse data already existing. The error must be
-// storage.ErrShortenedNotFound if an Update operation cannot be performed due to the Terse data not existing.
+// storage.ErrShortenedNotFound if an Update operation cannot be performed due to the Terse data not existing. Terse
+// data without a creation time are given the existing creation time, or the current time if the shortened URL is new.
 func (b BboltTerse) Write(_ context.Context, terseData map[string]*models.Terse, operation WriteOperation) (err error) {
 
 	// Open the bbolt database for writing, batch if possible.
@@ -117,13 +373,25 @@ func (b BboltTerse) Write(_ context.Context, terseData map[string]*models.Terse,
 		for shortened, terse := range terseData {
 
 			// Check to see if the shortened URL is present in the bucket.
-			if operation == Insert || operation == Update {
-				value := tx.Bucket(b.terseBucket).Get([]byte(shortened))
-				if value != nil && operation == Insert {
-					return ErrShortenedExists
-				}
-				if value == nil && operation == Update {
-					return ErrShortenedNotFound
+			value := tx.Bucket(b.terseBucket).Get([]byte(shortened))
+			if value != nil && operation == Insert {
+				return ErrShortenedExists
+			}
+			if value == nil && operation == Update {
+				return ErrShortenedNotFound
+			}
+
+			// Keep the creation time of existing Terse data.
+			if terse.CreatedAt == nil {
+				if value != nil {
+					old, err := bytesToTerse(value)
+					if err != nil {
+						return err
+					}
+					terse.CreatedAt = old.CreatedAt
+				} else {
+					now := strfmt.DateTime(time.Now())
+					terse.CreatedAt = &now
 				}
 			}
 
@@ -133,10 +401,24 @@ func (b BboltTerse) Write(_ context.Context, terseData map[string]*models.Terse,
 				return err
 			}
 
+			// Remove the old Terse data from the indexes.
+			if err = b.indexRemove(tx, shortened); err != nil {
+				return err
+			}
+
 			// Write the Terse data to the bucket.
 			if err = tx.Bucket(b.terseBucket).Put([]byte(shortened), data); err != nil {
 				return err
 			}
+
+			// Add the new Terse data to the indexes.
+			createdKey, dedupeKey := indexKeys(shortened, *terse)
+			if err = tx.Bucket(b.createdBucket).Put(createdKey, []byte{}); err != nil {
+				return err
+			}
+			if err = tx.Bucket(b.dedupeBucket).Put(dedupeKey, []byte{}); err != nil {
+				return err
+			}
 		}
 
 		return nil
@@ -146,3 +428,127 @@ func (b BboltTerse) Write(_ context.Context, terseData map[string]*models.Terse,
 
 	return nil
 }
+
+// indexRemove removes the shortened URL's current Terse data from the indexes, if it exists.
+func (b BboltTerse) indexRemove(tx *bbolt.Tx, shortened string) (err error) {
+
+	// Get the current Terse data.
+	data := tx.Bucket(b.terseBucket).Get([]byte(shortened))
+	if data == nil {
+		return nil
+	}
+	terse, err := bytesToTerse(data)
+	if err != nil {
+		return err
+	}
+
+	// Delete the shortened URL from the indexes.
+	createdKey, dedupeKey := indexKeys(shortened, terse)
+	if err = tx.Bucket(b.createdBucket).Delete(createdKey); err != nil {
+		return err
+	}
+	return tx.Bucket(b.dedupeBucket).Delete(dedupeKey)
+}
+
+// bboltPageKeys gets the page keys in the requested page from the given bucket, whose keys must be page keys. The next
+// return value is the cursor for the following page. It is empty if there are no more results.
+func bboltPageKeys(bucket *bbolt.Bucket, page Page) (keys []string, next string, err error) {
+
+	// Get the page key of the last result from the previous page.
+	after, err := page.after()
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Move the cursor to the first page key in the page.
+	cursor := bucket.Cursor()
+	var k []byte
+	switch {
+	case after == "" && page.Descending:
+		k, _ = cursor.Last()
+	case after == "":
+		k, _ = cursor.First()
+	case page.Descending:
+		if k, _ = cursor.Seek([]byte(after)); k == nil {
+			k, _ = cursor.Last()
+		} else {
+			k, _ = cursor.Prev()
+		}
+	default:
+		if k, _ = cursor.Seek([]byte(after)); k != nil && string(k) == after {
+			k, _ = cursor.Next()
+		}
+	}
+
+	// Gather the page keys, plus one to know if there is a following page.
+	for ; k != nil; k, _ = moveCursor(cursor, page.Descending) {
+		if page.Limit != 0 && uint64(len(keys)) == page.Limit {
+			next = page.cursor(keys[len(keys)-1])
+			break
+		}
+		keys = append(keys, string(k))
+	}
+
+	return keys, next, nil
+}
+
+// createIndexes creates the created and dedupe buckets in the given bbolt database, if they don't already exist. When an
+// index is created, it is filled from the existing Terse data.
+func createIndexes(db *bbolt.DB, createdBucket, dedupeBucket, terseBucket []byte) (err error) {
+	return db.Update(func(tx *bbolt.Tx) error {
+
+		// Only fill the indexes that are created.
+		var created, dedupe *bbolt.Bucket
+		if tx.Bucket(createdBucket) == nil {
+			if created, err = tx.CreateBucket(createdBucket); err != nil {
+				return err
+			}
+		}
+		if tx.Bucket(dedupeBucket) == nil {
+			if dedupe, err = tx.CreateBucket(dedupeBucket); err != nil {
+				return err
+			}
+		}
+		if created == nil && dedupe == nil {
+			return nil
+		}
+
+		// Add all existing Terse data to the created indexes.
+		return tx.Bucket(terseBucket).ForEach(func(shortened, data []byte) error {
+			terse, err := bytesToTerse(data)
+			if err != nil {
+				return err
+			}
+			createdKey, dedupeKey := indexKeys(string(shortened), terse)
+			if created != nil {
+				if err = created.Put(createdKey, []byte{}); err != nil {
+					return err
+				}
+			}
+			if dedupe != nil {
+				return dedupe.Put(dedupeKey, []byte{})
+			}
+			return nil
+		})
+	})
+}
+
+// dedupeIndexKey creates the bbolt key for the dedupe index from the dedupe key and shortened URL.
+func dedupeIndexKey(key, shortened string) (indexKey []byte) {
+	return []byte(key + "\x00" + shortened)
+}
+
+// indexKeys creates the bbolt keys for the created and dedupe indexes from the shortened URL's Terse data.
+func indexKeys(shortened string, terse models.Terse) (createdKey, dedupeKey []byte) {
+	createdKey = []byte(pageKey(SortCreatedAt, shortened, terse.CreatedAt, 0))
+	dedupeKey = dedupeIndexKey(DedupeKey(terse.OriginalURL, terse.RedirectType), shortened)
+	return createdKey, dedupeKey
+}
+
+// moveCursor moves the cursor to the next key in the given order.
+func moveCursor(cursor *bbolt.Cursor, descending bool) (key, value []byte) {
+	if descending {
+		return cursor.Prev()
+	}
+	return cursor.Next()
+}
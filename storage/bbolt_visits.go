@@ -1,24 +1,32 @@
 package storage
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
+	"time"
 
 	"go.etcd.io/bbolt"
 
 	"github.com/MicahParks/terseurl/models"
 )
 
-// BboltVisits if a VisitsStore implementation that relies on a bbolt file for the backend storage.
+// BboltVisits if a VisitsStore implementation that relies on a bbolt file for the backend storage. Each shortened URL
+// has a nested bucket in the Visits bucket. Each visit is a single key in the nested bucket, so inserting a visit does
+// not rewrite the existing visits. The keys sort by the time of the visit. See visitKey. The aggregate counts and unique
+// visitor Sketch of pruned visits are kept in a nested bucket per shortened URL in the rollups bucket. See visitCounts.
 type BboltVisits struct {
-	db           *bbolt.DB
-	visitsBucket []byte
+	db            *bbolt.DB
+	rollupsBucket []byte
+	visitsBucket  []byte
 }
 
 // NewBboltVisits creates a new NewBboltVisits given the required assets.
-func NewBboltVisits(db *bbolt.DB, visitsBucket []byte) (visitsStore VisitsStore) {
+func NewBboltVisits(db *bbolt.DB, rollupsBucket, visitsBucket []byte) (visitsStore VisitsStore) {
 	return BboltVisits{
-		db:           db,
-		visitsBucket: visitsBucket,
+		db:            db,
+		rollupsBucket: rollupsBucket,
+		visitsBucket:  visitsBucket,
 	}
 }
 
@@ -39,10 +47,206 @@ func (b BboltVisits) DB() (db *bbolt.DB) {
 	return b.db
 }
 
-// Delete deletes Visits data for the given shortened URLs. If shortenedURLs is nil or empty, then all Visits data
-// are deleted. No error should be given if a shortened URL is not found.
+// Delete deletes Visits data for the given shortened URLs, including the aggregate counts of pruned visits. If
+// shortenedURLs is nil or empty, then all Visits data are deleted. No error should be given if a shortened URL is not
+// found.
 func (b BboltVisits) Delete(_ context.Context, shortenedURLs []string) (err error) {
-	return bboltDelete(b, shortenedURLs)
+
+	// Check for the empty case.
+	if len(shortenedURLs) == 0 {
+
+		// Open the bbolt database for exclusive writing.
+		return b.db.Update(func(tx *bbolt.Tx) error {
+
+			// Delete and recreate the rollups and Visits buckets, which deletes all the nested buckets.
+			for _, bucketName := range [][]byte{b.rollupsBucket, b.visitsBucket} {
+				if err = tx.DeleteBucket(bucketName); err != nil {
+					return err
+				}
+				if _, err = tx.CreateBucket(bucketName); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	}
+
+	// Open the bbolt database for writing, batch if possible.
+	return b.db.Batch(func(tx *bbolt.Tx) error {
+
+		// Iterate through the given shortened URLs.
+		for _, shortened := range shortenedURLs {
+
+			// Delete the shortened URL's nested buckets.
+			for _, bucketName := range [][]byte{b.rollupsBucket, b.visitsBucket} {
+				if err = tx.Bucket(bucketName).DeleteBucket([]byte(shortened)); err != nil && err != bbolt.ErrBucketNotFound {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// DeleteFiltered deletes the visits that match the filter for the given shortened URLs. The filter's limit is not used.
+// The aggregate counts of pruned visits are not deleted. If shortenedURLs is nil or empty, then the visits of all
+// shortened URLs are filtered. No error should be given if a shortened URL is not found.
+func (b BboltVisits) DeleteFiltered(_ context.Context, filter VisitsFilter, shortenedURLs []string) (err error) {
+
+	// Open the bbolt database for exclusive writing.
+	return b.db.Update(func(tx *bbolt.Tx) error {
+		visitsBucket := tx.Bucket(b.visitsBucket)
+
+		// Check for the empty case.
+		if len(shortenedURLs) == 0 {
+
+			// Filter all the shortened URLs. Nested buckets have a nil value.
+			if err = visitsBucket.ForEach(func(shortened, value []byte) error {
+				if value == nil {
+					shortenedURLs = append(shortenedURLs, string(shortened))
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+
+		// Only decode the visits if something other than the time is filtered.
+		decode := len(filter.IPNets) != 0 || filter.HeaderName != ""
+
+		// Iterate through the shortened URLs.
+		for _, shortened := range shortenedURLs {
+
+			// Get the shortened URL's nested bucket.
+			bucket := visitsBucket.Bucket([]byte(shortened))
+			if bucket == nil {
+				continue
+			}
+
+			// Find the keys of the visits to delete in the time window. The keys sort by time.
+			var keys [][]byte
+			cursor := bucket.Cursor()
+			var k, data []byte
+			if filter.From.IsZero() {
+				k, data = cursor.First()
+			} else {
+				k, data = cursor.Seek(visitKeyPrefix(filter.From))
+			}
+			for ; k != nil; k, data = cursor.Next() {
+				if !filter.To.IsZero() && bytes.Compare(k, visitKeyPrefix(filter.To)) >= 0 {
+					break
+				}
+
+				// Check the rest of the filter.
+				if decode {
+					visit, err := bytesToVisit(data)
+					if err != nil {
+						return err
+					}
+					if !filter.Matches(visit) {
+						continue
+					}
+				}
+
+				keys = append(keys, append([]byte{}, k...))
+			}
+
+			// Delete the visits.
+			for _, key := range keys {
+				if err = bucket.Delete(key); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// Histogram counts the visits in the series' time window for the given shortened URLs. The counts are keyed by the
+// Unix time of the start of the interval the visits are in. Pruned visits are counted at the start of their UTC day. If
+// shortenedURLs is nil or empty, then all shortened URLs are counted. No error should be given if a shortened URL is not
+// found.
+func (b BboltVisits) Histogram(_ context.Context, series Series, shortenedURLs []string) (histograms map[string]map[int64]uint64, err error) {
+
+	// Create the return map.
+	histograms = make(map[string]map[int64]uint64)
+
+	// Create the function to perform on each shortened URL's nested bucket.
+	forEach := func(shortened []byte, bucket *bbolt.Bucket) (err error) {
+		counts := make(map[int64]uint64)
+
+		// Start at the beginning of the time window. The keys sort by time.
+		cursor := bucket.Cursor()
+		var k []byte
+		if series.From.IsZero() {
+			k, _ = cursor.First()
+		} else {
+			k, _ = cursor.Seek(visitKeyPrefix(series.From))
+		}
+
+		// Count the visits until the end of the time window without decoding them.
+		for ; k != nil; k, _ = cursor.Next() {
+			if !series.To.IsZero() && bytes.Compare(k, visitKeyPrefix(series.To)) >= 0 {
+				break
+			}
+			series.count(counts, visitKeyTime(k), 1)
+		}
+
+		// Count the pruned visits.
+		if rollups := bucket.Tx().Bucket(b.rollupsBucket).Bucket(shortened); rollups != nil {
+			if err = rollups.ForEach(func(key, data []byte) error {
+				if t, ok := dayTime(string(key)); ok {
+					series.count(counts, t, bytesToCount(data))
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+
+		// Add the visit counts to the return map.
+		histograms[string(shortened)] = counts
+
+		return nil
+	}
+
+	// Check for the empty case.
+	if len(shortenedURLs) == 0 {
+
+		// Count the visits of all shortened URLs.
+		if err = b.forEachBucket(forEach, nil); err != nil {
+			return nil, err
+		}
+		return histograms, nil
+	}
+
+	// Open the bbolt database for reading.
+	if err = b.db.View(func(tx *bbolt.Tx) error {
+
+		// Iterate through the given shortened URLs.
+		for _, shortened := range shortenedURLs {
+
+			// Skip shortened URLs without a nested bucket.
+			bucket := tx.Bucket(b.visitsBucket).Bucket([]byte(shortened))
+			if bucket == nil {
+				continue
+			}
+
+			// Count the visits of the shortened URL.
+			if err = forEach([]byte(shortened), bucket); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return histograms, nil
 }
 
 // Insert inserts the given Visits data. The visits do not need to be unique, so the Visits data should be appended
@@ -50,42 +254,118 @@ func (b BboltVisits) Delete(_ context.Context, shortenedURLs []string) (err erro
 func (b BboltVisits) Insert(_ context.Context, visitsData map[string][]models.Visit) (err error) {
 
 	// Open the bbolt database for writing, batch if possible.
-	if err = b.db.Batch(func(tx *bbolt.Tx) error {
+	return b.db.Batch(func(tx *bbolt.Tx) error {
 
 		// Iterate through the given shortened URLs.
 		for shortened, visits := range visitsData {
 
-			// Get the existing Visits data.
-			var existingVisits []models.Visit
-			data := tx.Bucket(b.visitsBucket).Get([]byte(shortened))
+			// Get the shortened URL's nested bucket.
+			bucket, err := tx.Bucket(b.visitsBucket).CreateBucketIfNotExists([]byte(shortened))
+			if err != nil {
+				return err
+			}
 
-			// Transform the raw data into Visits data.
-			if data != nil {
-				if existingVisits, err = bytesToVisits(data); err != nil {
+			// Append each visit to the nested bucket.
+			for _, visit := range visits {
+				if err = putVisit(bucket, visit); err != nil {
 					return err
 				}
 			}
+		}
+
+		return nil
+	})
+}
 
-			// Add the given visits data to the existing Visits data.
-			existingVisits = append(existingVisits, visits...)
+// Prune rolls up the visits before the given time into aggregate counts and a unique visitor Sketch, then deletes them.
+// The aggregate counts are the daily visit counts and the breakdowns of Visits Summary data. They are kept until all
+// Visits data for the shortened URL are deleted. The number of pruned visits is returned.
+func (b BboltVisits) Prune(_ context.Context, before time.Time) (pruned uint64, err error) {
 
-			// Turn the Visits data back into raw data.
-			if data, err = visitsToBytes(existingVisits); err != nil {
+	// The keys of the visits to prune sort before this prefix.
+	end := visitKeyPrefix(before)
+
+	// Open the bbolt database for exclusive writing.
+	if err = b.db.Update(func(tx *bbolt.Tx) error {
+		rollupsBucket := tx.Bucket(b.rollupsBucket)
+		visitsBucket := tx.Bucket(b.visitsBucket)
+
+		// Find all the shortened URLs. Nested buckets have a nil value.
+		var shortenedURLs [][]byte
+		if err = visitsBucket.ForEach(func(shortened, value []byte) error {
+			if value == nil {
+				shortenedURLs = append(shortenedURLs, append([]byte{}, shortened...))
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		// Iterate through the shortened URLs.
+		for _, shortened := range shortenedURLs {
+			bucket := visitsBucket.Bucket(shortened)
+
+			// Aggregate the visits before the cutoff. The keys sort by time.
+			counts := make(map[string]uint64)
+			sketch := NewSketch()
+			var keys [][]byte
+			cursor := bucket.Cursor()
+			for k, data := cursor.First(); k != nil && bytes.Compare(k, end) < 0; k, data = cursor.Next() {
+				visit, err := bytesToVisit(data)
+				if err != nil {
+					return err
+				}
+				countVisit(counts, visit)
+				sketch.Add(visit)
+				keys = append(keys, append([]byte{}, k...))
+			}
+
+			// Check for the empty case.
+			if len(keys) == 0 {
+				continue
+			}
+
+			// Add the aggregate counts to the shortened URL's rollups.
+			rollups, err := rollupsBucket.CreateBucketIfNotExists(shortened)
+			if err != nil {
 				return err
 			}
+			for key, count := range counts {
+				if data := rollups.Get([]byte(key)); data != nil {
+					count += bytesToCount(data)
+				}
+				if err = rollups.Put([]byte(key), countToBytes(count)); err != nil {
+					return err
+				}
+			}
 
-			// Write the Visits data back to the bucket.
-			if err = tx.Bucket(b.visitsBucket).Put([]byte(shortened), data); err != nil {
+			// Merge the visitors into the shortened URL's unique visitor Sketch of pruned visits.
+			if data := rollups.Get([]byte(rollupVisitors)); data != nil {
+				existing, err := bytesToSketch(data)
+				if err != nil {
+					return err
+				}
+				sketch.Merge(existing)
+			}
+			if err = rollups.Put([]byte(rollupVisitors), sketchToBytes(sketch)); err != nil {
 				return err
 			}
+
+			// Delete the pruned visits.
+			for _, key := range keys {
+				if err = bucket.Delete(key); err != nil {
+					return err
+				}
+			}
+			pruned += uint64(len(keys))
 		}
 
 		return nil
 	}); err != nil {
-		return err
+		return 0, err
 	}
 
-	return err
+	return pruned, nil
 }
 
 // Read exports the Visits data for the given shortened URLs. If shortenedURLs is nil or empty, then all shortened
@@ -95,12 +375,19 @@ func (b BboltVisits) Read(_ context.Context, shortenedURLs []string) (visitsData
 	// Create the return map.
 	visitsData = make(map[string][]models.Visit)
 
-	// Create the forEachFunc.
-	var forEach forEachFunc = func(shortened, data []byte) (err error) {
+	// Create the function to perform on each shortened URL's nested bucket.
+	forEach := func(shortened []byte, bucket *bbolt.Bucket) (err error) {
 
-		// Turn the raw data into Visits data.
-		visits, err := bytesToVisits(data)
-		if err != nil {
+		// Turn the raw data into Visits data in order.
+		visits := make([]models.Visit, 0)
+		if err = bucket.ForEach(func(_, data []byte) error {
+			visit, err := bytesToVisit(data)
+			if err != nil {
+				return err
+			}
+			visits = append(visits, visit)
+			return nil
+		}); err != nil {
 			return err
 		}
 
@@ -111,42 +398,319 @@ func (b BboltVisits) Read(_ context.Context, shortenedURLs []string) (visitsData
 	}
 
 	// Read the Visits data into the return map.
-	if err = bboltRead(b, forEach, shortenedURLs); err != nil {
+	if err = b.forEachBucket(forEach, shortenedURLs); err != nil {
 		return nil, err
 	}
 
 	return visitsData, nil
 }
 
-// Summary summarizes the Visits data for the given shortened URLs. If shortenedURLs is nil or empty, then all
-// shortened URL Summary data are expected. The error must be storage.ErrShortenedNotFound if a shortened URL is not
+// ReadFiltered exports the Visits data that match the filter for the given shortened URLs. The visits are sorted by
+// time and at most filter.Limit visits are exported for each shortened URL. If shortenedURLs is nil or empty, then all
+// shortened URL Visits data are expected. The error must be storage.ErrShortenedNotFound if a shortened URL is not
 // found.
+func (b BboltVisits) ReadFiltered(_ context.Context, filter VisitsFilter, shortenedURLs []string) (visitsData map[string][]models.Visit, err error) {
+
+	// Create the return map.
+	visitsData = make(map[string][]models.Visit)
+
+	// Create the function to perform on each shortened URL's nested bucket.
+	forEach := func(shortened []byte, bucket *bbolt.Bucket) (err error) {
+		visits := make([]models.Visit, 0)
+
+		// Start at the beginning of the time window. The keys sort by time.
+		cursor := bucket.Cursor()
+		var k, data []byte
+		if filter.From.IsZero() {
+			k, data = cursor.First()
+		} else {
+			k, data = cursor.Seek(visitKeyPrefix(filter.From))
+		}
+
+		// Iterate through the visits until the end of the time window or the limit.
+		for ; k != nil; k, data = cursor.Next() {
+			if !filter.To.IsZero() && bytes.Compare(k, visitKeyPrefix(filter.To)) >= 0 {
+				break
+			}
+			if filter.Limit != 0 && uint64(len(visits)) == filter.Limit {
+				break
+			}
+
+			// Turn the raw data into a visit.
+			visit, err := bytesToVisit(data)
+			if err != nil {
+				return err
+			}
+
+			// Only keep the visits that match the filter.
+			if filter.Matches(visit) {
+				visits = append(visits, visit)
+			}
+		}
+
+		// Add the Visits data to the return map.
+		visitsData[string(shortened)] = visits
+
+		return nil
+	}
+
+	// Read the Visits data into the return map.
+	if err = b.forEachBucket(forEach, shortenedURLs); err != nil {
+		return nil, err
+	}
+
+	return visitsData, nil
+}
+
+// Shortened lists the shortened URLs with Visits data.
+func (b BboltVisits) Shortened(_ context.Context) (shortenedURLs []string, err error) {
+
+	// Open the bbolt database for reading.
+	shortenedURLs = make([]string, 0)
+	if err = b.db.View(func(tx *bbolt.Tx) error {
+
+		// Iterate through all the shortened URLs. Nested buckets have a nil value.
+		return tx.Bucket(b.visitsBucket).ForEach(func(shortened, value []byte) error {
+			if value == nil {
+				shortenedURLs = append(shortenedURLs, string(shortened))
+			}
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	return shortenedURLs, nil
+}
+
+// Summary summarizes the Visits data for the given shortened URLs. The summaries include pruned visits. If
+// shortenedURLs is nil or empty, then all shortened URL Summary data are expected. The error must be
+// storage.ErrShortenedNotFound if a shortened URL is not found.
 func (b BboltVisits) Summary(_ context.Context, shortenedURLs []string) (summaries map[string]*models.VisitsSummary, err error) {
 
 	// Create the return map.
 	summaries = make(map[string]*models.VisitsSummary)
 
-	// Create the forEachFunc.
-	var forEach forEachFunc = func(shortened, data []byte) (err error) {
+	// Create the function to perform on each shortened URL's nested bucket.
+	forEach := func(shortened []byte, bucket *bbolt.Bucket) (err error) {
 
-		// Turn the raw data into Visits data.
-		visits, err := bytesToVisits(data)
+		// Summarize the Visits data.
+		summary, _, err := b.summarize(shortened, bucket)
 		if err != nil {
 			return err
 		}
 
 		// Add the Visits data to the return map.
-		summaries[string(shortened)] = &models.VisitsSummary{
-			VisitCount: uint64(len(visits)),
-		}
+		summaries[string(shortened)] = summary
 
 		return nil
 	}
 
 	// Read the Summary data into the return map.
-	if err = bboltRead(b, forEach, shortenedURLs); err != nil {
+	if err = b.forEachBucket(forEach, shortenedURLs); err != nil {
 		return nil, err
 	}
 
 	return summaries, nil
 }
+
+// Visitors creates the unique visitor Sketch for each of the given shortened URLs. The sketches include the visitors of
+// pruned visits. If shortenedURLs is nil or empty, then all shortened URL sketches are expected. The error must be
+// storage.ErrShortenedNotFound if a shortened URL is not found.
+func (b BboltVisits) Visitors(_ context.Context, shortenedURLs []string) (sketches map[string]*Sketch, err error) {
+
+	// Create the return map.
+	sketches = make(map[string]*Sketch)
+
+	// Create the function to perform on each shortened URL's nested bucket.
+	forEach := func(shortened []byte, bucket *bbolt.Bucket) (err error) {
+
+		// Create the unique visitor Sketch.
+		_, sketch, err := b.summarize(shortened, bucket)
+		if err != nil {
+			return err
+		}
+
+		// Add the sketch to the return map.
+		sketches[string(shortened)] = sketch
+
+		return nil
+	}
+
+	// Read the sketches into the return map.
+	if err = b.forEachBucket(forEach, shortenedURLs); err != nil {
+		return nil, err
+	}
+
+	return sketches, nil
+}
+
+// forEachBucket performs the given function on the nested bucket of each of the given shortened URLs. If shortenedURLs
+// is nil or empty, it is performed on all nested buckets. The error will be storage.ErrShortenedNotFound if a shortened
+// URL does not have a nested bucket.
+func (b BboltVisits) forEachBucket(forEach func(shortened []byte, bucket *bbolt.Bucket) (err error), shortenedURLs []string) (err error) {
+
+	// Open the bbolt database for reading.
+	return b.db.View(func(tx *bbolt.Tx) error {
+		visitsBucket := tx.Bucket(b.visitsBucket)
+
+		// Check for the empty case.
+		if len(shortenedURLs) == 0 {
+
+			// Iterate through all the shortened URLs. Nested buckets have a nil value.
+			return visitsBucket.ForEach(func(shortened, value []byte) error {
+				if value != nil {
+					return nil
+				}
+				return forEach(shortened, visitsBucket.Bucket(shortened))
+			})
+		}
+
+		// Iterate through the given shortened URLs.
+		for _, shortened := range shortenedURLs {
+
+			// Get the shortened URL's nested bucket.
+			bucket := visitsBucket.Bucket([]byte(shortened))
+			if bucket == nil {
+				return ErrShortenedNotFound
+			}
+
+			// Perform the given function on the shortened URL and its nested bucket.
+			if err = forEach([]byte(shortened), bucket); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// summarize creates the Visits Summary data and the unique visitor Sketch for the given shortened URL from its nested
+// bucket and its rollups.
+func (b BboltVisits) summarize(shortened []byte, bucket *bbolt.Bucket) (summary *models.VisitsSummary, sketch *Sketch, err error) {
+
+	// Aggregate the visits.
+	counts := make(map[string]uint64)
+	sketch = NewSketch()
+	if err = bucket.ForEach(func(_, data []byte) error {
+		visit, err := bytesToVisit(data)
+		if err != nil {
+			return err
+		}
+		countVisit(counts, visit)
+		sketch.Add(visit)
+		return nil
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	// Add the aggregate counts and visitors of the pruned visits.
+	if rollups := bucket.Tx().Bucket(b.rollupsBucket).Bucket(shortened); rollups != nil {
+		if err = rollups.ForEach(func(key, data []byte) error {
+			if string(key) != rollupVisitors {
+				counts[string(key)] += bytesToCount(data)
+				return nil
+			}
+			pruned, err := bytesToSketch(data)
+			if err != nil {
+				return err
+			}
+			sketch.Merge(pruned)
+			return nil
+		}); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Summarize the aggregate counts.
+	summary = &models.VisitsSummary{}
+	addCounts(summary, counts)
+	summary.UniqueVisitors = sketch.Estimate()
+
+	return summary, sketch, nil
+}
+
+// migrateVisits moves Visits data from the old layout, where each shortened URL's key held all of its visits, to a
+// nested bucket per shortened URL. It does nothing if all Visits data already use nested buckets.
+func migrateVisits(db *bbolt.DB, visitsBucket []byte) (err error) {
+	return db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(visitsBucket)
+
+		// Find the shortened URLs in the old layout. Nested buckets have a nil value.
+		old := make(map[string][]byte)
+		if err = bucket.ForEach(func(shortened, data []byte) error {
+			if data != nil {
+				old[string(shortened)] = append([]byte{}, data...)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		// Iterate through the shortened URLs in the old layout.
+		for shortened, data := range old {
+
+			// Turn the raw data into Visits data.
+			visits, err := bytesToVisits(data)
+			if err != nil {
+				return err
+			}
+
+			// Replace the key with a nested bucket.
+			if err = bucket.Delete([]byte(shortened)); err != nil {
+				return err
+			}
+			nested, err := bucket.CreateBucket([]byte(shortened))
+			if err != nil {
+				return err
+			}
+
+			// Put each visit in the nested bucket.
+			for _, visit := range visits {
+				if err = putVisit(nested, visit); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// putVisit puts a single visit into a shortened URL's nested bucket.
+func putVisit(bucket *bbolt.Bucket, visit models.Visit) (err error) {
+
+	// Get a unique sequence number for the visit.
+	sequence, err := bucket.NextSequence()
+	if err != nil {
+		return err
+	}
+
+	// Transform the visit into bytes.
+	data, err := visitToBytes(visit)
+	if err != nil {
+		return err
+	}
+
+	return bucket.Put(visitKey(visit, sequence), data)
+}
+
+// visitKey creates the bbolt key for a visit in a shortened URL's nested bucket. It is the big endian Unix nanoseconds
+// of the visit followed by the big endian sequence number, so keys sort by time and visits at the same time are unique.
+func visitKey(visit models.Visit, sequence uint64) (key []byte) {
+	key = make([]byte, 16)
+	copy(key, visitKeyPrefix(visitTime(visit)))
+	binary.BigEndian.PutUint64(key[8:], sequence)
+	return key
+}
+
+// visitKeyPrefix creates the part of a visit's bbolt key that sorts by time. The zero time has the lowest prefix.
+func visitKeyPrefix(t time.Time) (prefix []byte) {
+	var nanoseconds int64
+	if !t.IsZero() {
+		nanoseconds = t.UnixNano()
+	}
+	prefix = make([]byte, 8)
+	binary.BigEndian.PutUint64(prefix, uint64(nanoseconds))
+	return prefix
+}
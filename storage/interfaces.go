@@ -2,12 +2,13 @@ package storage
 
 import (
 	"context"
+	"time"
 
 	"github.com/MicahParks/terseurl/models"
 )
 
 // AuthorizationStore is the Authorization data storage interface. It allows for Authorization data storage operations
-// without needing to know of the Authorization data are stored.
+// without needing to know how the Authorization data are stored.
 type AuthorizationStore interface {
 
 	// AuthorizedShortened creates a map of users to the shortened URLs they are authorized for.
@@ -19,6 +20,9 @@ type AuthorizationStore interface {
 	// Delete deletes the authorization information for the given shortened URLs. If shortenedURLs is nil or empty, all
 	// Authorization data are deleted. No error should be returned if a shortened URL is not found.
 	Delete(ctx context.Context, shortenedURLs []string) (err error)
+
+	// Upsert upserts the owner of the given shortened URLs. The owner is identified by the principal's subject.
+	Upsert(ctx context.Context, owners map[string]*models.Principal) (err error)
 }
 
 // SummaryStore is the Summary data storage interface. It allows for Summary data storage operations without needing to
@@ -32,16 +36,27 @@ type SummaryStore interface {
 	// Summary data are deleted. No error should be returned if a shortened URL is not found.
 	Delete(ctx context.Context, shortenedURLs []string) (err error)
 
-	// IncrementVisitCount increments the visit count for the given shortened URL. It is called in separate goroutine.
-	// The error must be storage.ErrShortenedNotFound if the shortened URL is not found.
-	IncrementVisitCount(ctx context.Context, shortened string) (err error)
+	// IncrementVisitCount adds the visit to the visit counts, breakdowns, and unique visitors of the Visits Summary data
+	// for the given shortened URL. The visit count is only incremented if counted is true. It is called in separate
+	// goroutine. The error must be storage.ErrShortenedNotFound if the shortened URL is not found.
+	IncrementVisitCount(ctx context.Context, shortened string, visit models.Visit, counted bool) (err error)
 
 	// Read provides the summary information for the given shortened URLs. If shortenedURLs is nil or empty, all
 	// summaries are returned. The error must be storage.ErrShortenedNotFound if a shortened URL is not found.
 	Read(ctx context.Context, shortenedURLs []string) (summaries map[string]*models.Summary, err error)
 
+	// ReadPage provides the summary information for the given shortened URLs as a sorted page. If shortenedURLs is nil
+	// or empty, all summaries are paginated. The next return value is the cursor for the following page. It is empty if
+	// there are no more results. The error must be storage.ErrShortenedNotFound if a shortened URL is not found. The
+	// error must be storage.ErrInvalidCursor if the page's cursor cannot be used.
+	ReadPage(ctx context.Context, page Page, shortenedURLs []string) (summaries []*models.Summary, next string, err error)
+
 	// Upsert upserts the summary information for the given shortened URL.
 	Upsert(ctx context.Context, summaries map[string]*models.Summary) (err error)
+
+	// UpsertVisitors upserts the unique visitor Sketch for the given shortened URLs. The unique visitors of their Visits
+	// Summary data are set to the sketch's estimate. Later visits are added to the sketch by IncrementVisitCount.
+	UpsertVisitors(ctx context.Context, sketches map[string]*Sketch) (err error)
 }
 
 // TerseStore is the Terse storage interface. It allows for Terse storage operations without needing to know how
@@ -55,17 +70,41 @@ type TerseStore interface {
 	// Terse data are deleted. There should be no error if a shortened URL is not found.
 	Delete(ctx context.Context, shortenedURLs []string) (err error)
 
+	// DeleteExpired deletes the Terse data for the given shortened URLs that have expired at the given time with the
+	// given visit counts. Expiration is checked when the Terse data are deleted, so Terse data updated after they were
+	// found to be expired are kept. The shortened URLs whose Terse data were deleted are returned. There should be no
+	// error if a shortened URL is not found.
+	DeleteExpired(ctx context.Context, visitCounts map[string]uint64, now time.Time) (deleted []string, err error)
+
+	// Deduplicate returns a map of the given dedupe keys to the shortened URLs whose Terse data have that dedupe key.
+	// Dedupe keys without any shortened URLs are not in the map. See storage.DedupeKey.
+	Deduplicate(ctx context.Context, keys []string) (shortenedURLs map[string][]string, err error)
+
 	// Read returns a map of shortened URLs to Terse data. If shortenedURLs is nil or empty, all shortened URL Terse
 	// data are expected. The error must be storage.ErrShortenedNotFound if a shortened URL is not found.
 	Read(ctx context.Context, shortenedURLs []string) (terseData map[string]*models.Terse, err error)
 
+	// ReadPage returns the Terse data for the given shortened URLs as a sorted page. If shortenedURLs is nil or empty,
+	// all shortened URL Terse data are paginated. The next return value is the cursor for the following page. It is
+	// empty if there are no more results. The error must be storage.ErrShortenedNotFound if a shortened URL is not
+	// found. The error must be storage.ErrInvalidCursor if the page's cursor cannot be used. The error must be
+	// storage.ErrUnsupportedSort if the page is sorted by visit count.
+	ReadPage(ctx context.Context, page Page, shortenedURLs []string) (terseData []*models.Terse, next string, err error)
+
+	// Search returns the Terse data whose original URL matches the query, sorted by shortened URL. Only shortened URLs
+	// after query.After are searched and at most query.Limit Terse data are returned. If shortenedURLs is nil or empty,
+	// all shortened URLs are searched. The next return value is the last returned shortened URL if there are more
+	// matches, otherwise it is empty.
+	Search(ctx context.Context, query models.SearchQuery, shortenedURLs []string) (terseData []*models.Terse, next string, err error)
+
 	// Summary summarizes the Terse data for the given shortened URLs. If shortenedURLs is nil or empty, then all
 	// shortened URL Summary data are expected.
 	Summary(ctx context.Context, shortenedURLs []string) (summaries map[string]*models.TerseSummary, err error)
 
 	// Write writes the given Terse data according to the given operation. The error must be storage.ErrShortenedExists
 	// if an Insert operation cannot be performed due to the Terse data already existing. The error must be
-	// storage.ErrShortenedNotFound if an Update operation cannot be performed due to the Terse data not existing.
+	// storage.ErrShortenedNotFound if an Update operation cannot be performed due to the Terse data not existing. Terse
+	// data without a creation time are given the existing creation time, or the current time if the shortened URL is new.
 	Write(ctx context.Context, terseData map[string]*models.Terse, operation WriteOperation) (err error)
 }
 
@@ -76,20 +115,51 @@ type VisitsStore interface {
 	// Close closes the connection to the underlying storage.
 	Close(ctx context.Context) (err error)
 
-	// Delete deletes Visits data for the given shortened URLs. If shortenedURLs is nil or empty, then all Visits data
-	// are deleted. No error should be given if a shortened URL is not found.
+	// Delete deletes Visits data for the given shortened URLs, including the aggregate counts of pruned visits. If
+	// shortenedURLs is nil or empty, then all Visits data are deleted. No error should be given if a shortened URL is
+	// not found.
 	Delete(ctx context.Context, shortenedURLs []string) (err error)
 
+	// DeleteFiltered deletes the visits that match the filter for the given shortened URLs. The filter's limit is not
+	// used. The aggregate counts of pruned visits are not deleted. If shortenedURLs is nil or empty, then the visits of
+	// all shortened URLs are filtered. No error should be given if a shortened URL is not found.
+	DeleteFiltered(ctx context.Context, filter VisitsFilter, shortenedURLs []string) (err error)
+
+	// Histogram counts the visits in the series' time window for the given shortened URLs. The counts are keyed by the
+	// Unix time of the start of the interval the visits are in. Pruned visits are counted at the start of their UTC day.
+	// If shortenedURLs is nil or empty, then all shortened URLs are counted. No error should be given if a shortened URL
+	// is not found.
+	Histogram(ctx context.Context, series Series, shortenedURLs []string) (histograms map[string]map[int64]uint64, err error)
+
 	// Insert inserts the given Visits data. The visits do not need to be unique, so the Visits data should be appended
 	// to the data structure in storage.
 	Insert(ctx context.Context, visitsData map[string][]models.Visit) (err error)
 
+	// Prune rolls up the visits before the given time into aggregate counts and a unique visitor Sketch, then deletes
+	// them. The aggregate counts are the daily visit counts and the breakdowns of Visits Summary data. They are kept
+	// until all Visits data for the shortened URL are deleted. The number of pruned visits is returned.
+	Prune(ctx context.Context, before time.Time) (pruned uint64, err error)
+
 	// Read exports the Visits data for the given shortened URLs. If shortenedURLs is nil or empty, then all shortened
 	// URL Visits data are expected. The error must be storage.ErrShortenedNotFound if a shortened URL is not found.
 	Read(ctx context.Context, shortenedURLs []string) (visitsData map[string][]models.Visit, err error)
 
-	// Summary summarizes the Visits data for the given shortened URLs. If shortenedURLs is nil or empty, then all
-	// shortened URL Summary data are expected. The error must be storage.ErrShortenedNotFound if a shortened URL is not
-	// found.
+	// ReadFiltered exports the Visits data that match the filter for the given shortened URLs. The visits are sorted by
+	// time and at most filter.Limit visits are exported for each shortened URL. If shortenedURLs is nil or empty, then
+	// all shortened URL Visits data are expected. The error must be storage.ErrShortenedNotFound if a shortened URL is
+	// not found.
+	ReadFiltered(ctx context.Context, filter VisitsFilter, shortenedURLs []string) (visitsData map[string][]models.Visit, err error)
+
+	// Shortened lists the shortened URLs with Visits data. It does not read the visits.
+	Shortened(ctx context.Context) (shortenedURLs []string, err error)
+
+	// Summary summarizes the Visits data for the given shortened URLs. The summaries include pruned visits. If
+	// shortenedURLs is nil or empty, then all shortened URL Summary data are expected. The error must be
+	// storage.ErrShortenedNotFound if a shortened URL is not found.
 	Summary(ctx context.Context, shortenedURLs []string) (summaries map[string]*models.VisitsSummary, err error)
+
+	// Visitors creates the unique visitor Sketch for each of the given shortened URLs. The sketches include the visitors
+	// of pruned visits. If shortenedURLs is nil or empty, then all shortened URL sketches are expected. The error must
+	// be storage.ErrShortenedNotFound if a shortened URL is not found.
+	Visitors(ctx context.Context, shortenedURLs []string) (sketches map[string]*Sketch, err error)
 }
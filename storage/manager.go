@@ -4,6 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/MicahParks/ctxerrgroup"
 
@@ -12,24 +15,84 @@ import (
 
 // StoreManager holds all data stores and coordinates operations on them.
 type StoreManager struct {
+	admin        AdminChecker
+	authStore    AuthorizationStore
+	countBots    bool
 	createCtx    CtxCreator
 	group        ctxerrgroup.Group
+	pending      *pendingVisits
 	summaryStore SummaryStore
 	terseStore   TerseStore
+	visitProcess []VisitProcessor
 	visitsStore  VisitsStore
 }
 
-// NewStoreManager creates a new manager for the data stores.
-func NewStoreManager(createCtx CtxCreator, group ctxerrgroup.Group, summaryStore SummaryStore, terseStore TerseStore, visitsStore VisitsStore) (manager StoreManager) {
+// NewStoreManager creates a new manager for the data stores. If countBots is false, visits from automated clients are not
+// in the visit counts of the Summary data. The visit processors are performed in order on each visit before it is
+// stored.
+func NewStoreManager(admin AdminChecker, authStore AuthorizationStore, countBots bool, createCtx CtxCreator, group ctxerrgroup.Group, summaryStore SummaryStore, terseStore TerseStore, visitProcess []VisitProcessor, visitsStore VisitsStore) (manager StoreManager) {
 	return StoreManager{
+		admin:        admin,
+		authStore:    authStore,
+		countBots:    countBots,
 		createCtx:    createCtx,
 		group:        group,
+		pending:      newPendingVisits(),
 		summaryStore: summaryStore,
 		terseStore:   terseStore,
+		visitProcess: visitProcess,
 		visitsStore:  visitsStore,
 	}
 }
 
+// Admin determines if the given principal is an administrator. A nil principal means authentication is turned off, so
+// it is treated as an administrator.
+func (s StoreManager) Admin(principal *models.Principal) (admin bool) {
+	return principal == nil || s.admin == nil || s.admin(principal)
+}
+
+// Analytics creates time series of the visit counts for the given shortened URLs. If shortenedURLs is nil, then all
+// shortened URLs the principal is authorized for are counted. Shortened URLs without Visits data have a count of zero.
+// The error will be storage.ErrTooManyPoints if the time series would have too many intervals.
+func (s StoreManager) Analytics(ctx context.Context, principal *models.Principal, shortenedURLs []string, series Series) (analytics *models.Analytics, err error) {
+
+	// Only use the shortened URLs the principal is authorized for.
+	var none bool
+	if shortenedURLs, none, err = s.authorizedShortened(ctx, principal, shortenedURLs); err != nil {
+		return nil, err
+	}
+
+	// Create the visit counts.
+	histograms := make(map[string]map[int64]uint64, len(shortenedURLs))
+	if none {
+		return series.analytics(histograms)
+	}
+
+	// Count the visits in the VisitsStore.
+	s.VisitsStore(func(store VisitsStore) {
+		histograms, err = store.Histogram(ctx, series, shortenedURLs)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Give the shortened URLs without Visits data a count of zero.
+	for _, shortened := range shortenedURLs {
+		if _, ok := histograms[shortened]; !ok {
+			histograms[shortened] = make(map[int64]uint64)
+		}
+	}
+
+	return series.analytics(histograms)
+}
+
+// AuthorizationStore accepts a function to do if the AuthorizationStore is not nil.
+func (s StoreManager) AuthorizationStore(doThis func(store AuthorizationStore)) {
+	if s.authStore != nil {
+		doThis(s.authStore)
+	}
+}
+
 // Close closes the ctxerrgroup and all the underlying data stores.
 func (s StoreManager) Close(ctx context.Context) (err error) {
 
@@ -38,8 +101,16 @@ func (s StoreManager) Close(ctx context.Context) (err error) {
 
 	// TODO See if you can stick more than one error in with %w somehow...
 
-	// Close the SummaryStore.
+	// Close the AuthorizationStore.
 	var closeErr error
+	s.AuthorizationStore(func(store AuthorizationStore) {
+		closeErr = store.Close(ctx)
+	})
+	if closeErr != nil {
+		err = fmt.Errorf("%v AuthorizationStore: %v", err, closeErr)
+	}
+
+	// Close the SummaryStore.
 	s.SummaryStore(func(store SummaryStore) {
 		closeErr = store.Close(ctx)
 	})
@@ -63,64 +134,264 @@ func (s StoreManager) Close(ctx context.Context) (err error) {
 	return err
 }
 
+// Deduplicate finds existing Terse data for the given dedupe keys. Only unexpired and deduplicable shortened URLs the
+// principal is authorized for are used. If more than one shortened URL has the same dedupe key, the first in lexical order is used.
+// Dedupe keys without usable Terse data are not in the returned map. See storage.DedupeKey.
+func (s StoreManager) Deduplicate(ctx context.Context, principal *models.Principal, keys []string) (existing map[string]*models.Terse, err error) {
+
+	// Create the return map.
+	existing = make(map[string]*models.Terse, len(keys))
+
+	// Get the shortened URLs for the dedupe keys.
+	var shortenedURLs map[string][]string
+	if shortenedURLs, err = s.terseStore.Deduplicate(ctx, makeStringSliceSet(keys)); err != nil {
+		return nil, err
+	}
+
+	// Check for the empty case.
+	if len(shortenedURLs) == 0 {
+		return existing, nil
+	}
+
+	// Get the shortened URLs the principal owns, if it needs to be checked.
+	var owned map[string]struct{}
+	if s.authStore != nil && !s.Admin(principal) {
+		var authorized map[*models.Principal][]string
+		if authorized, err = s.authStore.AuthorizedShortened(ctx, []*models.Principal{principal}); err != nil {
+			return nil, err
+		}
+		owned = make(map[string]struct{}, len(authorized[principal]))
+		for _, shortened := range authorized[principal] {
+			owned[shortened] = struct{}{}
+		}
+	}
+
+	// Iterate through the dedupe keys with shortened URLs.
+	for key, candidates := range shortenedURLs {
+		sort.Strings(candidates)
+
+		// Use the first shortened URL that is authorized and unexpired.
+		for _, shortened := range candidates {
+			if owned != nil {
+				if _, ok := owned[shortened]; !ok {
+					continue
+				}
+			}
+
+			// Get the Terse data.
+			var terseData map[string]*models.Terse
+			if terseData, err = s.terseStore.Read(ctx, []string{shortened}); err != nil {
+				if errors.Is(err, ErrShortenedNotFound) {
+					continue
+				}
+				return nil, err
+			}
+
+			// Confirm the Terse data can be deduplicated.
+			if !Deduplicable(*terseData[shortened]) {
+				continue
+			}
+
+			// Confirm the shortened URL has not expired.
+			var expired bool
+			if expired, err = s.expired(ctx, shortened, *terseData[shortened], 0); err != nil {
+				return nil, err
+			}
+			if expired {
+				continue
+			}
+
+			existing[key] = terseData[shortened]
+			break
+		}
+	}
+
+	return existing, nil
+}
+
 // DeleteShortened deletes the all data for the given shortened URLs. If shortenedURLs is nil, all shortened URL data
-// are deleted. There should be no error if a shortened URL is not found.
-func (s StoreManager) DeleteShortened(ctx context.Context, shortenedURLs []string) (err error) {
+// the principal is authorized for are deleted. There should be no error if a shortened URL is not found.
+func (s StoreManager) DeleteShortened(ctx context.Context, principal *models.Principal, shortenedURLs []string) (err error) {
 
-	// Turn the input slice into a set.
-	shortenedURLs = makeStringSliceSet(shortenedURLs)
+	// Only use the shortened URLs the principal is authorized for.
+	var none bool
+	if shortenedURLs, none, err = s.authorizedShortened(ctx, principal, shortenedURLs); err != nil || none {
+		return err
+	}
 
 	// Delete the Terse data for the shortened URL.
 	if err = s.terseStore.Delete(ctx, shortenedURLs); err != nil {
 		return err
 	}
 
-	// Delete the Visits data for the shortened URL.
+	// Delete the rest of the data for the shortened URL.
+	return s.deleteShortened(ctx, shortenedURLs)
+}
+
+// DeleteExpired deletes all data for the given expired shortened URLs that are still expired at the given time, and
+// for the given orphaned shortened URLs that still do not have Terse data. Expiration is checked again when the Terse
+// data are deleted, so shortened URLs that were updated after they were found by Expired are kept. The shortened URLs
+// whose data were deleted are returned.
+func (s StoreManager) DeleteExpired(ctx context.Context, now time.Time, expired, orphaned []string) (deletedExpired, deletedOrphaned []string, err error) {
+
+	// Get the current visit counts of the expired shortened URLs from the SummaryStore.
+	visitCounts := make(map[string]uint64, len(expired))
+	for _, shortened := range expired {
+		visitCounts[shortened] = 0
+		s.SummaryStore(func(store SummaryStore) {
+			var summaries map[string]*models.Summary
+			if summaries, err = store.Read(ctx, []string{shortened}); err != nil {
+				if errors.Is(err, ErrShortenedNotFound) {
+					err = nil
+				}
+				return
+			}
+			if visits := summaries[shortened].Visits; visits != nil {
+				visitCounts[shortened] = visits.VisitCount
+			}
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Delete the Terse data that are still expired.
+	if deletedExpired, err = s.terseStore.DeleteExpired(ctx, visitCounts, now); err != nil {
+		return nil, nil, err
+	}
+
+	// Only delete the orphaned data that still do not have Terse data.
+	deletedOrphaned = make([]string, 0, len(orphaned))
+	for _, shortened := range orphaned {
+		if _, err = s.terseStore.Read(ctx, []string{shortened}); err == nil {
+			continue
+		}
+		if !errors.Is(err, ErrShortenedNotFound) {
+			return nil, nil, err
+		}
+		err = nil
+		deletedOrphaned = append(deletedOrphaned, shortened)
+	}
+
+	// Delete the rest of the data for the shortened URLs. An empty slice would delete all data.
+	if deleted := append(append([]string{}, deletedExpired...), deletedOrphaned...); len(deleted) != 0 {
+		if err = s.deleteShortened(ctx, deleted); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return deletedExpired, deletedOrphaned, nil
+}
+
+// DeleteVisits deletes the Visits data that match the filter for the given shortened URLs. If the filter is empty, all
+// Visits data for the shortened URLs are deleted, but the shortened URLs keep empty Visits data. If shortenedURLs is
+// nil, then the Visits data of all shortened URLs the principal is authorized for are deleted. The visit counts in the
+// SummaryStore are updated afterwards. No error should be given if a shortened URL is not found.
+func (s StoreManager) DeleteVisits(ctx context.Context, principal *models.Principal, shortenedURLs []string, filter VisitsFilter) (err error) {
+
+	// Only use the shortened URLs the principal is authorized for.
+	var none bool
+	if shortenedURLs, none, err = s.authorizedShortened(ctx, principal, shortenedURLs); err != nil || none {
+		return err
+	}
+
+	// Delete the Visits data from the VisitsStore.
 	s.VisitsStore(func(store VisitsStore) {
-		err = store.Delete(ctx, shortenedURLs)
+		if filter.Empty() {
+			err = clearVisits(ctx, store, shortenedURLs)
+		} else {
+			err = store.DeleteFiltered(ctx, filter, shortenedURLs)
+		}
 	})
 	if err != nil {
 		return err
 	}
 
-	// Delete the Summary data for the shortened URL.
+	// Keep the visit counts in the SummaryStore consistent with the VisitsStore.
+	return s.syncVisitCounts(ctx, shortenedURLs)
+}
+
+// Expired finds the shortened URLs that have expired by time or by visit count at the given time. If orphans is true,
+// the orphaned return value contains shortened URLs with Visits data or Summary data, but no Terse data. Otherwise, it
+// is empty.
+func (s StoreManager) Expired(ctx context.Context, now time.Time, orphans bool) (expired, orphaned []string, err error) {
+
+	// Get all the Terse data.
+	var terse map[string]*models.Terse
+	if terse, err = s.terseStore.Read(ctx, nil); err != nil {
+		return nil, nil, err
+	}
+
+	// Get the visit counts from the SummaryStore.
+	visitCounts := make(map[string]uint64)
+	var summaries map[string]*models.Summary
 	s.SummaryStore(func(store SummaryStore) {
-		err = store.Delete(ctx, shortenedURLs)
+		summaries, err = store.Read(ctx, nil)
 	})
 	if err != nil {
-		return err
+		return nil, nil, err
+	}
+	for shortened, summary := range summaries {
+		if summary.Visits != nil {
+			visitCounts[shortened] = summary.Visits.VisitCount
+		}
 	}
 
-	return nil
-}
+	// Create the return slices.
+	expired = make([]string, 0)
+	orphaned = make([]string, 0)
 
-// DeleteVisits deletes Visits data for the given shortened URLs. If shortenedURLs is nil, then all Visits data are
-// deleted. No error should be given if a shortened URL is not found.
-func (s StoreManager) DeleteVisits(ctx context.Context, shortenedURLs []string) (err error) {
+	// Find the expired Terse data.
+	for shortened, t := range terse {
+		if terseExpired(*t, visitCounts[shortened], now) {
+			expired = append(expired, shortened)
+		}
+	}
 
-	// Turn the input slice into a set.
-	shortenedURLs = makeStringSliceSet(shortenedURLs)
+	// Only look for orphaned data if asked to.
+	if !orphans {
+		return expired, orphaned, nil
+	}
 
-	// Delete the Visits data from the VisitsStore.
+	// Get the shortened URLs in the VisitsStore.
+	var visitsShortened []string
 	s.VisitsStore(func(store VisitsStore) {
-		err = store.Delete(ctx, shortenedURLs)
+		visitsShortened, err = store.Shortened(ctx)
 	})
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
-	return nil
+	// Find the Summary data and Visits data without Terse data.
+	for shortened := range summaries {
+		if _, ok := terse[shortened]; !ok {
+			orphaned = append(orphaned, shortened)
+		}
+	}
+	for _, shortened := range visitsShortened {
+		if _, ok := terse[shortened]; !ok {
+			orphaned = append(orphaned, shortened)
+		}
+	}
+
+	return expired, makeStringSliceSet(orphaned), nil
 }
 
 // Export exports the Terse data and Visits data for the given shortened URLs. If shortenedURLs is nil, then all
-// shortened URLs are exported.
-func (s StoreManager) Export(ctx context.Context, shortenedURLs []string) (export map[string]*models.Export, err error) {
+// shortened URLs the principal is authorized for are exported.
+func (s StoreManager) Export(ctx context.Context, principal *models.Principal, shortenedURLs []string) (export map[string]*models.Export, err error) {
 
-	// Turn the input slice into a set.
-	shortenedURLs = makeStringSliceSet(shortenedURLs)
+	// Only use the shortened URLs the principal is authorized for.
+	var none bool
+	if shortenedURLs, none, err = s.authorizedShortened(ctx, principal, shortenedURLs); err != nil {
+		return nil, err
+	}
 
 	// Create the return map.
 	export = make(map[string]*models.Export, len(shortenedURLs))
+	if none {
+		return export, nil
+	}
 
 	// Get the Terse data for the export.
 	var terse map[string]*models.Terse
@@ -153,8 +424,8 @@ func (s StoreManager) Export(ctx context.Context, shortenedURLs []string) (expor
 }
 
 // Import imports the given Terse data and Visits data to the TerseStore and VisitsStore respectively. Terse data will
-// be overwritten, Visits data will be appended.
-func (s StoreManager) Import(ctx context.Context, data map[string]*models.Export) (err error) {
+// be overwritten, Visits data will be appended. The principal will become the owner of the imported shortened URLs.
+func (s StoreManager) Import(ctx context.Context, principal *models.Principal, data map[string]*models.Export) (err error) {
 
 	// Iterate through the given import data and put it in the proper format.
 	terse := make(map[string]*models.Terse)
@@ -164,11 +435,22 @@ func (s StoreManager) Import(ctx context.Context, data map[string]*models.Export
 		visits[shortened] = export.Visits
 	}
 
+	// Confirm the principal is not overwriting shortened URLs it is not authorized for.
+	var owners map[string]*models.Principal
+	if owners, err = s.authorizeWrite(ctx, principal, terse); err != nil {
+		return err
+	}
+
 	// Write the Terse data to the TerseStore.
 	if err = s.terseStore.Write(ctx, terse, Upsert); err != nil {
 		return err
 	}
 
+	// Record the principal as the owner of the imported shortened URLs.
+	if err = s.upsertOwners(ctx, owners); err != nil {
+		return err
+	}
+
 	// Write the Visits data to the VisitsStore.
 	s.VisitsStore(func(store VisitsStore) {
 		err = store.Insert(ctx, visits)
@@ -183,10 +465,14 @@ func (s StoreManager) Import(ctx context.Context, data map[string]*models.Export
 // InitializeSummaryStore initializes the SummaryStore with SummaryData gathered from the TerseStore and VisitsStore.
 func (s StoreManager) InitializeSummaryStore(ctx context.Context) (err error) {
 
-	// Get the Visits Summary data.
+	// Get the Visits Summary data and the unique visitor sketches.
 	var visitsSummary map[string]*models.VisitsSummary
+	var sketches map[string]*Sketch
 	s.VisitsStore(func(store VisitsStore) {
-		visitsSummary, err = store.Summary(ctx, nil)
+		if visitsSummary, err = store.Summary(ctx, nil); err != nil {
+			return
+		}
+		sketches, err = store.Visitors(ctx, nil)
 	})
 	if err != nil {
 		return err
@@ -205,8 +491,9 @@ func (s StoreManager) InitializeSummaryStore(ctx context.Context) (err error) {
 
 		// Check if there is Visits data.
 		visits := &models.VisitsSummary{}
-		if haveVisits {
+		if haveVisits && visitsSummary[shortened] != nil {
 			visits = visitsSummary[shortened]
+			s.countedVisits(visits)
 		}
 
 		// Assign the shortened URL's summary data to the return map.
@@ -219,42 +506,192 @@ func (s StoreManager) InitializeSummaryStore(ctx context.Context) (err error) {
 	// If Visits data are allowed to be present when Terse data are not present for a shortened URL, then it would need to
 	// be looped through.
 
-	// Delete all existing Summary data and import the most recent summary data.
+	// Delete all existing Summary data and import the most recent summary data and unique visitor sketches.
 	s.SummaryStore(func(store SummaryStore) {
 		if err = store.Delete(ctx, nil); err != nil { // Not necessary if only used on startup.
 			return
 		}
-		err = store.Upsert(ctx, summaryData)
+		if err = store.Upsert(ctx, summaryData); err != nil {
+			return
+		}
+		err = store.UpsertVisitors(ctx, sketches)
 	})
 
 	return err
 }
 
-// Redirect is called when a visit to a shortened URL has occurred. It will keep track of the visit and return the
-// required information for a redirect.
-func (s StoreManager) Redirect(ctx context.Context, shortened string, visit models.Visit) (terse *models.Terse, err error) {
+// PruneVisits rolls up the visits before the given time into aggregate counts, then deletes them from the VisitsStore.
+// The number of pruned visits is returned. The Summary data do not change, because they include the aggregate counts of
+// pruned visits.
+func (s StoreManager) PruneVisits(ctx context.Context, before time.Time) (pruned uint64, err error) {
+	s.VisitsStore(func(store VisitsStore) {
+		pruned, err = store.Prune(ctx, before)
+	})
+	return pruned, err
+}
+
+// RecordVisit keeps track of a visit to a shortened URL. The visit is handled asynchronously for a faster response.
+// The visit Redirect reserved is released once it is in the visit count.
+func (s StoreManager) RecordVisit(shortened string, visit models.Visit) {
+	go s.handleVisit(shortened, visit)
+}
+
+// Redirect is called when a visit to a shortened URL has occurred. It will return the required information for a
+// redirect. If the shortened URL has variants, one is chosen. The assigned variant is used if it still exists, so
+// visitors can be kept on the same variant. Otherwise, it is chosen at random by weight. The error will be
+// storage.ErrShortenedExpired if the shortened URL has expired. If the shortened URL has a maximum visit count and the
+// visit is counted, the visit is reserved before returning, so concurrent visits cannot go over the maximum. Unless
+// there was an error, the visit must be kept track of with RecordVisit or released with ReleaseVisit.
+func (s StoreManager) Redirect(ctx context.Context, shortened, assigned string, bot bool) (terse *models.Terse, variant *models.Variant, err error) {
 
 	// Get the Terse data from the TerseStore.
 	var terseData map[string]*models.Terse
-	if terseData, err = s.Terse(ctx, []string{shortened}); err != nil {
+	if terseData, err = s.Terse(ctx, nil, []string{shortened}); err != nil {
+		return nil, nil, err
+	}
+	terse = terseData[shortened]
+
+	// Lock the pending visits until the visit is reserved, if the visit counts towards a maximum visit count.
+	var pending uint64
+	reserve := terse.MaxVisits != 0 && (s.countBots || !bot)
+	if reserve {
+		s.pending.mux.Lock()
+		defer s.pending.mux.Unlock()
+		pending = s.pending.counts[shortened]
+	}
+
+	// Confirm the shortened URL has not expired. Pending visits count towards the maximum visit count.
+	var expired bool
+	if expired, err = s.expired(ctx, shortened, *terse, pending); err != nil {
+		return nil, nil, err
+	}
+	if expired {
+		return nil, nil, fmt.Errorf("%w: %s", ErrShortenedExpired, shortened)
+	}
+
+	// Choose the variant to redirect to, if any.
+	if variant, err = chooseVariant(terse.Variants, assigned); err != nil {
+		return nil, nil, err
+	}
+
+	// Reserve the visit.
+	if reserve {
+		s.pending.counts[shortened]++
+	}
+
+	return terse, variant, nil
+}
+
+// ReleaseVisit releases the visit Redirect reserved, if any, when the visit will not be kept track of with RecordVisit.
+func (s StoreManager) ReleaseVisit(shortened string, bot bool) {
+	if s.countBots || !bot {
+		s.pending.release(shortened)
+	}
+}
+
+// Reusable determines if the given shortened URL's Terse data can be reused for the given Terse data, instead of writing
+// it. It can be reused if they are duplicates, it has not expired, and the principal is authorized for it. The reusable
+// Terse data is nil if it cannot be reused. See storage.Duplicate.
+func (s StoreManager) Reusable(ctx context.Context, principal *models.Principal, shortened string, terse *models.Terse) (reusable *models.Terse, exists bool, err error) {
+
+	// Read the Terse data for the shortened URL from the TerseStore.
+	var terseData map[string]*models.Terse
+	if terseData, err = s.terseStore.Read(ctx, []string{shortened}); err != nil {
+		if errors.Is(err, ErrShortenedNotFound) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	existing := terseData[shortened]
+
+	// Confirm the Terse data are duplicates.
+	if !Duplicate(*existing, *terse) {
+		return nil, true, nil
+	}
+
+	// Confirm the principal is authorized for the shortened URL.
+	if _, _, err = s.authorizedShortened(ctx, principal, []string{shortened}); err != nil {
+		if errors.Is(err, ErrUnauthorized) {
+			return nil, true, nil
+		}
+		return nil, true, err
+	}
+
+	// Confirm the shortened URL has not expired.
+	var expired bool
+	if expired, err = s.expired(ctx, shortened, *existing, 0); err != nil {
+		return nil, true, err
+	}
+	if expired {
+		return nil, true, nil
+	}
+
+	return existing, true, nil
+}
+
+// Search finds the Terse data whose original URL matches the query. Only shortened URLs the principal is authorized
+// for are searched. If no limit is given, the default is used. The limit cannot exceed the maximum.
+func (s StoreManager) Search(ctx context.Context, principal *models.Principal, query models.SearchQuery) (results *models.SearchResults, err error) {
+
+	// Only search the shortened URLs the principal is authorized for.
+	var shortenedURLs []string
+	var none bool
+	if shortenedURLs, none, err = s.authorizedShortened(ctx, principal, nil); err != nil {
 		return nil, err
 	}
 
-	// Handle the visit in another goroutine for a faster response.
-	go s.handleVisit(shortened, visit)
+	// Create the return value.
+	results = &models.SearchResults{
+		Terse: make([]*models.Terse, 0),
+	}
+	if none {
+		return results, nil
+	}
+
+	// Bound the number of results.
+	if query.Limit == 0 {
+		query.Limit = defaultPageLimit
+	} else if query.Limit > maxPageLimit {
+		query.Limit = maxPageLimit
+	}
+
+	// Search the TerseStore.
+	if results.Terse, results.NextAfter, err = s.terseStore.Search(ctx, query, shortenedURLs); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// ShortenedExists determines if the given shortened URL already has Terse data. Authorization is not checked.
+func (s StoreManager) ShortenedExists(ctx context.Context, shortened string) (exists bool, err error) {
+
+	// Read the Terse data for the shortened URL from the TerseStore.
+	if _, err = s.terseStore.Read(ctx, []string{shortened}); err != nil {
+		if errors.Is(err, ErrShortenedNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
 
-	return terseData[shortened], nil
+	return true, nil
 }
 
 // Summary retrieves the Summary data for the given shortened URLs. If shortenedURLs is nil, then all shortened URL
-// summary data will be returned.
-func (s StoreManager) Summary(ctx context.Context, shortenedURLs []string) (summaries map[string]*models.Summary, err error) {
+// summary data the principal is authorized for will be returned.
+func (s StoreManager) Summary(ctx context.Context, principal *models.Principal, shortenedURLs []string) (summaries map[string]*models.Summary, err error) {
 
-	// Turn the input slice into a set.
-	shortenedURLs = makeStringSliceSet(shortenedURLs)
+	// Only use the shortened URLs the principal is authorized for.
+	var none bool
+	if shortenedURLs, none, err = s.authorizedShortened(ctx, principal, shortenedURLs); err != nil {
+		return nil, err
+	}
 
 	// Create the return map.
 	summaries = make(map[string]*models.Summary, len(shortenedURLs))
+	if none {
+		return summaries, nil
+	}
 
 	// Retrieve the Summary data from the SummaryStore.
 	s.SummaryStore(func(store SummaryStore) {
@@ -267,6 +704,35 @@ func (s StoreManager) Summary(ctx context.Context, shortenedURLs []string) (summ
 	return summaries, nil
 }
 
+// SummaryPage retrieves a sorted page of Summary data for the given shortened URLs. If shortenedURLs is nil, then all
+// shortened URL Summary data the principal is authorized for are paginated.
+func (s StoreManager) SummaryPage(ctx context.Context, principal *models.Principal, shortenedURLs []string, page Page) (summaries *models.SummaryPage, err error) {
+
+	// Only use the shortened URLs the principal is authorized for.
+	var none bool
+	if shortenedURLs, none, err = s.authorizedShortened(ctx, principal, shortenedURLs); err != nil {
+		return nil, err
+	}
+
+	// Create the return value.
+	summaries = &models.SummaryPage{
+		Summaries: make([]*models.Summary, 0),
+	}
+	if none {
+		return summaries, nil
+	}
+
+	// Retrieve the page of Summary data from the SummaryStore.
+	s.SummaryStore(func(store SummaryStore) {
+		summaries.Summaries, summaries.NextCursor, err = store.ReadPage(ctx, page, shortenedURLs)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return summaries, nil
+}
+
 // SummaryStore accepts a function to do if the SummaryStore is not nil
 func (s StoreManager) SummaryStore(doThis func(store SummaryStore)) {
 	if s.summaryStore != nil {
@@ -274,29 +740,101 @@ func (s StoreManager) SummaryStore(doThis func(store SummaryStore)) {
 	}
 }
 
-// Terse returns a map of shortened URLs to Terse data. If shortenedURLs is nil, all shortened URL Terse data are
-// expected. The error must be storage.ErrShortenedNotFound if a shortened URL is not found.
-func (s StoreManager) Terse(ctx context.Context, shortenedURLs []string) (terse map[string]*models.Terse, err error) {
+// Terse returns a map of shortened URLs to Terse data. If shortenedURLs is nil, all shortened URL Terse data the
+// principal is authorized for are expected. The error must be storage.ErrShortenedNotFound if a shortened URL is not
+// found.
+func (s StoreManager) Terse(ctx context.Context, principal *models.Principal, shortenedURLs []string) (terse map[string]*models.Terse, err error) {
 
-	// Turn the input slice into a set.
-	shortenedURLs = makeStringSliceSet(shortenedURLs)
+	// Only use the shortened URLs the principal is authorized for.
+	var none bool
+	if shortenedURLs, none, err = s.authorizedShortened(ctx, principal, shortenedURLs); err != nil {
+		return nil, err
+	}
+	if none {
+		return make(map[string]*models.Terse), nil
+	}
 
 	return s.terseStore.Read(ctx, shortenedURLs)
 }
 
-// Visits exports the Visits data for the given shortened URLs. If shortenedURLs is nil, then all shortened URL Visits
-// data are expected. The error must be storage.ErrShortenedNotFound if a shortened URL is not found.
-func (s StoreManager) Visits(ctx context.Context, shortenedURLs []string) (visits map[string][]models.Visit, err error) {
+// TersePage returns a sorted page of Terse data for the given shortened URLs. If shortenedURLs is nil, all shortened URL
+// Terse data the principal is authorized for are paginated. Sorting by visit count requires a SummaryStore. The error
+// will be storage.ErrUnsupportedSort if there is no SummaryStore to sort by visit count with.
+func (s StoreManager) TersePage(ctx context.Context, principal *models.Principal, shortenedURLs []string, page Page) (terse *models.TersePage, err error) {
 
-	// Turn the input slice into a set.
-	shortenedURLs = makeStringSliceSet(shortenedURLs)
+	// Only use the shortened URLs the principal is authorized for.
+	var none bool
+	if shortenedURLs, none, err = s.authorizedShortened(ctx, principal, shortenedURLs); err != nil {
+		return nil, err
+	}
+
+	// Create the return value.
+	terse = &models.TersePage{
+		Terse: make([]*models.Terse, 0),
+	}
+	if none {
+		return terse, nil
+	}
+
+	// The TerseStore does not have the visit counts, so use the SummaryStore to sort by visit count.
+	if page.Sort != SortVisitCount {
+		if terse.Terse, terse.NextCursor, err = s.terseStore.ReadPage(ctx, page, shortenedURLs); err != nil {
+			return nil, err
+		}
+		return terse, nil
+	}
+
+	// Get the page of shortened URLs sorted by visit count.
+	var summaries []*models.Summary
+	err = ErrUnsupportedSort
+	s.SummaryStore(func(store SummaryStore) {
+		summaries, terse.NextCursor, err = store.ReadPage(ctx, page, shortenedURLs)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(summaries) == 0 {
+		return terse, nil
+	}
+
+	// Get the Terse data for the page of shortened URLs.
+	pageShortened := make([]string, len(summaries))
+	for i, summary := range summaries {
+		pageShortened[i] = summary.Terse.ShortenedURL
+	}
+	var terseData map[string]*models.Terse
+	if terseData, err = s.terseStore.Read(ctx, pageShortened); err != nil {
+		return nil, err
+	}
+
+	// Keep the Terse data in the sorted order.
+	for _, shortened := range pageShortened {
+		terse.Terse = append(terse.Terse, terseData[shortened])
+	}
+
+	return terse, nil
+}
+
+// Visits exports the Visits data that match the filter for the given shortened URLs. If shortenedURLs is nil, then all
+// shortened URL Visits data the principal is authorized for are expected. The error must be
+// storage.ErrShortenedNotFound if a shortened URL is not found.
+func (s StoreManager) Visits(ctx context.Context, principal *models.Principal, shortenedURLs []string, filter VisitsFilter) (visits map[string][]models.Visit, err error) {
+
+	// Only use the shortened URLs the principal is authorized for.
+	var none bool
+	if shortenedURLs, none, err = s.authorizedShortened(ctx, principal, shortenedURLs); err != nil {
+		return nil, err
+	}
 
 	// Create the return map.
 	visits = make(map[string][]models.Visit, len(shortenedURLs))
+	if none {
+		return visits, nil
+	}
 
 	// Get the Visits data from the VisitsStore.
 	s.VisitsStore(func(store VisitsStore) {
-		visits, err = store.Read(ctx, shortenedURLs)
+		visits, err = store.ReadFiltered(ctx, filter, shortenedURLs)
 	})
 	if err != nil {
 		return nil, err
@@ -315,14 +853,26 @@ func (s StoreManager) VisitsStore(doThis func(store VisitsStore)) {
 // WriteTerse Write writes the given Terse data according to the given operation. The error must be
 // storage.ErrShortenedExists if an Insert operation cannot be performed due to the Terse data already existing. The
 // error must be storage.ErrShortenedNotFound if an Update operation cannot be performed due to the Terse data not
-// existing.
-func (s StoreManager) WriteTerse(ctx context.Context, terse map[string]*models.Terse, operation WriteOperation) (err error) {
+// existing. The error must be storage.ErrUnauthorized if the principal would overwrite a shortened URL it is not
+// authorized for. The principal will become the owner of the written shortened URLs.
+func (s StoreManager) WriteTerse(ctx context.Context, principal *models.Principal, terse map[string]*models.Terse, operation WriteOperation) (err error) {
+
+	// Confirm the principal is not overwriting shortened URLs it is not authorized for.
+	var owners map[string]*models.Principal
+	if owners, err = s.authorizeWrite(ctx, principal, terse); err != nil {
+		return err
+	}
 
 	// Write the Terse data.
 	if err = s.terseStore.Write(ctx, terse, operation); err != nil {
 		return err
 	}
 
+	// Record the principal as the owner of the written shortened URLs.
+	if err = s.upsertOwners(ctx, owners); err != nil {
+		return err
+	}
+
 	// Add the shortened URLs to the SummaryStore, if required.
 	s.SummaryStore(func(store SummaryStore) {
 		summaries := make(map[string]*models.Summary)
@@ -405,10 +955,214 @@ func (s StoreManager) WriteTerse(ctx context.Context, terse map[string]*models.T
 	return nil
 }
 
+// authorizeWrite confirms the principal is authorized to write the given Terse data. Shortened URLs that do not exist
+// yet can be written by anyone. Existing shortened URLs can only be written by their owner or an administrator. The
+// returned owners should be upserted into the AuthorizationStore after the write. The error will be
+// storage.ErrUnauthorized if the principal is not authorized.
+func (s StoreManager) authorizeWrite(ctx context.Context, principal *models.Principal, terse map[string]*models.Terse) (owners map[string]*models.Principal, err error) {
+
+	// Create the return map.
+	owners = make(map[string]*models.Principal, len(terse))
+
+	// Without a principal or AuthorizationStore, there is nothing to check.
+	if principal == nil || s.authStore == nil {
+		return owners, nil
+	}
+
+	// Get the shortened URLs the principal already owns.
+	var authorized map[*models.Principal][]string
+	if authorized, err = s.authStore.AuthorizedShortened(ctx, []*models.Principal{principal}); err != nil {
+		return nil, err
+	}
+	owned := make(map[string]struct{}, len(authorized[principal]))
+	for _, shortened := range authorized[principal] {
+		owned[shortened] = struct{}{}
+	}
+
+	// Iterate through the shortened URLs to write. Confirm the ones not owned by the principal don't exist yet.
+	for shortened := range terse {
+		if _, ok := owned[shortened]; ok {
+			owners[shortened] = principal
+			continue
+		}
+		if _, err = s.terseStore.Read(ctx, []string{shortened}); err != nil {
+			if errors.Is(err, ErrShortenedNotFound) {
+				owners[shortened] = principal
+				continue
+			}
+			return nil, err
+		}
+
+		// Administrators can overwrite existing shortened URLs without taking ownership of them.
+		if !s.Admin(principal) {
+			return nil, fmt.Errorf("%w: %s", ErrUnauthorized, shortened)
+		}
+	}
+
+	return owners, nil
+}
+
+// authorizedShortened filters the given shortened URLs to only those the principal is authorized for. If the principal
+// is an administrator or the AuthorizationStore is nil, the shortened URLs are returned as a set. If shortenedURLs is
+// empty, all shortened URLs the principal is authorized for are returned and none will be true if there are not any.
+// The error will be storage.ErrUnauthorized if a given shortened URL is not authorized for the principal.
+func (s StoreManager) authorizedShortened(ctx context.Context, principal *models.Principal, shortenedURLs []string) (authorized []string, none bool, err error) {
+
+	// Turn the input slice into a set.
+	shortenedURLs = makeStringSliceSet(shortenedURLs)
+
+	// Without a principal or AuthorizationStore, there is nothing to filter. Administrators are authorized for all
+	// shortened URLs.
+	if s.authStore == nil || s.Admin(principal) {
+		return shortenedURLs, false, nil
+	}
+
+	// Get the shortened URLs the principal owns.
+	var owned map[*models.Principal][]string
+	if owned, err = s.authStore.AuthorizedShortened(ctx, []*models.Principal{principal}); err != nil {
+		return nil, false, err
+	}
+
+	// Check for the empty case.
+	if len(shortenedURLs) == 0 {
+		return owned[principal], len(owned[principal]) == 0, nil
+	}
+
+	// Confirm all the given shortened URLs are owned by the principal.
+	set := make(map[string]struct{}, len(owned[principal]))
+	for _, shortened := range owned[principal] {
+		set[shortened] = struct{}{}
+	}
+	for _, shortened := range shortenedURLs {
+		if _, ok := set[shortened]; !ok {
+			return nil, false, fmt.Errorf("%w: %s", ErrUnauthorized, shortened)
+		}
+	}
+
+	return shortenedURLs, false, nil
+}
+
+// clearVisits deletes all Visits data for the given shortened URLs, then inserts empty Visits data for them, so they
+// can still be read like the Visits data of a new shortened URL. If shortenedURLs is empty, all shortened URLs are
+// cleared. Shortened URLs without Visits data are skipped.
+func clearVisits(ctx context.Context, store VisitsStore, shortenedURLs []string) (err error) {
+
+	// Find the shortened URLs with Visits data.
+	var existing []string
+	if existing, err = store.Shortened(ctx); err != nil {
+		return err
+	}
+	cleared := make(map[string][]models.Visit, len(existing))
+	if len(shortenedURLs) == 0 {
+		for _, shortened := range existing {
+			cleared[shortened] = make([]models.Visit, 0)
+		}
+	} else {
+		requested := make(map[string]struct{}, len(shortenedURLs))
+		for _, shortened := range shortenedURLs {
+			requested[shortened] = struct{}{}
+		}
+		for _, shortened := range existing {
+			if _, ok := requested[shortened]; ok {
+				cleared[shortened] = make([]models.Visit, 0)
+			}
+		}
+	}
+	if len(cleared) == 0 {
+		return nil
+	}
+
+	// Delete the Visits data, including the aggregate counts of pruned visits.
+	deleted := make([]string, 0, len(cleared))
+	for shortened := range cleared {
+		deleted = append(deleted, shortened)
+	}
+	if err = store.Delete(ctx, deleted); err != nil {
+		return err
+	}
+
+	// Insert the empty Visits data.
+	return store.Insert(ctx, cleared)
+}
+
+// countedVisits removes the visits from automated clients from the visit count of the Visits Summary data, unless bots
+// are counted.
+func (s StoreManager) countedVisits(visits *models.VisitsSummary) {
+	if !s.countBots {
+		visits.VisitCount -= visits.BotCount
+	}
+}
+
+// deleteShortened deletes the Visits data, Summary data, and Authorization data for the given shortened URLs. If
+// shortenedURLs is nil or empty, all of the data are deleted.
+func (s StoreManager) deleteShortened(ctx context.Context, shortenedURLs []string) (err error) {
+
+	// Delete the Visits data for the shortened URL.
+	s.VisitsStore(func(store VisitsStore) {
+		err = store.Delete(ctx, shortenedURLs)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Delete the Summary data for the shortened URL.
+	s.SummaryStore(func(store SummaryStore) {
+		err = store.Delete(ctx, shortenedURLs)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Delete the Authorization data for the shortened URL.
+	s.AuthorizationStore(func(store AuthorizationStore) {
+		err = store.Delete(ctx, shortenedURLs)
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// expired determines if the given shortened URL's Terse data has expired by time or by visit count. The visit count
+// comes from the SummaryStore, if present, plus the given number of pending visits not yet in the SummaryStore.
+func (s StoreManager) expired(ctx context.Context, shortened string, terse models.Terse, pending uint64) (expired bool, err error) {
+
+	// Get the visit count, only if it is needed.
+	var visitCount uint64
+	if terse.MaxVisits != 0 {
+		s.SummaryStore(func(store SummaryStore) {
+			var summaries map[string]*models.Summary
+			if summaries, err = store.Read(ctx, []string{shortened}); err != nil {
+				if errors.Is(err, ErrShortenedNotFound) {
+					err = nil
+				}
+				return
+			}
+			if visits := summaries[shortened].Visits; visits != nil {
+				visitCount = visits.VisitCount
+			}
+		})
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return terseExpired(terse, visitCount+pending, time.Now()), nil
+}
+
 // handleVisit happens asynchronously when a redirect occurs. It updates the appropriate data stores with the required
-// information.
+// information. The visit Redirect reserved is released when the visit count is incremented.
 func (s StoreManager) handleVisit(shortened string, visit models.Visit) {
 
+	// Keep track of whether the visit was reserved as a bot before it is processed.
+	bot := visit.Bot
+
+	// Process the visit before it is stored, like adding its location or removing personal data.
+	for _, process := range s.visitProcess {
+		visit = process(visit)
+	}
+
 	// Add the Visits data to the VisitsStore.
 	{
 		ctx, cancel := s.createCtx()
@@ -422,12 +1176,35 @@ func (s StoreManager) handleVisit(shortened string, visit models.Visit) {
 		})
 	}
 
-	// Update the count in the SummaryStore.
+	// Update the count in the SummaryStore. The visit Redirect reserved is released with the increment. If the work item
+	// is not done, because its context expired or the group is dead, the visit is released when the work item is
+	// canceled.
 	{
 		ctx, cancel := s.createCtx()
-		s.group.AddWorkItem(ctx, cancel, func(workCtx context.Context) (err error) {
-			s.SummaryStore(func(store SummaryStore) {
-				err = store.IncrementVisitCount(workCtx, shortened)
+		var settled sync.Once
+		release := func() {
+			cancel()
+			settled.Do(func() {
+				s.ReleaseVisit(shortened, bot)
+			})
+		}
+		if s.group.Dead() {
+			release()
+			return
+		}
+		s.group.AddWorkItem(ctx, release, func(workCtx context.Context) (err error) {
+			settled.Do(func() {
+				increment := func() (err error) {
+					s.SummaryStore(func(store SummaryStore) {
+						err = store.IncrementVisitCount(workCtx, shortened, visit, s.countBots || !visit.Bot)
+					})
+					return err
+				}
+				if s.countBots || !bot {
+					err = s.pending.settle(shortened, increment)
+				} else {
+					err = increment()
+				}
 			})
 
 			return err
@@ -435,6 +1212,92 @@ func (s StoreManager) handleVisit(shortened string, visit models.Visit) {
 	}
 }
 
+// syncVisitCounts sets the Visits Summary data and unique visitor sketches in the SummaryStore to those of the
+// VisitsStore for the given shortened URLs. If shortenedURLs is empty, all Visits Summary data are set. Shortened URLs
+// without Visits data have a visit count of zero. Shortened URLs without Summary data are skipped.
+func (s StoreManager) syncVisitCounts(ctx context.Context, shortenedURLs []string) (err error) {
+	s.SummaryStore(func(store SummaryStore) {
+
+		// Get the current Summary data.
+		var summaries map[string]*models.Summary
+		if len(shortenedURLs) == 0 {
+			if summaries, err = store.Read(ctx, nil); err != nil {
+				return
+			}
+		} else {
+			summaries = make(map[string]*models.Summary, len(shortenedURLs))
+			for _, shortened := range shortenedURLs {
+				var summary map[string]*models.Summary
+				if summary, err = store.Read(ctx, []string{shortened}); err != nil {
+					if errors.Is(err, ErrShortenedNotFound) {
+						err = nil
+						continue
+					}
+					return
+				}
+				summaries[shortened] = summary[shortened]
+			}
+		}
+
+		// Summarize the visits left in the VisitsStore.
+		updated := make(map[string]*models.Summary, len(summaries))
+		sketches := make(map[string]*Sketch, len(summaries))
+		for shortened, summary := range summaries {
+			visits := &models.VisitsSummary{}
+			sketch := NewSketch()
+			var visitsSummary map[string]*models.VisitsSummary
+			var visitors map[string]*Sketch
+			s.VisitsStore(func(visitsStore VisitsStore) {
+				if visitsSummary, err = visitsStore.Summary(ctx, []string{shortened}); err != nil {
+					return
+				}
+				visitors, err = visitsStore.Visitors(ctx, []string{shortened})
+			})
+			if err != nil {
+				if !errors.Is(err, ErrShortenedNotFound) {
+					return
+				}
+				err = nil
+			} else if visitsSummary[shortened] != nil && visitors[shortened] != nil {
+				visits = visitsSummary[shortened]
+				sketch = visitors[shortened]
+				s.countedVisits(visits)
+			}
+
+			// Copy the Summary data with the new Visits Summary data.
+			updated[shortened] = &models.Summary{
+				Terse:  summary.Terse,
+				Visits: visits,
+			}
+			sketches[shortened] = sketch
+		}
+
+		// Upsert the new visit counts and unique visitor sketches into the SummaryStore.
+		if err = store.Upsert(ctx, updated); err != nil {
+			return
+		}
+		err = store.UpsertVisitors(ctx, sketches)
+	})
+
+	return err
+}
+
+// upsertOwners records the owners of the given shortened URLs, if there is an AuthorizationStore.
+func (s StoreManager) upsertOwners(ctx context.Context, owners map[string]*models.Principal) (err error) {
+
+	// Only record ownership if there are owners.
+	if len(owners) == 0 {
+		return nil
+	}
+
+	// Upsert the owners into the AuthorizationStore.
+	s.AuthorizationStore(func(store AuthorizationStore) {
+		err = store.Upsert(ctx, owners)
+	})
+
+	return err
+}
+
 // makeStringSliceSet makes a slice of strings a set by removing duplicate elements.
 func makeStringSliceSet(slice []string) (set []string) {
 
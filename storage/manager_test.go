@@ -0,0 +1,339 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MicahParks/ctxerrgroup"
+	"github.com/go-openapi/strfmt"
+
+	"github.com/MicahParks/terseurl/models"
+)
+
+// newMemManager creates a StoreManager with in memory data stores.
+func newMemManager() (manager StoreManager) {
+	return NewStoreManager(nil, nil, false, nil, ctxerrgroup.Group{}, NewMemSummary(), NewMemTerse(), nil, NewMemVisits())
+}
+
+// TestStoreManager_Expired confirms expired and orphaned shortened URLs are found, and orphaned ones only when asked.
+func TestStoreManager_Expired(t *testing.T) {
+	ctx := context.Background()
+	manager := newMemManager()
+	now := time.Now()
+	past := strfmt.DateTime(now.Add(-time.Hour))
+
+	// Write the Terse data, Summary data, and Visits data.
+	if err := manager.terseStore.Write(ctx, map[string]*models.Terse{
+		"expired":   {OriginalURL: "https://example.com", ShortenedURL: "expired", ExpiresAt: &past},
+		"maxVisits": {OriginalURL: "https://example.com", ShortenedURL: "maxVisits", MaxVisits: 1},
+		"active":    {OriginalURL: "https://example.com", ShortenedURL: "active"},
+	}, Insert); err != nil {
+		t.Fatalf("failed to write Terse data: %s", err.Error())
+	}
+	if err := manager.summaryStore.Upsert(ctx, map[string]*models.Summary{
+		"maxVisits":     {Visits: &models.VisitsSummary{VisitCount: 1}},
+		"orphanSummary": {},
+	}); err != nil {
+		t.Fatalf("failed to write Summary data: %s", err.Error())
+	}
+	if err := manager.visitsStore.Insert(ctx, map[string][]models.Visit{"orphanVisits": {{}}}); err != nil {
+		t.Fatalf("failed to write Visits data: %s", err.Error())
+	}
+
+	for _, orphans := range []bool{false, true} {
+		expired, orphaned, err := manager.Expired(ctx, now, orphans)
+		if err != nil {
+			t.Fatalf("failed to find expired shortened URLs: %s", err.Error())
+		}
+		sort.Strings(expired)
+		sort.Strings(orphaned)
+		if fmt.Sprint(expired) != "[expired maxVisits]" {
+			t.Fatalf("orphans %t: unexpected expired shortened URLs %v", orphans, expired)
+		}
+		expectedOrphaned := "[]"
+		if orphans {
+			expectedOrphaned = "[orphanSummary orphanVisits]"
+		}
+		if fmt.Sprint(orphaned) != expectedOrphaned {
+			t.Fatalf("orphans %t: unexpected orphaned shortened URLs %v", orphans, orphaned)
+		}
+	}
+}
+
+// TestStoreManager_ExpiredConcurrent confirms finding expired shortened URLs does not race writes to the in memory data
+// stores. Run it with the race detector.
+func TestStoreManager_ExpiredConcurrent(t *testing.T) {
+	ctx := context.Background()
+	manager := newMemManager()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			shortened := fmt.Sprintf("short%d", i)
+			if err := manager.terseStore.Write(ctx, map[string]*models.Terse{shortened: {OriginalURL: "https://example.com", ShortenedURL: shortened}}, Upsert); err != nil {
+				t.Errorf("failed to write Terse data: %s", err.Error())
+				return
+			}
+			if err := manager.summaryStore.Upsert(ctx, map[string]*models.Summary{shortened: {}}); err != nil {
+				t.Errorf("failed to write Summary data: %s", err.Error())
+				return
+			}
+			if err := manager.visitsStore.Insert(ctx, map[string][]models.Visit{shortened: {{}}}); err != nil {
+				t.Errorf("failed to write Visits data: %s", err.Error())
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < 200; i++ {
+		if _, _, err := manager.Expired(ctx, time.Now(), true); err != nil {
+			t.Fatalf("failed to find expired shortened URLs: %s", err.Error())
+		}
+	}
+	wg.Wait()
+}
+
+// TestStoreManager_DeleteVisits confirms clearing the Visits data of shortened URLs keeps empty Visits data for them and
+// does not create Visits data for shortened URLs without any.
+func TestStoreManager_DeleteVisits(t *testing.T) {
+	ctx := context.Background()
+	manager := newMemManager()
+
+	// Write the Visits data.
+	if err := manager.visitsStore.Insert(ctx, map[string][]models.Visit{"a": {{}, {}}, "b": {{}}}); err != nil {
+		t.Fatalf("failed to write Visits data: %s", err.Error())
+	}
+
+	// Clear one shortened URL, then all of them.
+	for _, shortenedURLs := range [][]string{{"a", "missing"}, nil} {
+		if err := manager.DeleteVisits(ctx, nil, shortenedURLs, VisitsFilter{}); err != nil {
+			t.Fatalf("failed to delete Visits data: %s", err.Error())
+		}
+		visitsData, err := manager.visitsStore.Read(ctx, []string{"a", "b"})
+		if err != nil {
+			t.Fatalf("failed to read Visits data after deleting %v: %s", shortenedURLs, err.Error())
+		}
+		if len(visitsData["a"]) != 0 {
+			t.Fatalf("expected no visits, got %d", len(visitsData["a"]))
+		}
+		expected := 1
+		if shortenedURLs == nil {
+			expected = 0
+		}
+		if len(visitsData["b"]) != expected {
+			t.Fatalf("expected %d visits, got %d", expected, len(visitsData["b"]))
+		}
+		if _, err = manager.visitsStore.Read(ctx, []string{"missing"}); !errors.Is(err, ErrShortenedNotFound) {
+			t.Fatalf("expected %v, got %v", ErrShortenedNotFound, err)
+		}
+	}
+}
+
+// TestStoreManager_RecordVisit confirms the visit Redirect reserved is released when the visit is counted, when the
+// work item's context expired, and when the group is dead.
+func TestStoreManager_RecordVisit(t *testing.T) {
+	testCases := []struct {
+		name    string
+		expired bool
+		dead    bool
+		counted uint64
+	}{
+		{name: "counted", counted: 1},
+		{name: "context expired", expired: true},
+		{name: "group dead", dead: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			// Create a manager whose work items may never be done.
+			group := ctxerrgroup.New(1, func(_ ctxerrgroup.Group, _ error) {})
+			createCtx := func() (ctx context.Context, cancel context.CancelFunc) {
+				ctx, cancel = context.WithCancel(context.Background())
+				if testCase.expired {
+					cancel()
+				}
+				return ctx, cancel
+			}
+			manager := NewStoreManager(nil, nil, false, createCtx, group, NewMemSummary(), NewMemTerse(), nil, NewMemVisits())
+			if err := manager.terseStore.Write(ctx, map[string]*models.Terse{
+				"short": {OriginalURL: "https://example.com", ShortenedURL: "short", MaxVisits: 1},
+			}, Insert); err != nil {
+				t.Fatalf("failed to write Terse data: %s", err.Error())
+			}
+			if err := manager.summaryStore.Upsert(ctx, map[string]*models.Summary{"short": {Visits: &models.VisitsSummary{}}}); err != nil {
+				t.Fatalf("failed to write Summary data: %s", err.Error())
+			}
+
+			// Reserve the visit, then keep track of it.
+			if _, _, err := manager.Redirect(ctx, "short", "", false); err != nil {
+				t.Fatalf("failed to redirect: %s", err.Error())
+			}
+			if testCase.dead {
+				group.Kill()
+			}
+			manager.handleVisit("short", models.Visit{})
+			if !testCase.dead {
+				group.Wait()
+			}
+
+			// Confirm the reservation was released.
+			manager.pending.mux.Lock()
+			pending := manager.pending.counts["short"]
+			manager.pending.mux.Unlock()
+			if pending != 0 {
+				t.Fatalf("expected no pending visits, got %d", pending)
+			}
+
+			// Confirm the visit count.
+			summaries, err := manager.summaryStore.Read(ctx, []string{"short"})
+			if err != nil {
+				t.Fatalf("failed to read Summary data: %s", err.Error())
+			}
+			if visitCount := summaries["short"].Visits.VisitCount; visitCount != testCase.counted {
+				t.Fatalf("expected %d, got %d", testCase.counted, visitCount)
+			}
+		})
+	}
+}
+
+// slowSummary is a SummaryStore that pauses after incrementing a visit count, like a slow storage backend.
+type slowSummary struct {
+	SummaryStore
+}
+
+// IncrementVisitCount increments the visit count, then pauses.
+func (s slowSummary) IncrementVisitCount(ctx context.Context, shortened string, visit models.Visit, counted bool) (err error) {
+	err = s.SummaryStore.IncrementVisitCount(ctx, shortened, visit, counted)
+	time.Sleep(time.Millisecond)
+	return err
+}
+
+// TestStoreManager_RedirectMaxVisits confirms concurrent visits are allowed until the maximum visit count, but not past
+// it, while visits are being counted. Run it with the race detector.
+func TestStoreManager_RedirectMaxVisits(t *testing.T) {
+	ctx := context.Background()
+	const maxVisits = 50
+
+	group := ctxerrgroup.New(4, func(_ ctxerrgroup.Group, err error) {
+		t.Errorf("failed to count visit: %s", err.Error())
+	})
+	createCtx := func() (ctx context.Context, cancel context.CancelFunc) {
+		return context.WithTimeout(context.Background(), time.Minute)
+	}
+	manager := NewStoreManager(nil, nil, false, createCtx, group, slowSummary{NewMemSummary()}, NewMemTerse(), nil, NewMemVisits())
+	if err := manager.terseStore.Write(ctx, map[string]*models.Terse{
+		"short": {OriginalURL: "https://example.com", ShortenedURL: "short", MaxVisits: maxVisits},
+	}, Insert); err != nil {
+		t.Fatalf("failed to write Terse data: %s", err.Error())
+	}
+	if err := manager.summaryStore.Upsert(ctx, map[string]*models.Summary{"short": {Visits: &models.VisitsSummary{}}}); err != nil {
+		t.Fatalf("failed to write Summary data: %s", err.Error())
+	}
+
+	// Visit the shortened URL concurrently until the maximum visit count.
+	var wg sync.WaitGroup
+	for i := 0; i < maxVisits; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, _, err := manager.Redirect(ctx, "short", "", false); err != nil {
+				t.Errorf("failed to redirect: %s", err.Error())
+				return
+			}
+			manager.handleVisit("short", models.Visit{})
+		}()
+	}
+	wg.Wait()
+	group.Wait()
+
+	// Confirm the next visit is over the maximum visit count.
+	if _, _, err := manager.Redirect(ctx, "short", "", false); !errors.Is(err, ErrShortenedExpired) {
+		t.Fatalf("expected %v, got %v", ErrShortenedExpired, err)
+	}
+}
+
+// TestStoreManager_DeleteExpired confirms only the shortened URLs that are still expired or orphaned are deleted, for
+// every TerseStore implementation.
+func TestStoreManager_DeleteExpired(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config string
+	}{
+		{name: "memory"},
+		{name: "bbolt", config: fmt.Sprintf(`{"type":"bbolt","bboltPath":%q}`, filepath.Join(t.TempDir(), "terse.bbolt"))},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := context.Background()
+			now := time.Now()
+			past := strfmt.DateTime(now.Add(-time.Hour))
+			future := strfmt.DateTime(now.Add(time.Hour))
+
+			// Create the manager.
+			terseStore, _, err := NewTerseStore([]byte(testCase.config))
+			if err != nil {
+				t.Fatalf("failed to create TerseStore: %s", err.Error())
+			}
+			defer terseStore.Close(ctx)
+			manager := NewStoreManager(nil, nil, false, nil, ctxerrgroup.Group{}, NewMemSummary(), terseStore, nil, NewMemVisits())
+
+			// Write the Terse data and Visits data.
+			if err = manager.terseStore.Write(ctx, map[string]*models.Terse{
+				"expired": {OriginalURL: "https://example.com", ShortenedURL: "expired", ExpiresAt: &past},
+				"updated": {OriginalURL: "https://example.com", ShortenedURL: "updated", ExpiresAt: &past},
+			}, Insert); err != nil {
+				t.Fatalf("failed to write Terse data: %s", err.Error())
+			}
+			if err = manager.visitsStore.Insert(ctx, map[string][]models.Visit{
+				"expired": {{}}, "updated": {{}}, "orphan": {{}}, "recreated": {{}},
+			}); err != nil {
+				t.Fatalf("failed to write Visits data: %s", err.Error())
+			}
+
+			// Find the expired and orphaned shortened URLs.
+			expired, orphaned, err := manager.Expired(ctx, now, true)
+			if err != nil {
+				t.Fatalf("failed to find expired shortened URLs: %s", err.Error())
+			}
+
+			// Update an expired shortened URL and recreate an orphaned one before they are deleted.
+			if err = manager.terseStore.Write(ctx, map[string]*models.Terse{
+				"updated":   {OriginalURL: "https://example.com", ShortenedURL: "updated", ExpiresAt: &future},
+				"recreated": {OriginalURL: "https://example.com", ShortenedURL: "recreated"},
+			}, Upsert); err != nil {
+				t.Fatalf("failed to write Terse data: %s", err.Error())
+			}
+
+			// Confirm only the shortened URLs that are still expired or orphaned are deleted.
+			deletedExpired, deletedOrphaned, err := manager.DeleteExpired(ctx, now, expired, orphaned)
+			if err != nil {
+				t.Fatalf("failed to delete expired shortened URLs: %s", err.Error())
+			}
+			if fmt.Sprint(deletedExpired) != "[expired]" {
+				t.Fatalf("unexpected deleted expired shortened URLs %v", deletedExpired)
+			}
+			if fmt.Sprint(deletedOrphaned) != "[orphan]" {
+				t.Fatalf("unexpected deleted orphaned shortened URLs %v", deletedOrphaned)
+			}
+			shortenedURLs, err := manager.visitsStore.Shortened(ctx)
+			if err != nil {
+				t.Fatalf("failed to list shortened URLs with Visits data: %s", err.Error())
+			}
+			sort.Strings(shortenedURLs)
+			if fmt.Sprint(shortenedURLs) != "[recreated updated]" {
+				t.Fatalf("unexpected shortened URLs with Visits data %v", shortenedURLs)
+			}
+		})
+	}
+}
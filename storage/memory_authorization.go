@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"context"
+	"sync"
+
+	"github.com/MicahParks/terseurl/models"
+)
+
+// MemAuthorization is an AuthorizationStore implementation that stores all data in a Go map in memory.
+type MemAuthorization struct {
+	mux    sync.RWMutex
+	owners map[string]string
+}
+
+// NewMemAuthorization creates a new MemAuthorization.
+func NewMemAuthorization() (authStore AuthorizationStore) {
+	return &MemAuthorization{
+		owners: make(map[string]string),
+	}
+}
+
+// AuthorizedShortened creates a map of users to the shortened URLs they are authorized for.
+func (m *MemAuthorization) AuthorizedShortened(_ context.Context, users []*models.Principal) (authorized map[*models.Principal][]string, err error) {
+
+	// Create the return map.
+	authorized = make(map[*models.Principal][]string, len(users))
+
+	// Lock the Authorization data for async safe use.
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	// Iterate through the given users.
+	for _, user := range users {
+
+		// Gather the shortened URLs the user owns.
+		shortenedURLs := make([]string, 0)
+		for shortened, owner := range m.owners {
+			if owner == user.Sub {
+				shortenedURLs = append(shortenedURLs, shortened)
+			}
+		}
+
+		// Add the user's shortened URLs to the return map.
+		authorized[user] = shortenedURLs
+	}
+
+	return authorized, nil
+}
+
+// Close closes the connection to the underlying storage.
+func (m *MemAuthorization) Close(_ context.Context) (err error) {
+
+	// Lock the Authorization data for async safe use.
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	// Delete all the Authorization data.
+	m.deleteAll()
+
+	return nil
+}
+
+// Delete deletes the authorization information for the given shortened URLs. If shortenedURLs is nil or empty, all
+// Authorization data are deleted. No error should be returned if a shortened URL is not found.
+func (m *MemAuthorization) Delete(_ context.Context, shortenedURLs []string) (err error) {
+
+	// Lock the Authorization data for async safe use.
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	// Check for the empty case.
+	if len(shortenedURLs) == 0 {
+
+		// Delete all Authorization data.
+		m.deleteAll()
+	} else {
+
+		// Iterate through the given shortened URLs.
+		for _, shortened := range shortenedURLs {
+			delete(m.owners, shortened)
+		}
+	}
+
+	return nil
+}
+
+// Upsert upserts the owner of the given shortened URLs. The owner is identified by the principal's subject.
+func (m *MemAuthorization) Upsert(_ context.Context, owners map[string]*models.Principal) (err error) {
+
+	// Lock the Authorization data for async safe use.
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	// Iterate through the given owners. Upsert the Authorization data.
+	for shortened, owner := range owners {
+		m.owners[shortened] = owner.Sub
+	}
+
+	return nil
+}
+
+// deleteAll deletes all of the Authorization data. It does not lock, so a lock must be used for async safe usage.
+func (m *MemAuthorization) deleteAll() {
+
+	// Reassign the Authorization data so it's taken by the garbage collector.
+	m.owners = make(map[string]string)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"sync"
 
+	"github.com/go-openapi/strfmt"
+
 	"github.com/MicahParks/terseurl/models"
 )
 
@@ -14,12 +16,14 @@ import (
 type MemSummary struct {
 	summaries map[string]*models.Summary
 	mux       sync.RWMutex
+	visitors  map[string]*Sketch
 }
 
 // NewMemSummary creates a new MemSummary.
 func NewMemSummary() (summaryStore SummaryStore) {
 	return &MemSummary{
 		summaries: make(map[string]*models.Summary),
+		visitors:  make(map[string]*Sketch),
 	}
 }
 
@@ -54,15 +58,17 @@ func (m *MemSummary) Delete(_ context.Context, shortenedURLs []string) (err erro
 		// Iterate through the given shortened URLs.
 		for _, shortened := range shortenedURLs {
 			delete(m.summaries, shortened)
+			delete(m.visitors, shortened)
 		}
 	}
 
 	return nil
 }
 
-// IncrementVisitCount increments the visit count for the given shortened URL. It is called in separate goroutine.
+// IncrementVisitCount adds the visit to the visit counts, breakdowns, and unique visitors of the Visits Summary data for
+// the given shortened URL. The visit count is only incremented if counted is true. It is called in separate goroutine.
 // The error must be storage.ErrShortenedNotFound if the shortened URL is not found.
-func (m *MemSummary) IncrementVisitCount(_ context.Context, shortened string) (err error) {
+func (m *MemSummary) IncrementVisitCount(_ context.Context, shortened string, visit models.Visit, counted bool) (err error) {
 
 	// Lock the Summary data for async safe use.
 	m.mux.Lock()
@@ -74,8 +80,24 @@ func (m *MemSummary) IncrementVisitCount(_ context.Context, shortened string) (e
 		return ErrShortenedNotFound
 	}
 
-	// Increment the visits count.
-	summary.Visits.VisitCount++
+	// Add the visit to the visit counts and breakdowns.
+	counts := make(map[string]uint64)
+	countVisit(counts, visit)
+	addCounts(summary.Visits, counts)
+	if !counted {
+
+		// The visit is still in the daily visit counts.
+		summary.Visits.VisitCount--
+	}
+
+	// Add the visitor to the unique visitor Sketch. Shortened URLs without one have had no visits.
+	sketch, ok := m.visitors[shortened]
+	if !ok {
+		sketch = NewSketch()
+		m.visitors[shortened] = sketch
+	}
+	sketch.Add(visit)
+	summary.Visits.UniqueVisitors = sketch.Estimate()
 
 	// Reassign the summary data.
 	m.summaries[shortened] = summary
@@ -84,7 +106,8 @@ func (m *MemSummary) IncrementVisitCount(_ context.Context, shortened string) (e
 }
 
 // Read provides the summary information for the given shortened URLs. If shortenedURLs is nil or empty, all
-// summaries are returned. The error must be storage.ErrShortenedNotFound if a shortened URL is not found.
+// summaries are returned. The error must be storage.ErrShortenedNotFound if a shortened URL is not found. The Summary
+// data are copied, because IncrementVisitCount changes them after the lock is released.
 func (m *MemSummary) Read(_ context.Context, shortenedURLs []string) (summaries map[string]*models.Summary, err error) {
 
 	// Create the return map.
@@ -97,8 +120,11 @@ func (m *MemSummary) Read(_ context.Context, shortenedURLs []string) (summaries
 	// Check to see if all Summary data was requested, if so, copy all Summary data.
 	if len(shortenedURLs) == 0 {
 
-		// Use all Summary data.
-		summaries = m.summaries
+		// Copy all Summary data, so the map can be used after the lock is released.
+		summaries = make(map[string]*models.Summary, len(m.summaries))
+		for shortened, summary := range m.summaries {
+			summaries[shortened] = copySummary(summary)
+		}
 	} else {
 
 		// Iterate through the given shortened URLs. Copy the requested ones.
@@ -107,13 +133,65 @@ func (m *MemSummary) Read(_ context.Context, shortenedURLs []string) (summaries
 			if !ok {
 				return nil, ErrShortenedNotFound
 			}
-			summaries[shortened] = summary
+			summaries[shortened] = copySummary(summary)
 		}
 	}
 
 	return summaries, nil
 }
 
+// ReadPage provides the summary information for the given shortened URLs as a sorted page. If shortenedURLs is nil or
+// empty, all summaries are paginated. The next return value is the cursor for the following page. It is empty if there
+// are no more results. The error must be storage.ErrShortenedNotFound if a shortened URL is not found. The error must
+// be storage.ErrInvalidCursor if the page's cursor cannot be used. The Summary data are copied, like with Read.
+func (m *MemSummary) ReadPage(_ context.Context, page Page, shortenedURLs []string) (summaries []*models.Summary, next string, err error) {
+
+	// Lock the Summary data for async safe use.
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	// Check for the empty case.
+	if len(shortenedURLs) == 0 {
+
+		// Paginate all Summary data.
+		shortenedURLs = make([]string, 0, len(m.summaries))
+		for shortened := range m.summaries {
+			shortenedURLs = append(shortenedURLs, shortened)
+		}
+	}
+
+	// Create the page keys for the shortened URLs.
+	keys := make([]string, 0, len(shortenedURLs))
+	for _, shortened := range shortenedURLs {
+		summary, ok := m.summaries[shortened]
+		if !ok {
+			return nil, "", ErrShortenedNotFound
+		}
+		var createdAt *strfmt.DateTime
+		if summary.Terse != nil {
+			createdAt = summary.Terse.CreatedAt
+		}
+		var visitCount uint64
+		if summary.Visits != nil {
+			visitCount = summary.Visits.VisitCount
+		}
+		keys = append(keys, pageKey(page.Sort, shortened, createdAt, visitCount))
+	}
+
+	// Only use the page keys in the requested page.
+	if keys, next, err = page.paginate(keys); err != nil {
+		return nil, "", err
+	}
+
+	// Add the Summary data to the return slice in order.
+	summaries = make([]*models.Summary, len(keys))
+	for i, key := range keys {
+		summaries[i] = copySummary(m.summaries[pageKeyShortened(key)])
+	}
+
+	return summaries, next, nil
+}
+
 // Upsert upserts the Summary data for the given shortened URL.
 func (m *MemSummary) Upsert(_ context.Context, summaries map[string]*models.Summary) (err error) {
 
@@ -129,9 +207,62 @@ func (m *MemSummary) Upsert(_ context.Context, summaries map[string]*models.Summ
 	return nil
 }
 
+// UpsertVisitors upserts the unique visitor Sketch for the given shortened URLs. The unique visitors of their Visits
+// Summary data are set to the sketch's estimate. Later visits are added to the sketch by IncrementVisitCount.
+func (m *MemSummary) UpsertVisitors(_ context.Context, sketches map[string]*Sketch) (err error) {
+
+	// Lock the Summary data for async safe use.
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	// Iterate through the given sketches. Upsert the sketch and update the estimate.
+	for shortened, sketch := range sketches {
+		m.visitors[shortened] = sketch
+		if summary, ok := m.summaries[shortened]; ok && summary.Visits != nil {
+			summary.Visits.UniqueVisitors = sketch.Estimate()
+		}
+	}
+
+	return nil
+}
+
 // deleteAll deletes all of the Summary data. It does not lock, so a lock must be used for async safe usage.
 func (m *MemSummary) deleteAll() {
 
 	// Reassign the Summary data so it's taken by the garbage collector.
 	m.summaries = make(map[string]*models.Summary)
+	m.visitors = make(map[string]*Sketch)
+}
+
+// copySummary creates a deep copy of the Summary data, so it can be used after the lock is released.
+func copySummary(summary *models.Summary) (copied *models.Summary) {
+	copied = &models.Summary{}
+	if summary.Terse != nil {
+		terse := *summary.Terse
+		copied.Terse = &terse
+	}
+	if summary.Visits != nil {
+		visits := *summary.Visits
+		visits.Browsers = copyCounts(visits.Browsers)
+		visits.Countries = copyCounts(visits.Countries)
+		visits.DailyCounts = copyCounts(visits.DailyCounts)
+		visits.Devices = copyCounts(visits.Devices)
+		visits.OperatingSystems = copyCounts(visits.OperatingSystems)
+		visits.Referrers = copyCounts(visits.Referrers)
+		visits.Variants = copyCounts(visits.Variants)
+		copied.Visits = &visits
+	}
+	return copied
+}
+
+// copyCounts creates a copy of the given counts. A nil map stays nil.
+func copyCounts(counts map[string]uint64) (copied map[string]uint64) {
+	if counts == nil {
+		return nil
+	}
+	copied = make(map[string]uint64, len(counts))
+	for key, count := range counts {
+		copied[key] = count
+	}
+	return copied
 }
@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/MicahParks/terseurl/models"
+)
+
+// TestMemSummary_ReadConcurrent confirms the Summary data from Read and ReadPage can be used while visits are counted.
+// Run it with the race detector.
+func TestMemSummary_ReadConcurrent(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemSummary()
+	if err := store.Upsert(ctx, map[string]*models.Summary{"short": {Visits: &models.VisitsSummary{}}}); err != nil {
+		t.Fatalf("failed to write Summary data: %s", err.Error())
+	}
+
+	// Count visits with new breakdown values, so the count maps are written to.
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			visit := models.Visit{
+				Country: string(rune('A'+i%26)) + string(rune('A'+i/26)),
+				Headers: http.Header{"Referer": {"https://example" + string(rune('a'+i%26)) + ".com"}},
+			}
+			if err := store.IncrementVisitCount(ctx, "short", visit, true); err != nil {
+				t.Errorf("failed to count visit: %s", err.Error())
+				return
+			}
+		}
+	}()
+
+	// Encode the Summary data like the API does.
+	for i := 0; i < 200; i++ {
+		summaries, err := store.Read(ctx, nil)
+		if err != nil {
+			t.Fatalf("failed to read Summary data: %s", err.Error())
+		}
+		page, _, err := store.ReadPage(ctx, Page{}, []string{"short"})
+		if err != nil {
+			t.Fatalf("failed to read Summary data page: %s", err.Error())
+		}
+		if _, err = json.Marshal(summaries); err != nil {
+			t.Fatalf("failed to encode Summary data: %s", err.Error())
+		}
+		if _, err = json.Marshal(page); err != nil {
+			t.Fatalf("failed to encode Summary data page: %s", err.Error())
+		}
+	}
+	wg.Wait()
+}
+
+// TestMemSummary_ReadCopy confirms changing the Summary data from Read does not change the stored Summary data.
+func TestMemSummary_ReadCopy(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemSummary()
+	if err := store.Upsert(ctx, map[string]*models.Summary{"short": {
+		Terse:  &models.TerseSummary{MaxVisits: 1},
+		Visits: &models.VisitsSummary{BotCount: 1, Countries: map[string]uint64{"US": 1}, VisitCount: 2},
+	}}); err != nil {
+		t.Fatalf("failed to write Summary data: %s", err.Error())
+	}
+
+	// Change the read Summary data.
+	summaries, err := store.Read(ctx, []string{"short"})
+	if err != nil {
+		t.Fatalf("failed to read Summary data: %s", err.Error())
+	}
+	summaries["short"].Terse.MaxVisits = 5
+	summaries["short"].Visits.Countries["US"] = 5
+	summaries["short"].Visits.VisitCount = 5
+
+	// Confirm the stored Summary data did not change.
+	if summaries, err = store.Read(ctx, []string{"short"}); err != nil {
+		t.Fatalf("failed to read Summary data: %s", err.Error())
+	}
+	summary := summaries["short"]
+	if summary.Terse.MaxVisits != 1 || summary.Visits.Countries["US"] != 1 || summary.Visits.VisitCount != 2 {
+		t.Fatalf("the stored Summary data changed")
+	}
+}
@@ -2,21 +2,28 @@ package storage
 
 import (
 	"context"
+	"sort"
 	"sync"
+	"time"
+
+	"github.com/go-openapi/strfmt"
 
 	"github.com/MicahParks/terseurl/models"
 )
 
-// MemTerse is a TerseStore implementation that stores all data in a Go map in memory.
+// MemTerse is a TerseStore implementation that stores all data in a Go map in memory. The dedupe index maps dedupe keys
+// to the set of shortened URLs with that key.
 type MemTerse struct {
-	mux   sync.RWMutex
-	terse map[string]*models.Terse
+	dedupe map[string]map[string]struct{}
+	mux    sync.RWMutex
+	terse  map[string]*models.Terse
 }
 
 // NewMemTerse creates a new MemTerse given the required assets.
 func NewMemTerse() (terseStore TerseStore) {
 	return &MemTerse{
-		terse: make(map[string]*models.Terse),
+		dedupe: make(map[string]map[string]struct{}),
+		terse:  make(map[string]*models.Terse),
 	}
 }
 
@@ -50,6 +57,9 @@ func (m *MemTerse) Delete(_ context.Context, shortenedURLs []string) (err error)
 
 		// Iterate through the given shortened URLs.
 		for _, shortened := range shortenedURLs {
+			if terse, ok := m.terse[shortened]; ok {
+				m.dedupeRemove(shortened, terse)
+			}
 			delete(m.terse, shortened)
 		}
 	}
@@ -57,6 +67,56 @@ func (m *MemTerse) Delete(_ context.Context, shortenedURLs []string) (err error)
 	return nil
 }
 
+// DeleteExpired deletes the Terse data for the given shortened URLs that have expired at the given time with the given
+// visit counts. Expiration is checked when the Terse data are deleted, so Terse data updated after they were found to
+// be expired are kept. The shortened URLs whose Terse data were deleted are returned. There should be no error if a
+// shortened URL is not found.
+func (m *MemTerse) DeleteExpired(_ context.Context, visitCounts map[string]uint64, now time.Time) (deleted []string, err error) {
+
+	// Lock the Terse data for async safe use.
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	// Iterate through the given shortened URLs.
+	deleted = make([]string, 0, len(visitCounts))
+	for shortened, visitCount := range visitCounts {
+
+		// Only delete the Terse data if it has expired.
+		terse, ok := m.terse[shortened]
+		if !ok || !terseExpired(*terse, visitCount, now) {
+			continue
+		}
+		m.dedupeRemove(shortened, terse)
+		delete(m.terse, shortened)
+		deleted = append(deleted, shortened)
+	}
+
+	return deleted, nil
+}
+
+// Deduplicate returns a map of the given dedupe keys to the shortened URLs whose Terse data have that dedupe key.
+// Dedupe keys without any shortened URLs are not in the map. See storage.DedupeKey.
+func (m *MemTerse) Deduplicate(_ context.Context, keys []string) (shortenedURLs map[string][]string, err error) {
+
+	// Create the return map.
+	shortenedURLs = make(map[string][]string, len(keys))
+
+	// Lock the Terse data for async safe usage.
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	// Iterate through the given dedupe keys.
+	for _, key := range keys {
+
+		// Add the shortened URLs with the dedupe key to the return map.
+		for shortened := range m.dedupe[key] {
+			shortenedURLs[key] = append(shortenedURLs[key], shortened)
+		}
+	}
+
+	return shortenedURLs, nil
+}
+
 // Read returns a map of shortened URLs to Terse data. If shortenedURLs is nil or empty, all shortened URL Terse
 // data are expected. The error must be storage.ErrShortenedNotFound if a shortened URL is not found.
 func (m *MemTerse) Read(_ context.Context, shortenedURLs []string) (terseData map[string]*models.Terse, err error) {
@@ -71,8 +131,11 @@ func (m *MemTerse) Read(_ context.Context, shortenedURLs []string) (terseData ma
 	// Check for the empty case.
 	if len(shortenedURLs) == 0 {
 
-		// Use all Terse data.
-		terseData = m.terse
+		// Copy all Terse data, so the map can be used after the lock is released.
+		terseData = make(map[string]*models.Terse, len(m.terse))
+		for shortened, terse := range m.terse {
+			terseData[shortened] = terse
+		}
 	} else {
 
 		// Iterate through the given shortened URLs.
@@ -92,6 +155,106 @@ func (m *MemTerse) Read(_ context.Context, shortenedURLs []string) (terseData ma
 	return terseData, nil
 }
 
+// ReadPage returns the Terse data for the given shortened URLs as a sorted page. If shortenedURLs is nil or empty, all
+// shortened URL Terse data are paginated. The next return value is the cursor for the following page. It is empty if
+// there are no more results. The error must be storage.ErrShortenedNotFound if a shortened URL is not found. The error
+// must be storage.ErrInvalidCursor if the page's cursor cannot be used. The error must be storage.ErrUnsupportedSort
+// if the page is sorted by visit count.
+func (m *MemTerse) ReadPage(_ context.Context, page Page, shortenedURLs []string) (terseData []*models.Terse, next string, err error) {
+
+	// The visit count is not part of the Terse data.
+	if page.Sort == SortVisitCount {
+		return nil, "", ErrUnsupportedSort
+	}
+
+	// Lock the Terse data for async safe usage.
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	// Check for the empty case.
+	if len(shortenedURLs) == 0 {
+
+		// Paginate all shortened URLs.
+		shortenedURLs = make([]string, 0, len(m.terse))
+		for shortened := range m.terse {
+			shortenedURLs = append(shortenedURLs, shortened)
+		}
+	}
+
+	// Create the page keys for the shortened URLs.
+	keys := make([]string, 0, len(shortenedURLs))
+	for _, shortened := range shortenedURLs {
+		terse, ok := m.terse[shortened]
+		if !ok {
+			return nil, "", ErrShortenedNotFound
+		}
+		keys = append(keys, pageKey(page.Sort, shortened, terse.CreatedAt, 0))
+	}
+
+	// Only use the page keys in the requested page.
+	if keys, next, err = page.paginate(keys); err != nil {
+		return nil, "", err
+	}
+
+	// Add the Terse data to the return slice in order.
+	terseData = make([]*models.Terse, len(keys))
+	for i, key := range keys {
+		terseData[i] = m.terse[pageKeyShortened(key)]
+	}
+
+	return terseData, next, nil
+}
+
+// Search returns the Terse data whose original URL matches the query, sorted by shortened URL. Only shortened URLs
+// after query.After are searched and at most query.Limit Terse data are returned. If shortenedURLs is nil or empty,
+// all shortened URLs are searched. The next return value is the last returned shortened URL if there are more
+// matches, otherwise it is empty.
+func (m *MemTerse) Search(_ context.Context, query models.SearchQuery, shortenedURLs []string) (terseData []*models.Terse, next string, err error) {
+
+	// Create the return slice.
+	terseData = make([]*models.Terse, 0)
+
+	// Lock the Terse data for async safe usage.
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	// Check for the empty case.
+	if len(shortenedURLs) == 0 {
+
+		// Search all shortened URLs.
+		shortenedURLs = make([]string, 0, len(m.terse))
+		for shortened := range m.terse {
+			shortenedURLs = append(shortenedURLs, shortened)
+		}
+	}
+
+	// Sort the shortened URLs so pages are consistent.
+	sorted := make([]string, len(shortenedURLs))
+	copy(sorted, shortenedURLs)
+	sort.Strings(sorted)
+
+	// Iterate through the shortened URLs after the given one.
+	for _, shortened := range sorted[sort.Search(len(sorted), func(i int) bool { return sorted[i] > query.After }):] {
+
+		// Only use the Terse data that matches.
+		terse, ok := m.terse[shortened]
+		if !ok || !terseMatches(*terse, query.Match, query.Value) {
+			continue
+		}
+
+		// Stop if there are more matches than the limit.
+		if uint64(len(terseData)) == query.Limit {
+			next = terseData[len(terseData)-1].ShortenedURL
+			break
+		}
+
+		// Add the Terse data to the return slice.
+		terseData = append(terseData, terse)
+	}
+
+	return terseData, next, nil
+}
+
 // Summary summarizes the Terse data for the given shortened URLs. If shortenedURLs is nil or empty, then all
 // shortened URL Summary data are expected.
 func (m *MemTerse) Summary(_ context.Context, shortenedURLs []string) (summaries map[string]*models.TerseSummary, err error) {
@@ -131,7 +294,8 @@ func (m *MemTerse) Summary(_ context.Context, shortenedURLs []string) (summaries
 
 // Write writes the given Terse data according to the given operation. The error must be storage.ErrShortenedExists
 // if an Insert operation cannot be performed due to the Terse data already existing. The error must be
-// storage.ErrShortenedNotFound if an Update operation cannot be performed due to the Terse data not existing.
+// storage.ErrShortenedNotFound if an Update operation cannot be performed due to the Terse data not existing. Terse
+// data without a creation time are given the existing creation time, or the current time if the shortened URL is new.
 func (m *MemTerse) Write(_ context.Context, terseData map[string]*models.Terse, operation WriteOperation) (err error) {
 
 	// Lock the Terse data for async safe use.
@@ -152,16 +316,51 @@ func (m *MemTerse) Write(_ context.Context, terseData map[string]*models.Terse,
 			}
 		}
 
+		// Remove the old Terse data from the dedupe index. Keep its creation time.
+		old, ok := m.terse[shortened]
+		if ok {
+			m.dedupeRemove(shortened, old)
+		}
+		if terse.CreatedAt == nil {
+			if ok {
+				terse.CreatedAt = old.CreatedAt
+			} else {
+				now := strfmt.DateTime(time.Now())
+				terse.CreatedAt = &now
+			}
+		}
+
 		// Assign the shortened URL the given Terse data.
 		m.terse[shortened] = terse
+		m.dedupeAdd(shortened, terse)
 	}
 
 	return nil
 }
 
+// dedupeAdd adds the shortened URL to the dedupe index. It does not lock, so a lock must be used for async safe usage.
+func (m *MemTerse) dedupeAdd(shortened string, terse *models.Terse) {
+	key := DedupeKey(terse.OriginalURL, terse.RedirectType)
+	if m.dedupe[key] == nil {
+		m.dedupe[key] = make(map[string]struct{})
+	}
+	m.dedupe[key][shortened] = struct{}{}
+}
+
+// dedupeRemove removes the shortened URL from the dedupe index. It does not lock, so a lock must be used for async safe
+// usage.
+func (m *MemTerse) dedupeRemove(shortened string, terse *models.Terse) {
+	key := DedupeKey(terse.OriginalURL, terse.RedirectType)
+	delete(m.dedupe[key], shortened)
+	if len(m.dedupe[key]) == 0 {
+		delete(m.dedupe, key)
+	}
+}
+
 // deleteAll deletes all of the Terse data. It does not lock, so a lock must be used for async safe usage.
 func (m *MemTerse) deleteAll() {
 
-	// Reassign the Terse data so it's taken by the garbage collector.
+	// Reassign the Terse data and dedupe index so they're taken by the garbage collector.
+	m.dedupe = make(map[string]map[string]struct{})
 	m.terse = make(map[string]*models.Terse)
 }
@@ -2,21 +2,27 @@ package storage
 
 import (
 	"context"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/MicahParks/terseurl/models"
 )
 
 // MemVisits is a VisitsStore implementation that stores all data in a Go map in memory.
 type MemVisits struct {
-	mux    sync.RWMutex
-	visits map[string][]models.Visit
+	mux      sync.RWMutex
+	rollups  map[string]map[string]uint64
+	visitors map[string]*Sketch
+	visits   map[string][]models.Visit
 }
 
 // NewMemVisits creates a new MemVisits.
 func NewMemVisits() (visitsStore VisitsStore) {
 	return &MemVisits{
-		visits: make(map[string][]models.Visit),
+		rollups:  make(map[string]map[string]uint64),
+		visitors: make(map[string]*Sketch),
+		visits:   make(map[string][]models.Visit),
 	}
 }
 
@@ -33,8 +39,9 @@ func (m *MemVisits) Close(_ context.Context) (err error) {
 	return nil
 }
 
-// Delete deletes Visits data for the given shortened URLs. If shortenedURLs is nil or empty, then all Visits data
-// are deleted. No error should be given if a shortened URL is not found.
+// Delete deletes Visits data for the given shortened URLs, including the aggregate counts of pruned visits. If
+// shortenedURLs is nil or empty, then all Visits data are deleted. No error should be given if a shortened URL is not
+// found.
 func (m *MemVisits) Delete(_ context.Context, shortenedURLs []string) (err error) {
 
 	// Lock the Visits data for async safe use.
@@ -50,6 +57,8 @@ func (m *MemVisits) Delete(_ context.Context, shortenedURLs []string) (err error
 
 		// Iterate through the given shortened URLs.
 		for _, shortened := range shortenedURLs {
+			delete(m.rollups, shortened)
+			delete(m.visitors, shortened)
 			delete(m.visits, shortened)
 		}
 	}
@@ -57,6 +66,97 @@ func (m *MemVisits) Delete(_ context.Context, shortenedURLs []string) (err error
 	return nil
 }
 
+// DeleteFiltered deletes the visits that match the filter for the given shortened URLs. The filter's limit is not used.
+// The aggregate counts of pruned visits are not deleted. If shortenedURLs is nil or empty, then the visits of all
+// shortened URLs are filtered. No error should be given if a shortened URL is not found.
+func (m *MemVisits) DeleteFiltered(_ context.Context, filter VisitsFilter, shortenedURLs []string) (err error) {
+
+	// Lock the Visits data for async safe use.
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	// Check for the empty case.
+	if len(shortenedURLs) == 0 {
+
+		// Filter all Visits data.
+		shortenedURLs = make([]string, 0, len(m.visits))
+		for shortened := range m.visits {
+			shortenedURLs = append(shortenedURLs, shortened)
+		}
+	}
+
+	// Iterate through the given shortened URLs.
+	for _, shortened := range shortenedURLs {
+
+		// Get the Visits data for the shortened URL.
+		visits, ok := m.visits[shortened]
+		if !ok {
+			continue
+		}
+
+		// Only keep the visits that do not match the filter.
+		kept := make([]models.Visit, 0, len(visits))
+		for _, visit := range visits {
+			if !filter.Matches(visit) {
+				kept = append(kept, visit)
+			}
+		}
+		m.visits[shortened] = kept
+	}
+
+	return nil
+}
+
+// Histogram counts the visits in the series' time window for the given shortened URLs. The counts are keyed by the
+// Unix time of the start of the interval the visits are in. Pruned visits are counted at the start of their UTC day. If
+// shortenedURLs is nil or empty, then all shortened URLs are counted. No error should be given if a shortened URL is not
+// found.
+func (m *MemVisits) Histogram(_ context.Context, series Series, shortenedURLs []string) (histograms map[string]map[int64]uint64, err error) {
+
+	// Create the return map.
+	histograms = make(map[string]map[int64]uint64, len(shortenedURLs))
+
+	// Lock the Visits data for async safe use.
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	// Check for the empty case.
+	if len(shortenedURLs) == 0 {
+
+		// Count all Visits data.
+		shortenedURLs = make([]string, 0, len(m.visits))
+		for shortened := range m.visits {
+			shortenedURLs = append(shortenedURLs, shortened)
+		}
+	}
+
+	// Iterate through the given shortened URLs.
+	for _, shortened := range shortenedURLs {
+
+		// Get the Visits data for the shortened URL.
+		visits, ok := m.visits[shortened]
+		if !ok {
+			continue
+		}
+
+		// Count the visits and the pruned visits.
+		counts := make(map[int64]uint64)
+		for _, visit := range visits {
+			series.count(counts, visitTime(visit), 1)
+		}
+		for key, count := range m.rollups[shortened] {
+			if t, ok := dayTime(key); ok {
+				series.count(counts, t, count)
+			}
+		}
+
+		// Add the visit counts to the return map.
+		histograms[shortened] = counts
+	}
+
+	return histograms, nil
+}
+
 // Insert inserts the given Visits data. The visits do not need to be unique, so the Visits data should be appended
 // to the data structure in storage.
 func (m *MemVisits) Insert(_ context.Context, visitsData map[string][]models.Visit) (err error) {
@@ -67,12 +167,45 @@ func (m *MemVisits) Insert(_ context.Context, visitsData map[string][]models.Vis
 
 	// Iterate through the given Visits data and append it to the existing.
 	for shortened, visits := range visitsData {
-		visitsData[shortened] = append(visitsData[shortened], visits...)
+		m.visits[shortened] = append(m.visits[shortened], visits...)
 	}
 
 	return nil
 }
 
+// Prune rolls up the visits before the given time into aggregate counts and a unique visitor Sketch, then deletes them.
+// The aggregate counts are the daily visit counts and the breakdowns of Visits Summary data. They are kept until all
+// Visits data for the shortened URL are deleted. The number of pruned visits is returned.
+func (m *MemVisits) Prune(_ context.Context, before time.Time) (pruned uint64, err error) {
+
+	// Lock the Visits data for async safe use.
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	// Iterate through all the Visits data.
+	for shortened, visits := range m.visits {
+
+		// Roll up the visits before the cutoff and keep the rest.
+		kept := make([]models.Visit, 0, len(visits))
+		for _, visit := range visits {
+			if !visitTime(visit).Before(before) {
+				kept = append(kept, visit)
+				continue
+			}
+			if m.rollups[shortened] == nil {
+				m.rollups[shortened] = make(map[string]uint64)
+				m.visitors[shortened] = NewSketch()
+			}
+			countVisit(m.rollups[shortened], visit)
+			m.visitors[shortened].Add(visit)
+			pruned++
+		}
+		m.visits[shortened] = kept
+	}
+
+	return pruned, nil
+}
+
 // Read exports the Visits data for the given shortened URLs. If shortenedURLs is nil or empty, then all shortened
 // URL Visits data are expected. The error must be storage.ErrShortenedNotFound if a shortened URL is not found.
 func (m *MemVisits) Read(_ context.Context, shortenedURLs []string) (visitsData map[string][]models.Visit, err error) {
@@ -87,8 +220,11 @@ func (m *MemVisits) Read(_ context.Context, shortenedURLs []string) (visitsData
 	// Check for the empty case.
 	if len(shortenedURLs) == 0 {
 
-		// Use all Visits data.
-		visitsData = m.visits
+		// Copy all Visits data, so the map can be used after the lock is released.
+		visitsData = make(map[string][]models.Visit, len(m.visits))
+		for shortened, visits := range m.visits {
+			visitsData[shortened] = visits
+		}
 	} else {
 
 		// Iterate through the given shortened URLs.
@@ -108,9 +244,80 @@ func (m *MemVisits) Read(_ context.Context, shortenedURLs []string) (visitsData
 	return visitsData, nil
 }
 
-// Summary summarizes the Visits data for the given shortened URLs. If shortenedURLs is nil or empty, then all
-// shortened URL Summary data are expected. The error must be storage.ErrShortenedNotFound if a shortened URL is not
+// ReadFiltered exports the Visits data that match the filter for the given shortened URLs. The visits are sorted by
+// time and at most filter.Limit visits are exported for each shortened URL. If shortenedURLs is nil or empty, then all
+// shortened URL Visits data are expected. The error must be storage.ErrShortenedNotFound if a shortened URL is not
 // found.
+func (m *MemVisits) ReadFiltered(_ context.Context, filter VisitsFilter, shortenedURLs []string) (visitsData map[string][]models.Visit, err error) {
+
+	// Create the return map.
+	visitsData = make(map[string][]models.Visit, len(shortenedURLs))
+
+	// Lock the Visits data for async safe use.
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	// Check for the empty case.
+	if len(shortenedURLs) == 0 {
+
+		// Filter all Visits data.
+		shortenedURLs = make([]string, 0, len(m.visits))
+		for shortened := range m.visits {
+			shortenedURLs = append(shortenedURLs, shortened)
+		}
+	}
+
+	// Iterate through the shortened URLs.
+	for _, shortened := range shortenedURLs {
+
+		// Get the Visits data for the shortened URL.
+		visits, ok := m.visits[shortened]
+		if !ok {
+			return nil, ErrShortenedNotFound
+		}
+
+		// Only keep the visits that match the filter.
+		matched := make([]models.Visit, 0)
+		for _, visit := range visits {
+			if filter.Matches(visit) {
+				matched = append(matched, visit)
+			}
+		}
+
+		// Sort the visits by time, then apply the limit.
+		sort.SliceStable(matched, func(i, j int) bool {
+			return visitTime(matched[i]).Before(visitTime(matched[j]))
+		})
+		if filter.Limit != 0 && uint64(len(matched)) > filter.Limit {
+			matched = matched[:filter.Limit]
+		}
+
+		// Add the Visits data to the return map.
+		visitsData[shortened] = matched
+	}
+
+	return visitsData, nil
+}
+
+// Shortened lists the shortened URLs with Visits data.
+func (m *MemVisits) Shortened(_ context.Context) (shortenedURLs []string, err error) {
+
+	// Lock the Visits data for async safe use.
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	// Collect the shortened URLs.
+	shortenedURLs = make([]string, 0, len(m.visits))
+	for shortened := range m.visits {
+		shortenedURLs = append(shortenedURLs, shortened)
+	}
+
+	return shortenedURLs, nil
+}
+
+// Summary summarizes the Visits data for the given shortened URLs. The summaries include pruned visits. If
+// shortenedURLs is nil or empty, then all shortened URL Summary data are expected. The error must be
+// storage.ErrShortenedNotFound if a shortened URL is not found.
 func (m *MemVisits) Summary(_ context.Context, shortenedURLs []string) (summaries map[string]*models.VisitsSummary, err error) {
 
 	// Create the return map.
@@ -125,9 +332,7 @@ func (m *MemVisits) Summary(_ context.Context, shortenedURLs []string) (summarie
 
 		// Gather the Summary data for all shortened URLs.
 		for shortened, visits := range m.visits {
-			summaries[shortened] = &models.VisitsSummary{
-				VisitCount: uint64(len(visits)),
-			}
+			summaries[shortened], _ = m.summarize(shortened, visits)
 		}
 	} else {
 
@@ -141,18 +346,82 @@ func (m *MemVisits) Summary(_ context.Context, shortenedURLs []string) (summarie
 			}
 
 			// Add the Visits data to the return map.
-			summaries[shortened] = &models.VisitsSummary{
-				VisitCount: uint64(len(visits)),
-			}
+			summaries[shortened], _ = m.summarize(shortened, visits)
 		}
 	}
 
 	return summaries, nil
 }
 
+// Visitors creates the unique visitor Sketch for each of the given shortened URLs. The sketches include the visitors of
+// pruned visits. If shortenedURLs is nil or empty, then all shortened URL sketches are expected. The error must be
+// storage.ErrShortenedNotFound if a shortened URL is not found.
+func (m *MemVisits) Visitors(_ context.Context, shortenedURLs []string) (sketches map[string]*Sketch, err error) {
+
+	// Create the return map.
+	sketches = make(map[string]*Sketch, len(shortenedURLs))
+
+	// Lock the Visits data for async safe use.
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	// Check for the empty case.
+	if len(shortenedURLs) == 0 {
+
+		// Create the sketches for all shortened URLs.
+		for shortened, visits := range m.visits {
+			_, sketches[shortened] = m.summarize(shortened, visits)
+		}
+	} else {
+
+		// Iterate through the given shortened URLs.
+		for _, shortened := range shortenedURLs {
+
+			// Get the Visits data for the shortened URL.
+			visits, ok := m.visits[shortened]
+			if !ok {
+				return nil, ErrShortenedNotFound
+			}
+
+			// Add the sketch to the return map.
+			_, sketches[shortened] = m.summarize(shortened, visits)
+		}
+	}
+
+	return sketches, nil
+}
+
 // deleteAll deletes all of the Visits data. It does not lock, so a lock must be used for async safe usage.
 func (m *MemVisits) deleteAll() {
 
 	// Reassign the Visits data so it's taken by the garbage collector.
+	m.rollups = make(map[string]map[string]uint64)
+	m.visitors = make(map[string]*Sketch)
 	m.visits = make(map[string][]models.Visit)
 }
+
+// summarize creates the Visits Summary data and the unique visitor Sketch for the given shortened URL and its visits. It
+// does not lock, so a lock must be used for async safe usage.
+func (m *MemVisits) summarize(shortened string, visits []models.Visit) (summary *models.VisitsSummary, sketch *Sketch) {
+
+	// Aggregate the visits and add the aggregate counts of the pruned visits.
+	counts := make(map[string]uint64)
+	sketch = NewSketch()
+	for _, visit := range visits {
+		countVisit(counts, visit)
+		sketch.Add(visit)
+	}
+	for key, count := range m.rollups[shortened] {
+		counts[key] += count
+	}
+	if pruned, ok := m.visitors[shortened]; ok {
+		sketch.Merge(pruned)
+	}
+
+	// Summarize the aggregate counts.
+	summary = &models.VisitsSummary{}
+	addCounts(summary, counts)
+	summary.UniqueVisitors = sketch.Estimate()
+
+	return summary, sketch
+}
@@ -0,0 +1,150 @@
+package storage
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+)
+
+const (
+
+	// SortCreatedAt sorts by the time the shortened URL was created. Ties are sorted by shortened URL.
+	SortCreatedAt PageSort = "createdAt"
+
+	// SortShortenedURL sorts by shortened URL.
+	SortShortenedURL PageSort = "shortenedURL"
+
+	// SortVisitCount sorts by the number of visits. Ties are sorted by shortened URL.
+	SortVisitCount PageSort = "visitCount"
+)
+
+var (
+
+	// ErrInvalidCursor indicates the given cursor could not be decoded or was created for a different sort.
+	ErrInvalidCursor = errors.New("the cursor is invalid or was created for a different sort")
+
+	// ErrUnsupportedSort indicates the data store cannot sort its data by the requested field.
+	ErrUnsupportedSort = errors.New("the data store does not support the sort")
+)
+
+// PageSort is the field to sort a page of results by.
+type PageSort string
+
+// Page describes a page of sorted results to read.
+type Page struct {
+
+	// Cursor is the opaque cursor returned with the previous page. If empty, the first page is read.
+	Cursor string
+
+	// Descending reverses the sort order.
+	Descending bool
+
+	// Limit is the maximum number of results in the page. If zero, all remaining results are in the page.
+	Limit uint64
+
+	// Sort is the field to sort by. If empty, SortShortenedURL is used.
+	Sort PageSort
+}
+
+// after decodes the cursor into the page key of the last result from the previous page. The key is empty if there is
+// no cursor. The error will be storage.ErrInvalidCursor if the cursor cannot be used with the page's sort.
+func (p Page) after() (key string, err error) {
+
+	// Check for the first page.
+	if p.Cursor == "" {
+		return "", nil
+	}
+
+	// Decode the cursor.
+	raw, err := base64.RawURLEncoding.DecodeString(p.Cursor)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidCursor, err.Error())
+	}
+
+	// Confirm the cursor was made for the same sort.
+	parts := strings.SplitN(string(raw), "\x00", 2)
+	if len(parts) != 2 || PageSort(parts[0]) != p.sort() {
+		return "", ErrInvalidCursor
+	}
+
+	return parts[1], nil
+}
+
+// cursor creates the opaque cursor for the page that follows the result with the given page key.
+func (p Page) cursor(key string) (cursor string) {
+	return base64.RawURLEncoding.EncodeToString([]byte(string(p.sort()) + "\x00" + key))
+}
+
+// paginate sorts the given page keys and returns the ones in the page. The next return value is the cursor for the
+// following page. It is empty if there are no more results.
+func (p Page) paginate(keys []string) (inPage []string, next string, err error) {
+
+	// Get the page key of the last result from the previous page.
+	after, err := p.after()
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Sort the page keys in the requested order.
+	if p.Descending {
+		sort.Sort(sort.Reverse(sort.StringSlice(keys)))
+	} else {
+		sort.Strings(keys)
+	}
+
+	// Skip the page keys from previous pages.
+	if after != "" {
+		keys = keys[sort.Search(len(keys), func(i int) bool {
+			if p.Descending {
+				return keys[i] < after
+			}
+			return keys[i] > after
+		}):]
+	}
+
+	// Only keep as many page keys as the limit allows.
+	if p.Limit != 0 && uint64(len(keys)) > p.Limit {
+		keys = keys[:p.Limit]
+		next = p.cursor(keys[len(keys)-1])
+	}
+
+	return keys, next, nil
+}
+
+// sort returns the field to sort by.
+func (p Page) sort() (pageSort PageSort) {
+	if p.Sort == "" {
+		return SortShortenedURL
+	}
+	return p.Sort
+}
+
+// pageKey creates a key for the shortened URL that sorts in the order of the given page sort. The sorted value is fixed
+// width and is followed by a null byte and the shortened URL, so ties are sorted by shortened URL.
+func pageKey(pageSort PageSort, shortened string, createdAt *strfmt.DateTime, visitCount uint64) (key string) {
+	switch pageSort {
+	case SortCreatedAt:
+		var nanoseconds int64
+		if createdAt != nil && time.Time(*createdAt).After(time.Unix(0, 0)) {
+			nanoseconds = time.Time(*createdAt).UnixNano()
+		}
+		return fmt.Sprintf("%016x\x00%s", nanoseconds, shortened)
+	case SortVisitCount:
+		return fmt.Sprintf("%016x\x00%s", visitCount, shortened)
+	default:
+		return shortened
+	}
+}
+
+// pageKeyShortened returns the shortened URL from a page key.
+func pageKeyShortened(key string) (shortened string) {
+	if i := strings.IndexByte(key, 0); i != -1 {
+		return key[i+1:]
+	}
+	return key
+}
@@ -0,0 +1,185 @@
+package storage
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+)
+
+// pageFixture is a shortened URL with the values it can be sorted by.
+type pageFixture struct {
+	shortened  string
+	createdAt  time.Time
+	visitCount uint64
+}
+
+// pageFixtures are the shortened URLs to paginate. The created times and visit counts are not in the same order as the
+// shortened URLs, and there are ties.
+var pageFixtures = []pageFixture{
+	{shortened: "a", createdAt: time.Unix(300, 0), visitCount: 10},
+	{shortened: "b", createdAt: time.Unix(100, 0), visitCount: 2},
+	{shortened: "c", createdAt: time.Unix(200, 0), visitCount: 2},
+	{shortened: "d", createdAt: time.Unix(100, 0), visitCount: 0},
+	{shortened: "e", createdAt: time.Unix(400, 0), visitCount: 300},
+}
+
+// pageKeys creates the page keys of the fixtures for the given sort.
+func pageKeys(pageSort PageSort) (keys []string) {
+	keys = make([]string, len(pageFixtures))
+	for i, fixture := range pageFixtures {
+		createdAt := strfmt.DateTime(fixture.createdAt)
+		keys[i] = pageKey(pageSort, fixture.shortened, &createdAt, fixture.visitCount)
+	}
+	return keys
+}
+
+// TestPage_paginate confirms every sort and direction can be read one page at a time by following the cursors.
+func TestPage_paginate(t *testing.T) {
+	testCases := []struct {
+		name       string
+		sort       PageSort
+		descending bool
+		expected   []string
+	}{
+		{name: "default", expected: []string{"a", "b", "c", "d", "e"}},
+		{name: "default descending", descending: true, expected: []string{"e", "d", "c", "b", "a"}},
+		{name: "shortenedURL", sort: SortShortenedURL, expected: []string{"a", "b", "c", "d", "e"}},
+		{name: "shortenedURL descending", sort: SortShortenedURL, descending: true, expected: []string{"e", "d", "c", "b", "a"}},
+		{name: "createdAt", sort: SortCreatedAt, expected: []string{"b", "d", "c", "a", "e"}},
+		{name: "createdAt descending", sort: SortCreatedAt, descending: true, expected: []string{"e", "a", "c", "d", "b"}},
+		{name: "visitCount", sort: SortVisitCount, expected: []string{"d", "b", "c", "a", "e"}},
+		{name: "visitCount descending", sort: SortVisitCount, descending: true, expected: []string{"e", "a", "c", "b", "d"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			for _, limit := range []uint64{1, 2, 3, 5, 6} {
+
+				// Follow the cursors until there are no more pages.
+				page := Page{
+					Descending: testCase.descending,
+					Limit:      limit,
+					Sort:       testCase.sort,
+				}
+				shortenedURLs := make([]string, 0, len(pageFixtures))
+				for pages := 0; ; pages++ {
+					if pages > len(pageFixtures) {
+						t.Fatalf("limit %d: too many pages", limit)
+					}
+
+					inPage, next, err := page.paginate(pageKeys(testCase.sort))
+					if err != nil {
+						t.Fatalf("limit %d: failed to paginate: %s", limit, err.Error())
+					}
+					if uint64(len(inPage)) > limit {
+						t.Fatalf("limit %d: page has %d results", limit, len(inPage))
+					}
+					for _, key := range inPage {
+						shortenedURLs = append(shortenedURLs, pageKeyShortened(key))
+					}
+
+					if next == "" {
+						break
+					}
+					page.Cursor = next
+				}
+
+				if !reflect.DeepEqual(shortenedURLs, testCase.expected) {
+					t.Fatalf("limit %d: expected %v, got %v", limit, testCase.expected, shortenedURLs)
+				}
+			}
+		})
+	}
+}
+
+// TestPage_paginateNoLimit confirms a page without a limit has all the results and no cursor.
+func TestPage_paginateNoLimit(t *testing.T) {
+	inPage, next, err := Page{}.paginate(pageKeys(SortShortenedURL))
+	if err != nil {
+		t.Fatalf("failed to paginate: %s", err.Error())
+	}
+	if len(inPage) != len(pageFixtures) {
+		t.Fatalf("expected %d results, got %d", len(pageFixtures), len(inPage))
+	}
+	if next != "" {
+		t.Fatalf("expected no cursor, got %q", next)
+	}
+}
+
+// TestPage_paginateInvalidCursor confirms cursors that cannot be decoded or were made for a different sort are
+// rejected.
+func TestPage_paginateInvalidCursor(t *testing.T) {
+	testCases := []struct {
+		name   string
+		cursor string
+		sort   PageSort
+	}{
+		{name: "not base64", cursor: "!!!"},
+		{name: "padded base64", cursor: base64.URLEncoding.EncodeToString([]byte(string(SortShortenedURL) + "\x00a"))},
+		{name: "no separator", cursor: base64.RawURLEncoding.EncodeToString([]byte("a"))},
+		{name: "unknown sort", cursor: base64.RawURLEncoding.EncodeToString([]byte("unknown\x00a"))},
+		{name: "different sort", cursor: Page{Sort: SortVisitCount}.cursor("a"), sort: SortCreatedAt},
+		{name: "different sort from default", cursor: Page{Sort: SortCreatedAt}.cursor("a")},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			page := Page{
+				Cursor: testCase.cursor,
+				Limit:  1,
+				Sort:   testCase.sort,
+			}
+			if _, _, err := page.paginate(pageKeys(page.sort())); !errors.Is(err, ErrInvalidCursor) {
+				t.Fatalf("expected %v, got %v", ErrInvalidCursor, err)
+			}
+		})
+	}
+}
+
+// TestPage_cursor confirms a cursor decodes to the page key it was made from.
+func TestPage_cursor(t *testing.T) {
+	for _, pageSort := range []PageSort{"", SortCreatedAt, SortShortenedURL, SortVisitCount} {
+		for _, key := range pageKeys(pageSort) {
+			page := Page{Sort: pageSort}
+			page.Cursor = page.cursor(key)
+			after, err := page.after()
+			if err != nil {
+				t.Fatalf("sort %q: failed to decode cursor: %s", pageSort, err.Error())
+			}
+			if after != key {
+				t.Fatalf("sort %q: expected %q, got %q", pageSort, key, after)
+			}
+		}
+	}
+}
+
+// TestPageKey confirms page keys keep the shortened URL and zero creation times are sorted first.
+func TestPageKey(t *testing.T) {
+	testCases := []struct {
+		name      string
+		sort      PageSort
+		createdAt *strfmt.DateTime
+	}{
+		{name: "shortenedURL", sort: SortShortenedURL},
+		{name: "createdAt nil", sort: SortCreatedAt},
+		{name: "createdAt zero", sort: SortCreatedAt, createdAt: &strfmt.DateTime{}},
+		{name: "visitCount", sort: SortVisitCount},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			key := pageKey(testCase.sort, "short", testCase.createdAt, 0)
+			if shortened := pageKeyShortened(key); shortened != "short" {
+				t.Fatalf("expected %q, got %q", "short", shortened)
+			}
+			earliest := strfmt.DateTime(time.Unix(1, 0))
+			if testCase.sort == SortCreatedAt && key >= pageKey(testCase.sort, "short", &earliest, 0) {
+				t.Fatalf("expected a missing creation time to be sorted first")
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"sync"
+)
+
+// pendingVisits keeps track of the visits Redirect has allowed that are not in the visit counts of the SummaryStore
+// yet. Visits are counted asynchronously, so without it, concurrent visits could go over a maximum visit count.
+type pendingVisits struct {
+	counts map[string]uint64
+	mux    sync.Mutex
+}
+
+// newPendingVisits creates a new pendingVisits with no visits.
+func newPendingVisits() (pending *pendingVisits) {
+	return &pendingVisits{
+		counts: make(map[string]uint64),
+	}
+}
+
+// release removes a pending visit for the shortened URL, if there is one.
+func (p *pendingVisits) release(shortened string) {
+
+	// Lock the counts for async safe use.
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	// Remove the pending visit.
+	p.remove(shortened)
+}
+
+// settle calls increment, which adds a visit to the visit count in the SummaryStore, and releases a pending visit for
+// the shortened URL in the same critical section, so Redirect never counts the visit twice. The pending visit is
+// released even if increment fails. Without pending visits for the shortened URL, increment is called without locking.
+func (p *pendingVisits) settle(shortened string, increment func() (err error)) (err error) {
+
+	// Lock the counts for async safe use.
+	p.mux.Lock()
+	if p.counts[shortened] == 0 {
+		p.mux.Unlock()
+		return increment()
+	}
+	defer p.mux.Unlock()
+
+	// Increment the visit count, then remove the pending visit.
+	defer p.remove(shortened)
+
+	return increment()
+}
+
+// remove removes a pending visit for the shortened URL, if there is one. The lock must be held.
+func (p *pendingVisits) remove(shortened string) {
+
+	// Delete the count when it reaches zero, so the map does not grow forever.
+	switch p.counts[shortened] {
+	case 0:
+	case 1:
+		delete(p.counts, shortened)
+	default:
+		p.counts[shortened]--
+	}
+}
@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/bits"
+	"net/http"
+
+	"github.com/MicahParks/terseurl/models"
+)
+
+const (
+
+	// sketchPrecision is the number of hash bits used to pick a register of a Sketch. It gives a standard error of about
+	// 1.04 / sqrt(2^12), or 1.6%.
+	sketchPrecision = 12
+
+	// sketchRegisters is the number of registers in a Sketch.
+	sketchRegisters = 1 << sketchPrecision
+)
+
+// ErrInvalidSketch indicates the data could not be turned into a Sketch.
+var ErrInvalidSketch = errors.New("the data is not a valid unique visitor sketch")
+
+// Sketch is a HyperLogLog sketch that estimates the number of unique visitors to a shortened URL. Visitors are
+// identified by a hash of their IP address and User-Agent. The hash is not kept, only the position of its leading one
+// bit in one of the registers, so visitors cannot be recovered from a Sketch.
+type Sketch struct {
+	registers []uint8
+}
+
+// NewSketch creates a new Sketch with no visitors.
+func NewSketch() (sketch *Sketch) {
+	return &Sketch{
+		registers: make([]uint8, sketchRegisters),
+	}
+}
+
+// Add adds the visitor of the given visit to the Sketch. Visits with neither an IP address nor a User-Agent are not
+// added.
+func (s *Sketch) Add(visit models.Visit) {
+
+	// Hash the visitor.
+	hash, ok := visitorHash(visit)
+	if !ok {
+		return
+	}
+
+	// Use the first bits of the hash to pick the register. Keep the position of the leading one bit of the rest.
+	index := hash >> (64 - sketchPrecision)
+	rank := uint8(bits.LeadingZeros64(hash<<sketchPrecision|1<<(sketchPrecision-1))) + 1
+	if rank > s.registers[index] {
+		s.registers[index] = rank
+	}
+}
+
+// Estimate estimates the number of unique visitors added to the Sketch.
+func (s *Sketch) Estimate() (visitors uint64) {
+
+	// Take the harmonic mean of the registers and count the empty ones.
+	var sum float64
+	var empty int
+	for _, rank := range s.registers {
+		sum += 1 / float64(uint64(1)<<rank)
+		if rank == 0 {
+			empty++
+		}
+	}
+
+	// Create the raw estimate.
+	m := float64(sketchRegisters)
+	estimate := 0.7213 / (1 + 1.079/m) * m * m / sum
+
+	// Use linear counting for small estimates, which is more accurate.
+	if estimate <= 2.5*m && empty != 0 {
+		estimate = m * math.Log(m/float64(empty))
+	}
+
+	return uint64(math.Round(estimate))
+}
+
+// Merge adds the visitors of the other Sketch to this one.
+func (s *Sketch) Merge(other *Sketch) {
+	for i, rank := range other.registers {
+		if rank > s.registers[i] {
+			s.registers[i] = rank
+		}
+	}
+}
+
+// bytesToSketch transforms bytes to a Sketch.
+func bytesToSketch(data []byte) (sketch *Sketch, err error) {
+	if len(data) != sketchRegisters {
+		return nil, ErrInvalidSketch
+	}
+	return &Sketch{
+		registers: append([]uint8{}, data...),
+	}, nil
+}
+
+// sketchToBytes transforms a Sketch to bytes.
+func sketchToBytes(sketch *Sketch) (data []byte) {
+	return append([]byte{}, sketch.registers...)
+}
+
+// visitorHash hashes the IP address and User-Agent of the visit's visitor. If ok is false, the visit has neither.
+func visitorHash(visit models.Visit) (hash uint64, ok bool) {
+
+	// Get the visitor's IP address and User-Agent.
+	var ip string
+	if visit.IP != nil {
+		ip = ipHost(*visit.IP)
+	}
+	userAgent := http.Header(visit.Headers).Get("User-Agent")
+	if ip == "" && userAgent == "" {
+		return 0, false
+	}
+
+	// Hash the visitor with SHA-256 and keep the first 64 bits.
+	sum := sha256.Sum256([]byte(ip + "\x00" + userAgent))
+
+	return binary.BigEndian.Uint64(sum[:8]), true
+}
@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/MicahParks/terseurl/models"
+)
+
+// sketchVisit creates a visit for the visitor with the given number.
+func sketchVisit(visitor int) (visit models.Visit) {
+	ip := fmt.Sprintf("10.%d.%d.%d:1234", visitor>>16&0xff, visitor>>8&0xff, visitor&0xff)
+	return models.Visit{
+		Headers: map[string][]string{"User-Agent": {"agent"}},
+		IP:      &ip,
+	}
+}
+
+// sketchOf creates a Sketch with the visitors numbered from start up to, but not including, end.
+func sketchOf(start, end int) (sketch *Sketch) {
+	sketch = NewSketch()
+	for visitor := start; visitor < end; visitor++ {
+		sketch.Add(sketchVisit(visitor))
+	}
+	return sketch
+}
+
+// TestSketch_Estimate confirms the estimate is within three standard errors of the number of unique visitors.
+func TestSketch_Estimate(t *testing.T) {
+	standardError := 1.04 / math.Sqrt(sketchRegisters)
+
+	for _, visitors := range []int{0, 1, 2, 10, 100, 1000, 5000, 10000, 50000, 200000} {
+		t.Run(fmt.Sprintf("%d visitors", visitors), func(t *testing.T) {
+			estimate := sketchOf(0, visitors).Estimate()
+			if allowed := math.Max(1, 3*standardError*float64(visitors)); math.Abs(float64(estimate)-float64(visitors)) > allowed {
+				t.Fatalf("expected %d ± %.0f, got %d", visitors, allowed, estimate)
+			}
+		})
+	}
+}
+
+// TestSketch_AddRepeat confirms repeat visits from the same visitor do not change the estimate, and visits without an
+// IP address or User-Agent are not added.
+func TestSketch_AddRepeat(t *testing.T) {
+	sketch := sketchOf(0, 1000)
+	expected := sketch.Estimate()
+
+	// Add the same visitors again. The port of the IP address is ignored.
+	for visitor := 0; visitor < 1000; visitor++ {
+		visit := sketchVisit(visitor)
+		ip := ipHost(*visit.IP)
+		visit.IP = &ip
+		sketch.Add(visit)
+	}
+	sketch.Add(models.Visit{})
+
+	if estimate := sketch.Estimate(); estimate != expected {
+		t.Fatalf("expected %d, got %d", expected, estimate)
+	}
+	if estimate := sketchOf(0, 0).Estimate(); estimate != 0 {
+		t.Fatalf("expected an empty sketch to estimate 0, got %d", estimate)
+	}
+}
+
+// TestSketch_Merge confirms merging Sketches gives the same Sketch as adding all of their visitors to one.
+func TestSketch_Merge(t *testing.T) {
+	testCases := []struct {
+		name       string
+		first      [2]int
+		second     [2]int
+		unionStart int
+		unionEnd   int
+	}{
+		{name: "disjoint", first: [2]int{0, 5000}, second: [2]int{5000, 10000}, unionStart: 0, unionEnd: 10000},
+		{name: "overlapping", first: [2]int{0, 6000}, second: [2]int{4000, 10000}, unionStart: 0, unionEnd: 10000},
+		{name: "subset", first: [2]int{0, 10000}, second: [2]int{2000, 3000}, unionStart: 0, unionEnd: 10000},
+		{name: "same", first: [2]int{0, 3000}, second: [2]int{0, 3000}, unionStart: 0, unionEnd: 3000},
+		{name: "empty", first: [2]int{0, 3000}, second: [2]int{0, 0}, unionStart: 0, unionEnd: 3000},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			union := sketchOf(testCase.unionStart, testCase.unionEnd)
+
+			// Merge in both orders.
+			for _, order := range [][2][2]int{{testCase.first, testCase.second}, {testCase.second, testCase.first}} {
+				merged := sketchOf(order[0][0], order[0][1])
+				merged.Merge(sketchOf(order[1][0], order[1][1]))
+				if !reflect.DeepEqual(merged.registers, union.registers) {
+					t.Fatalf("merged sketch is not the same as the union")
+				}
+
+				// Merging again does not change the Sketch.
+				merged.Merge(sketchOf(order[1][0], order[1][1]))
+				if estimate := merged.Estimate(); estimate != union.Estimate() {
+					t.Fatalf("expected %d after merging twice, got %d", union.Estimate(), estimate)
+				}
+			}
+		})
+	}
+}
+
+// TestSketch_bytes confirms a Sketch can be turned into bytes and back, and bytes of the wrong length are rejected.
+func TestSketch_bytes(t *testing.T) {
+	sketch := sketchOf(0, 1000)
+	data := sketchToBytes(sketch)
+
+	decoded, err := bytesToSketch(data)
+	if err != nil {
+		t.Fatalf("failed to decode sketch: %s", err.Error())
+	}
+	if !reflect.DeepEqual(decoded.registers, sketch.registers) {
+		t.Fatalf("decoded sketch is not the same as the original")
+	}
+
+	// The bytes are copied, so changing them does not change the Sketch.
+	data[0]++
+	if decoded.registers[0] == data[0] {
+		t.Fatalf("decoded sketch shares memory with the bytes")
+	}
+
+	for _, length := range []int{0, sketchRegisters - 1, sketchRegisters + 1} {
+		if _, err = bytesToSketch(make([]byte, length)); !errors.Is(err, ErrInvalidSketch) {
+			t.Fatalf("length %d: expected %v, got %v", length, ErrInvalidSketch, err)
+		}
+	}
+}
@@ -3,9 +3,14 @@ package storage
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
 	"encoding/gob"
 	"encoding/json"
 	"errors"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
 
 	"go.etcd.io/bbolt"
 
@@ -14,6 +19,16 @@ import (
 
 const (
 
+	// defaultAuthorizationPath is the bbolt file used for the AuthorizationStore when none is configured and the
+	// TerseStore is a bbolt file.
+	defaultAuthorizationPath = "authorization.bbolt"
+
+	// defaultPageLimit is the number of results to return in a page when no limit is given.
+	defaultPageLimit = 100
+
+	// maxPageLimit is the largest number of results that can be returned in a page.
+	maxPageLimit = 1000
+
 	// storageBbolt is the constant used when describing a storage backend as a bbolt file.
 	storageBbolt = "bbolt"
 
@@ -29,9 +44,27 @@ var (
 	// ErrShortenedNotFound indicates the given shortened URL was not found in the underlying storage.
 	ErrShortenedNotFound = errors.New("the shortened URL was not found")
 
+	// ErrShortenedExpired indicates the given shortened URL has expired by time or by visit count.
+	ErrShortenedExpired = errors.New("the shortened URL has expired")
+
 	// ErrShortenedExists indicates that an attempt was made to add a shortened URL that already existed.
 	ErrShortenedExists = errors.New("the shortened URL already exists")
 
+	// ErrUnauthorized indicates that the user is not authorized for a given shortened URL.
+	ErrUnauthorized = errors.New("the user is not authorized for the shortened URL")
+
+	// bboltAuthorizationBucket is the bbolt bucket to use for Authorization.
+	bboltAuthorizationBucket = []byte("terseAuthorization")
+
+	// bboltCreatedBucket is the bbolt bucket to use for the Terse creation time index.
+	bboltCreatedBucket = []byte("terseCreated")
+
+	// bboltDedupeBucket is the bbolt bucket to use for the Terse dedupe index.
+	bboltDedupeBucket = []byte("terseDedupe")
+
+	// bboltRollupsBucket is the bbolt bucket to use for the aggregate counts of pruned Visits.
+	bboltRollupsBucket = []byte("terseVisitsRollups")
+
 	// bboltTerseBucket is the bbolt bucket to use for Terse.
 	bboltTerseBucket = []byte("terse")
 
@@ -45,9 +78,90 @@ type configuration struct {
 	BboltPath string `json:"bboltPath"`
 }
 
+// AdminChecker is a function signature that determines if the given principal is an administrator. Administrators are
+// authorized for all shortened URLs.
+type AdminChecker func(principal *models.Principal) (admin bool)
+
 // CtxCreator is a function signature that creates a context and its cancel function.
 type CtxCreator func() (ctx context.Context, cancel context.CancelFunc)
 
+// VisitProcessor is a function signature that changes a visit before it is stored, like adding its location or removing
+// personal data. It must not modify the given visit's headers map.
+type VisitProcessor func(visit models.Visit) (processed models.Visit)
+
+// DedupeKey creates the key that identifies Terse data with the same destination. It is made of the redirect type and
+// the normalized original URL. The scheme and host are lowercased, default ports are removed, an empty path becomes
+// "/", and query parameters are sorted. Original URLs that cannot be parsed are only trimmed of whitespace.
+func DedupeKey(originalURL string, redirectType models.RedirectType) (key string) {
+	return string(redirectType) + " " + normalizeURL(originalURL)
+}
+
+// Deduplicable determines if the Terse data can be deduplicated. The dedupe key only identifies the destination, so
+// Terse data with any other option set, like an expiration, are never deduplicated.
+func Deduplicable(terse models.Terse) (ok bool) {
+	return terse.ExpiresAt == nil && terse.MaxVisits == 0 && terse.MediaPreview == nil && !terse.JavascriptTracking &&
+		len(terse.Targets) == 0 && len(terse.Variants) == 0 && !terse.StickyVariants && !terse.ForwardQuery &&
+		len(terse.QueryDefaults) == 0 && (terse.QueryConflict == "" || terse.QueryConflict == models.QueryConflictIncoming) &&
+		!terse.ForwardPath
+}
+
+// Duplicate determines if the two Terse data can be deduplicated and have the same dedupe key.
+func Duplicate(a, b models.Terse) (duplicate bool) {
+	return Deduplicable(a) && Deduplicable(b) && DedupeKey(a.OriginalURL, a.RedirectType) == DedupeKey(b.OriginalURL, b.RedirectType)
+}
+
+// NewAuthorizationStore creates a new AuthorizationStore from the given configJSON. The storeType return value is used
+// for logging. If no JSON was given and the terseStoreType is bbolt, a bbolt file is used, so the owners of shortened
+// URLs are not lost on a restart. Otherwise, an in memory implementation is used by default.
+func NewAuthorizationStore(configJSON json.RawMessage, terseStoreType string) (authStore AuthorizationStore, storeType string, err error) {
+
+	// Create the configuration.
+	config := &configuration{}
+
+	// If no JSON was given, match the persistence of the TerseStore.
+	if len(configJSON) == 0 {
+		config.Type = storageMemory
+		if terseStoreType == storageBbolt {
+			config.Type = storageBbolt
+			config.BboltPath = defaultAuthorizationPath
+		}
+	} else {
+
+		// Turn the configuration JSON into a Go structure.
+		if err = json.Unmarshal(configJSON, config); err != nil {
+			return nil, "", err
+		}
+	}
+
+	// Create the appropriate AuthorizationStore.
+	switch config.Type {
+
+	// Open a file as a bbolt database for the AuthorizationStore.
+	case storageBbolt:
+
+		// Open the bbolt database file.
+		var db *bbolt.DB
+		if db, err = openBbolt(config.BboltPath); err != nil {
+			return nil, "", err
+		}
+
+		// Create the bucket.
+		if err = createBucket(db, bboltAuthorizationBucket); err != nil {
+			return nil, "", err
+		}
+
+		// Assign the interface implementation.
+		authStore = NewBboltAuthorization(db, bboltAuthorizationBucket)
+
+	// Use and in memory implementation of the AuthorizationStore by default.
+	default:
+		config.Type = storageMemory
+		authStore = NewMemAuthorization()
+	}
+
+	return authStore, config.Type, nil
+}
+
 // NewSummaryStore creates a new SummaryStore from the given configJSON. The storeType return value is used for logging.
 func NewSummaryStore(configJSON json.RawMessage) (summaryStore SummaryStore, storeType string, err error) {
 
@@ -111,8 +225,13 @@ func NewTerseStore(configJSON json.RawMessage) (terseStore TerseStore, storeType
 			return nil, "", err
 		}
 
+		// Create the creation time and dedupe indexes.
+		if err = createIndexes(db, bboltCreatedBucket, bboltDedupeBucket, bboltTerseBucket); err != nil {
+			return nil, "", err
+		}
+
 		// Assign the interface implementation.
-		terseStore = NewBboltTerse(db, bboltTerseBucket)
+		terseStore = NewBboltTerse(db, bboltCreatedBucket, bboltDedupeBucket, bboltTerseBucket)
 
 	// Use and in memory implementation of the TerseStore by default.
 	default:
@@ -155,13 +274,21 @@ func NewVisitsStore(configJSON json.RawMessage) (visitsStore VisitsStore, storeT
 			return nil, "", err
 		}
 
-		// Create the bucket.
+		// Create the buckets.
 		if err = createBucket(db, bboltVisitsBucket); err != nil {
 			return nil, "", err
 		}
+		if err = createBucket(db, bboltRollupsBucket); err != nil {
+			return nil, "", err
+		}
+
+		// Move any Visits data from the old layout to nested buckets.
+		if err = migrateVisits(db, bboltVisitsBucket); err != nil {
+			return nil, "", err
+		}
 
 		// Assign the interface implementation.
-		visitsStore = NewBboltVisits(db, bboltVisitsBucket)
+		visitsStore = NewBboltVisits(db, bboltRollupsBucket, bboltVisitsBucket)
 
 	// Use and in memory implementation of the VisitsStore by default.
 	default:
@@ -182,7 +309,17 @@ func bytesToTerse(data []byte) (terse models.Terse, err error) {
 	return terse, nil
 }
 
-// bytesToVisits transforms bytes to Visits data.
+// bytesToVisit transforms bytes to a visit.
+func bytesToVisit(data []byte) (visit models.Visit, err error) {
+	buf := bytes.NewReader(data)
+	dec := gob.NewDecoder(buf)
+	if err = dec.Decode(&visit); err != nil {
+		return models.Visit{}, err
+	}
+	return visit, nil
+}
+
+// bytesToVisits transforms bytes to Visits data. It is only used for the old layout of BboltVisits.
 func bytesToVisits(data []byte) (visits []models.Visit, err error) {
 	buf := bytes.NewReader(data)
 	dec := gob.NewDecoder(buf)
@@ -192,6 +329,50 @@ func bytesToVisits(data []byte) (visits []models.Visit, err error) {
 	return visits, nil
 }
 
+// chooseVariant picks the variant to redirect to. If a variant with the assigned name exists, it is used. Otherwise, a
+// variant is picked at random by weight, where an empty weight is 1. If there are no variants, the variant is nil.
+func chooseVariant(variants []models.Variant, assigned string) (variant *models.Variant, err error) {
+
+	// Use the assigned variant, if it still exists.
+	if assigned != "" {
+		for i := range variants {
+			if variants[i].Name == assigned {
+				return &variants[i], nil
+			}
+		}
+	}
+
+	// Add up the weights of the variants.
+	weights := make([]*big.Int, len(variants))
+	total := big.NewInt(0)
+	for i, v := range variants {
+		weights[i] = new(big.Int).SetUint64(v.Weight)
+		if v.Weight == 0 {
+			weights[i].SetInt64(1)
+		}
+		total.Add(total, weights[i])
+	}
+	if total.Sign() == 0 {
+		return nil, nil
+	}
+
+	// Pick a random point in the total weight.
+	var point *big.Int
+	if point, err = rand.Int(rand.Reader, total); err != nil {
+		return nil, err
+	}
+
+	// Find the variant the point falls in.
+	for i, weight := range weights {
+		if point.Cmp(weight) < 0 {
+			return &variants[i], nil
+		}
+		point.Sub(point, weight)
+	}
+
+	return nil, nil // Should be unreachable.
+}
+
 // createBucket creates the given bucketName in the given bbolt database, if it doesn't already exist.
 func createBucket(db *bbolt.DB, bucketName []byte) (err error) {
 	if err = db.Update(func(tx *bbolt.Tx) error {
@@ -205,6 +386,38 @@ func createBucket(db *bbolt.DB, bucketName []byte) (err error) {
 	return nil
 }
 
+// normalizeURL normalizes the given URL so that equivalent URLs are the same string. See DedupeKey.
+func normalizeURL(rawURL string) (normalized string) {
+
+	// Parse the URL.
+	rawURL = strings.TrimSpace(rawURL)
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+
+	// Lowercase the case insensitive parts of the URL.
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+
+	// Remove the default port for the scheme.
+	if (u.Scheme == "http" && u.Port() == "80") || (u.Scheme == "https" && u.Port() == "443") {
+		u.Host = strings.TrimSuffix(u.Host, ":"+u.Port())
+	}
+
+	// Use the root path if there is no path.
+	if u.Path == "" {
+		u.Path = "/"
+	}
+
+	// Sort the query parameters.
+	if u.RawQuery != "" {
+		u.RawQuery = u.Query().Encode()
+	}
+
+	return u.String()
+}
+
 // openBbolt opens the file found at filePath as a bbolt database.
 func openBbolt(filePath string) (db *bbolt.DB, err error) {
 	return bbolt.Open(filePath, 0666, nil)
@@ -213,12 +426,51 @@ func openBbolt(filePath string) (db *bbolt.DB, err error) {
 // summarizeTerse creates a *models.TerseSummary from a models.Terse.
 func summarizeTerse(terse models.Terse) (summary *models.TerseSummary) {
 	return &models.TerseSummary{
+		CreatedAt:    terse.CreatedAt,
+		ExpiresAt:    terse.ExpiresAt,
+		MaxVisits:    terse.MaxVisits,
 		OriginalURL:  terse.OriginalURL,
 		RedirectType: terse.RedirectType,
 		ShortenedURL: terse.ShortenedURL,
 	}
 }
 
+// terseMatches determines if the original URL of the Terse data matches the search. See models.SearchQuery.
+func terseMatches(terse models.Terse, match models.SearchMatch, value string) bool {
+	switch match {
+	case models.SearchMatchExact:
+		return normalizeURL(terse.OriginalURL) == normalizeURL(value)
+	case models.SearchMatchHost:
+		u, err := url.Parse(terse.OriginalURL)
+		if err != nil {
+			return false
+		}
+		host := strings.ToLower(u.Hostname())
+		value = strings.ToLower(value)
+		return host == value || strings.HasSuffix(host, "."+value)
+	case models.SearchMatchPathPrefix:
+		u, err := url.Parse(terse.OriginalURL)
+		if err != nil {
+			return false
+		}
+		return strings.HasPrefix(u.Path, value)
+	default:
+		return strings.Contains(strings.ToLower(terse.OriginalURL), strings.ToLower(value))
+	}
+}
+
+// terseExpired determines if the Terse data has expired by time or by visit count at the given time.
+func terseExpired(terse models.Terse, visitCount uint64, now time.Time) (expired bool) {
+
+	// Check for expiration by time.
+	if terse.ExpiresAt != nil && !now.Before(time.Time(*terse.ExpiresAt)) {
+		return true
+	}
+
+	// Check for expiration by visit count.
+	return terse.MaxVisits != 0 && visitCount >= terse.MaxVisits
+}
+
 // terseToBytes transforms Terse data to bytes.
 func terseToBytes(terse models.Terse) (data []byte, err error) {
 	var buf bytes.Buffer
@@ -229,11 +481,11 @@ func terseToBytes(terse models.Terse) (data []byte, err error) {
 	return buf.Bytes(), nil
 }
 
-// visitsToBytes transforms Visits data to bytes.
-func visitsToBytes(visits []models.Visit) (data []byte, err error) {
+// visitToBytes transforms a visit to bytes.
+func visitToBytes(visit models.Visit) (data []byte, err error) {
 	var buf bytes.Buffer
 	enc := gob.NewEncoder(&buf)
-	if err = enc.Encode(visits); err != nil { // TODO Need to use *[]models.Visit?
+	if err = enc.Encode(&visit); err != nil {
 		return nil, err
 	}
 	return buf.Bytes(), nil
@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"math"
+	"testing"
+
+	"github.com/MicahParks/terseurl/models"
+)
+
+// TestChooseVariant confirms the assigned variant is used if it exists, and otherwise variants are picked in proportion
+// to their weights, where an empty weight is 1.
+func TestChooseVariant(t *testing.T) {
+	const draws = 20000
+
+	testCases := []struct {
+		name     string
+		weights  []uint64
+		assigned string
+		expected []float64
+	}{
+		{name: "single", weights: []uint64{5}, expected: []float64{1}},
+		{name: "equal", weights: []uint64{1, 1}, expected: []float64{0.5, 0.5}},
+		{name: "weighted", weights: []uint64{1, 3}, expected: []float64{0.25, 0.75}},
+		{name: "three", weights: []uint64{2, 3, 5}, expected: []float64{0.2, 0.3, 0.5}},
+		{name: "zero weights", weights: []uint64{0, 0}, expected: []float64{0.5, 0.5}},
+		{name: "zero weight is one", weights: []uint64{0, 3}, expected: []float64{0.25, 0.75}},
+		{name: "large weights", weights: []uint64{math.MaxUint64, math.MaxUint64}, expected: []float64{0.5, 0.5}},
+		{name: "assigned", weights: []uint64{1, 1000}, assigned: "0", expected: []float64{1, 0}},
+		{name: "assigned zero weight", weights: []uint64{1000, 0}, assigned: "1", expected: []float64{0, 1}},
+		{name: "assigned removed", weights: []uint64{1, 3}, assigned: "removed", expected: []float64{0.25, 0.75}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			// Create the variants. They are named after their index.
+			variants := make([]models.Variant, len(testCase.weights))
+			for i, weight := range testCase.weights {
+				variants[i] = models.Variant{
+					Name:        string(rune('0' + i)),
+					OriginalURL: "https://example.com",
+					Weight:      weight,
+				}
+			}
+
+			// Count how many times each variant is chosen.
+			counts := make([]int, len(variants))
+			for i := 0; i < draws; i++ {
+				variant, err := chooseVariant(variants, testCase.assigned)
+				if err != nil {
+					t.Fatalf("failed to choose variant: %s", err.Error())
+				}
+				if variant == nil {
+					t.Fatalf("no variant was chosen")
+				}
+				index := int(variant.Name[0] - '0')
+				if variant != &variants[index] {
+					t.Fatalf("the chosen variant is not from the given slice")
+				}
+				counts[index]++
+			}
+
+			// Confirm each variant was chosen in proportion to its weight. Five standard deviations of the binomial
+			// distribution are allowed.
+			for i, count := range counts {
+				p := testCase.expected[i]
+				allowed := 5 * math.Sqrt(p*(1-p)/draws)
+				if actual := float64(count) / draws; math.Abs(actual-p) > allowed {
+					t.Fatalf("variant %d: expected a proportion of %.3f ± %.3f, got %.3f", i, p, allowed, actual)
+				}
+			}
+		})
+	}
+}
+
+// TestChooseVariantNone confirms no variant is chosen when there are none, even if one was assigned.
+func TestChooseVariantNone(t *testing.T) {
+	for _, variants := range [][]models.Variant{nil, {}} {
+		for _, assigned := range []string{"", "removed"} {
+			variant, err := chooseVariant(variants, assigned)
+			if err != nil {
+				t.Fatalf("failed to choose variant: %s", err.Error())
+			}
+			if variant != nil {
+				t.Fatalf("expected no variant, got %q", variant.Name)
+			}
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package storage
+
+import (
+	"net"
+	"strings"
+	"time"
+
+	"github.com/MicahParks/terseurl/models"
+)
+
+// VisitsFilter describes which visits to read from or delete in a VisitsStore. The zero value matches all visits.
+type VisitsFilter struct {
+
+	// From only matches visits at or after this time. If zero, there is no lower bound.
+	From time.Time
+
+	// HeaderContains only matches visits with a value for the HeaderName header that contains this case insensitive
+	// substring. If empty, visits only need to have the HeaderName header.
+	HeaderContains string
+
+	// HeaderName is the header to match HeaderContains against. If empty, headers are not matched.
+	HeaderName string
+
+	// IPNets only matches visits from an IP address in one of these networks. If empty, IP addresses are not matched.
+	IPNets []*net.IPNet
+
+	// Limit is the maximum number of visits to read for each shortened URL. If zero, there is no limit.
+	Limit uint64
+
+	// To only matches visits before this time. If zero, there is no upper bound.
+	To time.Time
+}
+
+// Matches determines if the visit matches the filter.
+func (v VisitsFilter) Matches(visit models.Visit) bool {
+
+	// Check the time window.
+	if !v.InWindow(visitTime(visit)) {
+		return false
+	}
+
+	// Check the IP address.
+	if len(v.IPNets) != 0 && !v.ipMatches(visit.IP) {
+		return false
+	}
+
+	// Check the header.
+	if v.HeaderName != "" && !v.headerMatches(visit.Headers) {
+		return false
+	}
+
+	return true
+}
+
+// Empty determines if the filter matches all visits without a limit.
+func (v VisitsFilter) Empty() bool {
+	return v.From.IsZero() && v.HeaderName == "" && len(v.IPNets) == 0 && v.Limit == 0 && v.To.IsZero()
+}
+
+// InWindow determines if the given time is in the filter's time window.
+func (v VisitsFilter) InWindow(t time.Time) bool {
+	if !v.From.IsZero() && t.Before(v.From) {
+		return false
+	}
+	if !v.To.IsZero() && !t.Before(v.To) {
+		return false
+	}
+	return true
+}
+
+// headerMatches determines if the headers have the HeaderName header with a value that contains HeaderContains.
+func (v VisitsFilter) headerMatches(headers map[string][]string) bool {
+	contains := strings.ToLower(v.HeaderContains)
+	for key, values := range headers {
+		if !strings.EqualFold(key, v.HeaderName) {
+			continue
+		}
+		if contains == "" {
+			return true
+		}
+		for _, value := range values {
+			if strings.Contains(strings.ToLower(value), contains) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ipMatches determines if the IP address is in one of the IPNets.
+func (v VisitsFilter) ipMatches(address *string) bool {
+	if address == nil {
+		return false
+	}
+	ip := net.ParseIP(ipHost(*address))
+	if ip == nil {
+		return false
+	}
+	for _, ipNet := range v.IPNets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseIPNet parses an IP address or CIDR block into a network. An IP address is parsed as a network that only contains
+// itself.
+func ParseIPNet(s string) (ipNet *net.IPNet, err error) {
+
+	// Parse a CIDR block.
+	if strings.Contains(s, "/") {
+		_, ipNet, err = net.ParseCIDR(s)
+		return ipNet, err
+	}
+
+	// Parse an IP address.
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, &net.ParseError{Type: "IP address", Text: s}
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}, nil
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+}
+
+// ipHost removes the port from the given address, if present.
+func ipHost(address string) (host string) {
+	if h, _, err := net.SplitHostPort(address); err == nil {
+		return h
+	}
+	return address
+}
+
+// visitTime returns the time of the visit. It is the zero time if the visit does not have one.
+func visitTime(visit models.Visit) (t time.Time) {
+	if visit.Accessed != nil {
+		t = time.Time(*visit.Accessed)
+	}
+	return t
+}
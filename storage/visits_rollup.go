@@ -0,0 +1,190 @@
+package storage
+
+import (
+	"encoding/binary"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/MicahParks/terseurl/models"
+	"github.com/MicahParks/terseurl/useragent"
+)
+
+const (
+
+	// countBot is the aggregate count key for visits from automated clients.
+	countBot = "bot"
+
+	// countHuman is the aggregate count key for visits that are not from automated clients.
+	countHuman = "human"
+
+	// dayFormat is the format of the UTC date used to key daily visit counts.
+	dayFormat = "2006-01-02"
+
+	// maxReferrerHosts is the maximum number of referrer hosts kept in Visits Summary data.
+	maxReferrerHosts = 100
+
+	// otherReferrers is the referrer host that visits from referrer hosts past the maximum are counted under.
+	otherReferrers = "other"
+
+	// prefixBrowser is the prefix of the aggregate count keys for browser families.
+	prefixBrowser = "browser\x00"
+
+	// prefixCountry is the prefix of the aggregate count keys for countries.
+	prefixCountry = "country\x00"
+
+	// prefixDevice is the prefix of the aggregate count keys for device types.
+	prefixDevice = "device\x00"
+
+	// prefixOS is the prefix of the aggregate count keys for OS families.
+	prefixOS = "os\x00"
+
+	// prefixReferrer is the prefix of the aggregate count keys for referrer hosts.
+	prefixReferrer = "referrer\x00"
+
+	// prefixVariant is the prefix of the aggregate count keys for variants.
+	prefixVariant = "variant\x00"
+
+	// rollupVisitors is the bbolt rollups key of the unique visitor Sketch of pruned visits. It is not an aggregate
+	// count.
+	rollupVisitors = "visitors"
+)
+
+// addCounts adds the given aggregate counts to the Visits Summary data. The aggregate counts are keyed by the UTC date
+// for daily visit counts, or by a prefixed breakdown value. See visitCounts.
+func addCounts(summary *models.VisitsSummary, counts map[string]uint64) {
+
+	// Add the aggregate counts. Referrer hosts are added last.
+	referrers := make([]string, 0)
+	for key, count := range counts {
+		switch {
+		case key == countBot:
+			summary.BotCount += count
+		case key == countHuman:
+			summary.HumanCount += count
+		case strings.HasPrefix(key, prefixBrowser):
+			summary.Browsers = addCount(summary.Browsers, strings.TrimPrefix(key, prefixBrowser), count)
+		case strings.HasPrefix(key, prefixCountry):
+			summary.Countries = addCount(summary.Countries, strings.TrimPrefix(key, prefixCountry), count)
+		case strings.HasPrefix(key, prefixDevice):
+			summary.Devices = addCount(summary.Devices, strings.TrimPrefix(key, prefixDevice), count)
+		case strings.HasPrefix(key, prefixOS):
+			summary.OperatingSystems = addCount(summary.OperatingSystems, strings.TrimPrefix(key, prefixOS), count)
+		case strings.HasPrefix(key, prefixReferrer):
+			referrers = append(referrers, key)
+		case strings.HasPrefix(key, prefixVariant):
+			summary.Variants = addCount(summary.Variants, strings.TrimPrefix(key, prefixVariant), count)
+		default:
+			summary.DailyCounts = addCount(summary.DailyCounts, key, count)
+			summary.VisitCount += count
+		}
+	}
+
+	// Add the referrer hosts with the highest counts first. Hosts past the maximum are counted under other.
+	sort.Slice(referrers, func(i, j int) bool {
+		if counts[referrers[i]] != counts[referrers[j]] {
+			return counts[referrers[i]] > counts[referrers[j]]
+		}
+		return referrers[i] < referrers[j]
+	})
+	for _, key := range referrers {
+		host := strings.TrimPrefix(key, prefixReferrer)
+		if _, ok := summary.Referrers[host]; !ok && len(summary.Referrers) >= maxReferrerHosts {
+			host = otherReferrers
+		}
+		summary.Referrers = addCount(summary.Referrers, host, counts[key])
+	}
+}
+
+// addCount adds the count to the given key of the map. The map is created if it is nil.
+func addCount(m map[string]uint64, key string, count uint64) map[string]uint64 {
+	if m == nil {
+		m = make(map[string]uint64)
+	}
+	m[key] += count
+	return m
+}
+
+// bytesToCount transforms bytes to an aggregate count.
+func bytesToCount(data []byte) (count uint64) {
+	return binary.BigEndian.Uint64(data)
+}
+
+// countToBytes transforms an aggregate count to bytes.
+func countToBytes(count uint64) (data []byte) {
+	data = make([]byte, 8)
+	binary.BigEndian.PutUint64(data, count)
+	return data
+}
+
+// countVisit adds one to each of the aggregate counts the visit is part of.
+func countVisit(counts map[string]uint64, visit models.Visit) {
+	for _, key := range visitCounts(visit) {
+		counts[key]++
+	}
+}
+
+// dayTime returns the start of the UTC date a daily visit count is for. If ok is false, the aggregate count key is not
+// for a daily visit count.
+func dayTime(key string) (t time.Time, ok bool) {
+	t, err := time.Parse(dayFormat, key)
+	return t, err == nil
+}
+
+// referrerHost returns the lowercase host of the Referer header. It is empty if there is no valid Referer header.
+func referrerHost(headers http.Header) (host string) {
+	u, err := url.Parse(headers.Get("Referer"))
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// visitCounts returns the keys of the aggregate counts the visit is part of: its UTC date, bot or human, its browser
+// family, device type, OS family, and its country, referrer host, and variant, if any. Visits with the bot flag are bots,
+// even if their User-Agent is not recognized as one.
+func visitCounts(visit models.Visit) (keys []string) {
+	headers := http.Header(visit.Headers)
+
+	// Parse the User-Agent.
+	agent := useragent.Parse(headers.Get("User-Agent"))
+
+	// Create the keys.
+	keys = []string{
+		visitDay(visitTime(visit)),
+		countHuman,
+		prefixBrowser + agent.Browser,
+		prefixDevice + agent.Device,
+		prefixOS + agent.OS,
+	}
+	if agent.Bot || visit.Bot {
+		keys[1] = countBot
+	}
+	if visit.Country != "" {
+		keys = append(keys, prefixCountry+visit.Country)
+	}
+	if host := referrerHost(headers); host != "" {
+		keys = append(keys, prefixReferrer+host)
+	}
+	if visit.Variant != "" {
+		keys = append(keys, prefixVariant+visit.Variant)
+	}
+
+	return keys
+}
+
+// visitDay returns the UTC date a visit at the given time is counted on. Visits without a time are counted on the Unix
+// epoch, which matches where their bbolt keys sort.
+func visitDay(t time.Time) (day string) {
+	if t.IsZero() {
+		t = time.Unix(0, 0)
+	}
+	return t.UTC().Format(dayFormat)
+}
+
+// visitKeyTime returns the time of a visit from its bbolt key. Visits without a time have the Unix epoch. See visitKey.
+func visitKeyTime(key []byte) (t time.Time) {
+	return time.Unix(0, int64(binary.BigEndian.Uint64(key[:8])))
+}
@@ -0,0 +1,165 @@
+package useragent
+
+import (
+	"strings"
+)
+
+const (
+
+	// DeviceBot is the device type of automated clients like crawlers and link preview unfurlers.
+	DeviceBot = "bot"
+
+	// DeviceDesktop is the device type of desktop and laptop computers.
+	DeviceDesktop = "desktop"
+
+	// DeviceMobile is the device type of phones.
+	DeviceMobile = "mobile"
+
+	// DeviceOther is the device type when there is no User-Agent.
+	DeviceOther = "other"
+
+	// DeviceTablet is the device type of tablets.
+	DeviceTablet = "tablet"
+
+	// Other is the browser or OS family when it could not be determined.
+	Other = "Other"
+)
+
+var (
+
+	// botPatterns are case insensitive substrings of User-Agents that belong to automated clients. This includes the
+	// link preview unfurlers of chat and social media apps. The bot patterns are anchored to what follows the name,
+	// because device models like CUBOT phones and app names like Pinterest's in-app browser are not bots.
+	botPatterns = []string{
+		"bot/",
+		"bot;",
+		"+http",
+		"twitterbot",
+		"slackbot",
+		"telegrambot",
+		"pinterestbot",
+		"crawl",
+		"spider",
+		"slurp",
+		"facebookexternalhit",
+		"facebookcatalog",
+		"embedly",
+		"quora link preview",
+		"whatsapp",
+		"skypeuripreview",
+		"vkshare",
+		"bitlybot",
+		"outbrain",
+		"google-inspectiontool",
+		"headlesschrome",
+		"curl/",
+		"wget/",
+		"python-requests",
+		"python-urllib",
+		"go-http-client",
+		"okhttp",
+		"java/",
+		"libwww-perl",
+	}
+
+	// browserPatterns are the substrings that identify browser families, in the order they must be checked. Many
+	// browsers include the names of other browsers in their User-Agent.
+	browserPatterns = []family{
+		{name: "Edge", patterns: []string{"Edg/", "Edge/", "EdgA/", "EdgiOS/"}},
+		{name: "Opera", patterns: []string{"OPR/", "Opera"}},
+		{name: "Samsung Internet", patterns: []string{"SamsungBrowser/"}},
+		{name: "Firefox", patterns: []string{"Firefox/", "FxiOS/"}},
+		{name: "Chrome", patterns: []string{"Chrome/", "CriOS/", "Chromium/"}},
+		{name: "Safari", patterns: []string{"Safari/"}},
+		{name: "Internet Explorer", patterns: []string{"MSIE ", "Trident/"}},
+	}
+
+	// osPatterns are the substrings that identify OS families, in the order they must be checked. Android
+	// User-Agents include Linux.
+	osPatterns = []family{
+		{name: "iOS", patterns: []string{"iPhone", "iPad", "iPod"}},
+		{name: "Android", patterns: []string{"Android"}},
+		{name: "Windows", patterns: []string{"Windows"}},
+		{name: "macOS", patterns: []string{"Macintosh", "Mac OS X"}},
+		{name: "Chrome OS", patterns: []string{"CrOS"}},
+		{name: "Linux", patterns: []string{"Linux", "X11"}},
+	}
+)
+
+// Agent is what could be determined about a client from its User-Agent.
+type Agent struct {
+
+	// Bot indicates the client is automated, like a crawler or a link preview unfurler.
+	Bot bool
+
+	// Browser is the browser family, such as Chrome or Firefox.
+	Browser string
+
+	// Device is the device type. It is one of the Device constants.
+	Device string
+
+	// OS is the OS family, such as Windows or iOS.
+	OS string
+}
+
+// family is a browser or OS family and the User-Agent substrings that identify it.
+type family struct {
+	name     string
+	patterns []string
+}
+
+// Parse determines the browser family, OS family, device type, and if the client is a bot from the given User-Agent.
+// It uses substring matching instead of a full User-Agent database, so results are best effort.
+func Parse(userAgent string) (agent Agent) {
+
+	// Check for the empty case.
+	if userAgent == "" {
+		return Agent{
+			Browser: Other,
+			Device:  DeviceOther,
+			OS:      Other,
+		}
+	}
+
+	// Determine the browser and OS families.
+	agent.Browser = match(userAgent, browserPatterns)
+	agent.OS = match(userAgent, osPatterns)
+
+	// Determine the device type.
+	agent.Bot = IsBot(userAgent)
+	switch {
+	case agent.Bot:
+		agent.Device = DeviceBot
+	case strings.Contains(userAgent, "iPad") || strings.Contains(userAgent, "Tablet") || agent.OS == "Android" && !strings.Contains(userAgent, "Mobile"):
+		agent.Device = DeviceTablet
+	case strings.Contains(userAgent, "Mobi") || strings.Contains(userAgent, "iPhone") || strings.Contains(userAgent, "iPod"):
+		agent.Device = DeviceMobile
+	default:
+		agent.Device = DeviceDesktop
+	}
+
+	return agent
+}
+
+// IsBot determines if the given User-Agent belongs to an automated client, like a crawler or a link preview unfurler.
+func IsBot(userAgent string) (bot bool) {
+	lower := strings.ToLower(userAgent)
+	for _, pattern := range botPatterns {
+		if strings.Contains(lower, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// match returns the name of the first family with a pattern in the User-Agent.
+func match(userAgent string, families []family) (name string) {
+	for _, f := range families {
+		for _, pattern := range f.patterns {
+			if strings.Contains(userAgent, pattern) {
+				return f.name
+			}
+		}
+	}
+	return Other
+}
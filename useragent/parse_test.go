@@ -0,0 +1,108 @@
+package useragent
+
+import (
+	"testing"
+)
+
+// TestParse confirms the browser family, OS family, device type, and bot detection of common User-Agents.
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name      string
+		userAgent string
+		expected  Agent
+	}{
+		{
+			name:     "empty",
+			expected: Agent{Browser: Other, Device: DeviceOther, OS: Other},
+		},
+		{
+			name:      "Chrome on Windows",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			expected:  Agent{Browser: "Chrome", Device: DeviceDesktop, OS: "Windows"},
+		},
+		{
+			name:      "Edge on Windows",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.0.0",
+			expected:  Agent{Browser: "Edge", Device: DeviceDesktop, OS: "Windows"},
+		},
+		{
+			name:      "Firefox on Linux",
+			userAgent: "Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0",
+			expected:  Agent{Browser: "Firefox", Device: DeviceDesktop, OS: "Linux"},
+		},
+		{
+			name:      "Safari on iPhone",
+			userAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Mobile/15E148 Safari/604.1",
+			expected:  Agent{Browser: "Safari", Device: DeviceMobile, OS: "iOS"},
+		},
+		{
+			name:      "Safari on iPad",
+			userAgent: "Mozilla/5.0 (iPad; CPU OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Mobile/15E148 Safari/604.1",
+			expected:  Agent{Browser: "Safari", Device: DeviceTablet, OS: "iOS"},
+		},
+		{
+			name:      "Android tablet",
+			userAgent: "Mozilla/5.0 (Linux; Android 13; SM-X700) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			expected:  Agent{Browser: "Chrome", Device: DeviceTablet, OS: "Android"},
+		},
+		{
+			name:      "CUBOT phone",
+			userAgent: "Mozilla/5.0 (Linux; Android 10; CUBOT X30 Build/QP1A.190711.020) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36",
+			expected:  Agent{Browser: "Chrome", Device: DeviceMobile, OS: "Android"},
+		},
+		{
+			name:      "Pinterest in-app browser",
+			userAgent: "Mozilla/5.0 (Linux; Android 12; Pixel 6) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/120.0.0.0 Mobile Safari/537.36 [Pinterest/Android]",
+			expected:  Agent{Browser: "Chrome", Device: DeviceMobile, OS: "Android"},
+		},
+		{
+			name:      "Googlebot",
+			userAgent: "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			expected:  Agent{Bot: true, Browser: Other, Device: DeviceBot, OS: Other},
+		},
+		{
+			name:      "curl",
+			userAgent: "curl/8.4.0",
+			expected:  Agent{Bot: true, Browser: Other, Device: DeviceBot, OS: Other},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if agent := Parse(testCase.userAgent); agent != testCase.expected {
+				t.Fatalf("expected %+v, got %+v", testCase.expected, agent)
+			}
+		})
+	}
+}
+
+// TestIsBot confirms automated clients are detected without flagging browsers whose User-Agent contains a bot name.
+func TestIsBot(t *testing.T) {
+	testCases := []struct {
+		name      string
+		userAgent string
+		expected  bool
+	}{
+		{name: "Twitterbot", userAgent: "Twitterbot/1.0", expected: true},
+		{name: "Slackbot", userAgent: "Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)", expected: true},
+		{name: "Slackbot without a link", userAgent: "Slackbot 1.0", expected: true},
+		{name: "Discordbot", userAgent: "Mozilla/5.0 (compatible; Discordbot/2.0; +https://discordapp.com)", expected: true},
+		{name: "Telegram", userAgent: "TelegramBot (like TwitterBot)", expected: true},
+		{name: "Pinterestbot", userAgent: "Mozilla/5.0 (compatible; Pinterestbot/1.0; +http://www.pinterest.com/bot.html)", expected: true},
+		{name: "bingbot", userAgent: "Mozilla/5.0 (compatible; bingbot/2.0; +http://www.bing.com/bingbot.htm)", expected: true},
+		{name: "Facebook", userAgent: "facebookexternalhit/1.1 (+http://www.facebook.com/externalhit_uatext.php)", expected: true},
+		{name: "WhatsApp", userAgent: "WhatsApp/2.23.20.0 A", expected: true},
+		{name: "Go", userAgent: "Go-http-client/1.1", expected: true},
+		{name: "CUBOT phone", userAgent: "Mozilla/5.0 (Linux; Android 11; CUBOT_P50) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36"},
+		{name: "Pinterest in-app browser", userAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148 [Pinterest/iOS]"},
+		{name: "Firefox", userAgent: "Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if bot := IsBot(testCase.userAgent); bot != testCase.expected {
+				t.Fatalf("expected %t, got %t", testCase.expected, bot)
+			}
+		})
+	}
+}